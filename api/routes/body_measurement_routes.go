@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBodyMeasurementRoutes(db *sql.DB, router *gin.Engine) {
+	userStore := postgres.NewUserStore(db)
+	measurementService := service.NewBodyMeasurementService(postgres.NewBodyMeasurementStore(db))
+
+	measurements := router.Group("/api/body-measurements")
+	{
+		measurements.GET("", func(c *gin.Context) {
+			handlers.HandleGetBodyMeasurements(userStore, measurementService, c)
+		})
+		measurements.POST("", func(c *gin.Context) {
+			handlers.HandleCreateBodyMeasurement(measurementService, c)
+		})
+		measurements.GET("/trend", func(c *gin.Context) {
+			handlers.HandleGetBodyMeasurementTrend(userStore, measurementService, c)
+		})
+		measurements.PUT("/:id", func(c *gin.Context) {
+			handlers.HandleUpdateBodyMeasurement(measurementService, c)
+		})
+		measurements.DELETE("/:id", func(c *gin.Context) {
+			handlers.HandleDeleteBodyMeasurement(measurementService, c)
+		})
+	}
+}
@@ -4,33 +4,37 @@ import (
 	"database/sql"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupEquipmentRoutes(db *sql.DB, router *gin.Engine) {
+	equipmentService := service.NewEquipmentService(postgres.NewEquipmentStore(db), postgres.NewEquipmentTypeStore(db))
+
 	gymEquipment := router.Group("/api/gyms/:gymId/equipment")
 	{
 		gymEquipment.GET("", func(c *gin.Context) {
-			handlers.HandleGetAllGymEquipments(db, c)
+			handlers.HandleGetAllGymEquipments(equipmentService, c)
 		})
 
 		gymEquipment.POST("", func(c *gin.Context) {
-			handlers.HandleAddNewGymEquipment(db, c)
+			handlers.HandleAddNewGymEquipment(equipmentService, c)
 		})
 	}
 
 	equipmentRoutes := router.Group("/api/gym-equipment")
 	{
-		equipmentRoutes.POST("", func(c *gin.Context) {
-			handlers.HandleGetGymEquipment(db, c)
+		equipmentRoutes.GET("/:id", func(c *gin.Context) {
+			handlers.HandleGetGymEquipment(equipmentService, c)
 		})
 
 		equipmentRoutes.PUT("/:id", func(c *gin.Context) {
-			handlers.HandleUpdateGymEquipment(db, c)
+			handlers.HandleUpdateGymEquipment(equipmentService, c)
 		})
 
 		equipmentRoutes.DELETE("/:id", func(c *gin.Context) {
-			handlers.HandleDeleteGymEquipment(db, c)
+			handlers.HandleDeleteGymEquipment(equipmentService, c)
 		})
 	}
 }
@@ -4,26 +4,30 @@ import (
 	"database/sql"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupEquipmentTypeRoutes(db *sql.DB, router *gin.Engine) {
+	equipmentTypeService := service.NewEquipmentTypeService(postgres.NewEquipmentTypeStore(db))
+
 	equipmentTypes := router.Group("/api/equipment-types")
 	{
 		equipmentTypes.GET("", func(c *gin.Context) {
-			handlers.HandleGetAllEquipmentTypes(db, c)
+			handlers.HandleGetAllEquipmentTypes(equipmentTypeService, c)
 		})
 		equipmentTypes.POST("", func(c *gin.Context) {
-			handlers.HandleCreateEquipmentType(db, c)
+			handlers.HandleCreateEquipmentType(equipmentTypeService, c)
 		})
 		equipmentTypes.GET("/:id", func(c *gin.Context) {
-			handlers.HandleGetEquipmentType(db, c)
+			handlers.HandleGetEquipmentType(equipmentTypeService, c)
 		})
 		equipmentTypes.PUT("/:id", func(c *gin.Context) {
-			handlers.HandleUpdateEquipmentType(db, c)
+			handlers.HandleUpdateEquipmentType(equipmentTypeService, c)
 		})
 		equipmentTypes.DELETE("/:id", func(c *gin.Context) {
-			handlers.HandleDeleteEquipmentType(db, c)
+			handlers.HandleDeleteEquipmentType(equipmentTypeService, c)
 		})
 	}
 }
@@ -4,26 +4,30 @@ import (
 	"database/sql"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupExerciseRoutes(db *sql.DB, router *gin.Engine) {
+	exerciseService := service.NewExerciseService(postgres.NewExerciseStore(db))
+
 	exercises := router.Group("/api/exercises")
 	{
 		exercises.GET("", func(c *gin.Context) {
-			handlers.HandleGetAllExercises(db, c)
+			handlers.HandleGetAllExercises(exerciseService, c)
 		})
 
 		exercises.POST("", func(c *gin.Context) {
-			handlers.HandleCreateExercise(db, c)
+			handlers.HandleCreateExercise(exerciseService, c)
 		})
 
 		exercises.PUT("/:id", func(c *gin.Context) {
-			handlers.HandleUpdateExercise(db, c)
+			handlers.HandleUpdateExercise(exerciseService, c)
 		})
 
 		exercises.DELETE("/:id", func(c *gin.Context) {
-			handlers.HandleDeleteExercise(db, c)
+			handlers.HandleDeleteExercise(exerciseService, c)
 		})
 	}
 }
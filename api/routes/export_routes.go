@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupExportRoutes(db *sql.DB, router *gin.Engine) {
+	exports := router.Group("/api/export")
+	{
+		exports.GET("", func(c *gin.Context) {
+			handlers.HandleExportUserData(db, c)
+		})
+	}
+}
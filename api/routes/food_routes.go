@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupFoodRoutes(db *sql.DB, router *gin.Engine) {
+	foodService := service.NewFoodService(postgres.NewFoodStore(db), service.NewPantryService(postgres.NewPantryStore(db)))
+
+	foods := router.Group("/api/foods")
+	{
+		foods.GET("", func(c *gin.Context) {
+			handlers.HandleSearchFoods(foodService, c)
+		})
+		foods.GET("/barcode/:code", func(c *gin.Context) {
+			handlers.HandleGetFoodByBarcode(foodService, c)
+		})
+		foods.GET("/:id", func(c *gin.Context) {
+			handlers.HandleGetFood(foodService, c)
+		})
+	}
+}
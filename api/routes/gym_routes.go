@@ -4,29 +4,33 @@ import (
 	"database/sql"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupGymRoutes(db *sql.DB, router *gin.Engine) {
+	gymService := service.NewGymService(postgres.NewGymStore(db))
+
 	gym := router.Group("/api/gyms")
 	{
 		gym.GET("", func(c *gin.Context) {
-			handlers.HandleGetGyms(db, c)
+			handlers.HandleGetGyms(gymService, c)
 		})
 		gym.POST("", func(c *gin.Context) {
-			handlers.HandleCreateGym(db, c)
+			handlers.HandleCreateGym(gymService, c)
 		})
 		gym.GET("/id/:id", func(c *gin.Context) {
-			handlers.HandleGetGymByID(db, c)
+			handlers.HandleGetGymByID(gymService, c)
 		})
 		gym.GET("/user/:user_id", func(c *gin.Context) {
-			handlers.HandleGetGymsByUserID(db, c)
+			handlers.HandleGetGymsByUserID(gymService, c)
 		})
 		gym.PUT("/:id", func(c *gin.Context) {
-			handlers.HandleUpdateGym(db, c)
+			handlers.HandleUpdateGym(gymService, c)
 		})
 		gym.DELETE("/:id", func(c *gin.Context) {
-			handlers.HandleDeleteGym(db, c)
+			handlers.HandleDeleteGym(gymService, c)
 		})
 	}
 }
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupImportRoutes(db *sql.DB, router *gin.Engine) {
+	userStore := postgres.NewUserStore(db)
+
+	imports := router.Group("/api/import")
+	{
+		imports.POST("", func(c *gin.Context) {
+			handlers.HandleImportWorkouts(db, userStore, c)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMealPlanRoutes(db *sql.DB, router *gin.Engine) {
+	userStore := postgres.NewUserStore(db)
+	mealPlanService := service.NewMealPlanService(postgres.NewMealPlanStore(db), postgres.NewRecipeStore(db))
+
+	mealPlan := router.Group("/api/meal-plan")
+	{
+		mealPlan.GET("", func(c *gin.Context) {
+			handlers.HandleGetMealPlan(userStore, mealPlanService, c)
+		})
+		mealPlan.POST("", func(c *gin.Context) {
+			handlers.HandleCreateMealPlanEntry(mealPlanService, c)
+		})
+		mealPlan.GET("/requirements", func(c *gin.Context) {
+			handlers.HandleGetMealPlanRequirements(userStore, mealPlanService, c)
+		})
+		mealPlan.GET("/:id", func(c *gin.Context) {
+			handlers.HandleGetMealPlanEntry(mealPlanService, c)
+		})
+		mealPlan.PUT("/:id", func(c *gin.Context) {
+			handlers.HandleUpdateMealPlanEntry(mealPlanService, c)
+		})
+		mealPlan.DELETE("/:id", func(c *gin.Context) {
+			handlers.HandleDeleteMealPlanEntry(mealPlanService, c)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMealRoutes(db *sql.DB, router *gin.Engine) {
+	userStore := postgres.NewUserStore(db)
+	mealService := service.NewMealService(postgres.NewMealStore(db))
+
+	meals := router.Group("/api/meals")
+	{
+		meals.GET("", func(c *gin.Context) {
+			handlers.HandleGetMeals(userStore, mealService, c)
+		})
+		meals.POST("", func(c *gin.Context) {
+			handlers.HandleCreateMeal(mealService, c)
+		})
+		meals.GET("/:id", func(c *gin.Context) {
+			handlers.HandleGetMeal(mealService, c)
+		})
+		meals.PUT("/:id", func(c *gin.Context) {
+			handlers.HandleUpdateMeal(mealService, c)
+		})
+		meals.DELETE("/:id", func(c *gin.Context) {
+			handlers.HandleDeleteMeal(mealService, c)
+		})
+	}
+}
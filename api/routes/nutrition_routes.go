@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupNutritionRoutes(db *sql.DB, router *gin.Engine) {
+	userStore := postgres.NewUserStore(db)
+	nutritionService := service.NewNutritionService(
+		userStore,
+		postgres.NewProfileStore(db),
+		postgres.NewBodyMeasurementStore(db),
+		postgres.NewNutritionStore(db),
+	)
+
+	nutrition := router.Group("/api/nutrition")
+	{
+		nutrition.GET("/summary", func(c *gin.Context) {
+			handlers.HandleGetNutritionSummary(userStore, nutritionService, c)
+		})
+		nutrition.GET("/today", func(c *gin.Context) {
+			handlers.HandleGetNutritionToday(userStore, nutritionService, c)
+		})
+		nutrition.GET("/targets", func(c *gin.Context) {
+			handlers.HandleGetNutritionTargets(nutritionService, c)
+		})
+		nutrition.PUT("/targets", func(c *gin.Context) {
+			handlers.HandleUpdateNutritionTargets(nutritionService, c)
+		})
+		nutrition.GET("/calculator", func(c *gin.Context) {
+			handlers.HandleCalculateNutritionTargets(nutritionService, c)
+		})
+	}
+}
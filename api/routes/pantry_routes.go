@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPantryRoutes(db *sql.DB, router *gin.Engine) {
+	userStore := postgres.NewUserStore(db)
+	pantryService := service.NewPantryService(postgres.NewPantryStore(db))
+	foodService := service.NewFoodService(postgres.NewFoodStore(db), pantryService)
+
+	pantry := router.Group("/api/pantry")
+	{
+		pantry.GET("", func(c *gin.Context) {
+			handlers.HandleGetPantryItems(pantryService, c)
+		})
+		pantry.POST("", func(c *gin.Context) {
+			handlers.HandleCreatePantryItem(pantryService, c)
+		})
+		pantry.POST("/from-food", func(c *gin.Context) {
+			handlers.HandleCreatePantryItemFromFood(foodService, c)
+		})
+		pantry.GET("/expiring", func(c *gin.Context) {
+			handlers.HandleGetExpiringPantryItems(userStore, pantryService, c)
+		})
+		pantry.GET("/forecast", func(c *gin.Context) {
+			handlers.HandleGetPantryForecast(userStore, pantryService, c)
+		})
+		pantry.POST("/forecast/thresholds", func(c *gin.Context) {
+			handlers.HandleTunePantryThresholds(userStore, pantryService, c)
+		})
+		pantry.GET("/:id", func(c *gin.Context) {
+			handlers.HandleGetPantryItem(pantryService, c)
+		})
+		pantry.PUT("/:id", func(c *gin.Context) {
+			handlers.HandleUpdatePantryItem(pantryService, c)
+		})
+		pantry.POST("/:id/restock", func(c *gin.Context) {
+			handlers.HandleRestockPantryItem(pantryService, c)
+		})
+		pantry.DELETE("/:id", func(c *gin.Context) {
+			handlers.HandleDeletePantryItem(pantryService, c)
+		})
+		pantry.GET("/:id/batches", func(c *gin.Context) {
+			handlers.HandleGetPantryBatches(pantryService, c)
+		})
+		pantry.DELETE("/:id/batches/:batch_id", func(c *gin.Context) {
+			handlers.HandleDiscardPantryBatch(pantryService, c)
+		})
+	}
+}
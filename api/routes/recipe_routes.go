@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRecipeRoutes(db *sql.DB, router *gin.Engine) {
+	recipeService := service.NewRecipeService(postgres.NewRecipeStore(db))
+
+	recipes := router.Group("/api/recipes")
+	{
+		recipes.GET("", func(c *gin.Context) {
+			handlers.HandleGetRecipes(recipeService, c)
+		})
+		recipes.POST("", func(c *gin.Context) {
+			handlers.HandleCreateRecipe(recipeService, c)
+		})
+		recipes.GET("/:id", func(c *gin.Context) {
+			handlers.HandleGetRecipe(recipeService, c)
+		})
+		recipes.PUT("/:id", func(c *gin.Context) {
+			handlers.HandleUpdateRecipe(recipeService, c)
+		})
+		recipes.DELETE("/:id", func(c *gin.Context) {
+			handlers.HandleDeleteRecipe(recipeService, c)
+		})
+		recipes.POST("/:id/log", func(c *gin.Context) {
+			handlers.HandleLogRecipe(recipeService, c)
+		})
+	}
+}
@@ -1,25 +1,49 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/Ross1116/gym-tracker-backend/docs"
+	"github.com/Ross1116/gym-tracker-backend/internal/config"
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/logging"
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/requestid"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func SetupRoutes(db *sql.DB) {
-	docs.SwaggerInfo.Host = "localhost:9000"
+// SetupRoutes builds the API's handler.
+func SetupRoutes(db *sql.DB, cfg config.Config) *gin.Engine {
+	docs.SwaggerInfo.Host = cfg.HTTP.PublicHost
 
-	router := gin.Default()
+	if cfg.Log.Level != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(requestid.Middleware())
+	router.Use(otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithGinFilter(traced)))
+	router.Use(logging.Middleware(slog.Default()))
+	router.Use(metrics.Middleware())
+	router.Use(gin.CustomRecoveryWithWriter(io.Discard, handlers.HandlePanic))
+	router.Use(requestTimeout(time.Duration(cfg.HTTP.RequestTimeout)))
+	router.NoRoute(handlers.HandleNoRoute)
 
 	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000"}, // Your Next.js app URL
+		AllowOrigins:     cfg.HTTP.CORSOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", requestid.Header, "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Content-Length", requestid.Header},
 		AllowCredentials: true,
 	}))
 
@@ -29,8 +53,59 @@ func SetupRoutes(db *sql.DB) {
 	SetupEquipmentTypeRoutes(db, router)
 	SetupExerciseRoutes(db, router)
 	SetupWorkoutRoutes(db, router)
+	SetupImportRoutes(db, router)
+	SetupExportRoutes(db, router)
+	SetupBodyMeasurementRoutes(db, router)
+	SetupPantryRoutes(db, router)
+	SetupMealRoutes(db, router)
+	SetupShoppingListRoutes(db, router)
+	SetupNutritionRoutes(db, router)
+	SetupRecipeRoutes(db, router)
+	SetupMealPlanRoutes(db, router)
+	SetupFoodRoutes(db, router)
+
+	router.GET("/metrics", metrics.Handler())
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	router.Run(":9000")
+	return router
+}
+
+// traced leaves scrapes and the API docs out of traces.
+func traced(c *gin.Context) bool {
+	path := c.FullPath()
+	return path != "/metrics" && !strings.HasPrefix(path, "/swagger/")
+}
+
+// streamingRoutes move a whole account's data in one request, which can
+// take longer than any fixed limit.
+var streamingRoutes = map[string]bool{
+	"/api/export": true,
+	"/api/import": true,
+}
+
+// requestTimeout gives each request's context a deadline, so that the
+// database calls made with it are cancelled once it passes or the client
+// goes away. Streaming routes get no deadline, and the server's read and
+// write timeouts are lifted for their connection so that large exports are
+// not cut off part way through.
+func requestTimeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if streamingRoutes[c.FullPath()] {
+			rc := http.NewResponseController(c.Writer)
+			if err := rc.SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				slog.WarnContext(c.Request.Context(), "could not lift read deadline", "error", err)
+			}
+			if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				slog.WarnContext(c.Request.Context(), "could not lift write deadline", "error", err)
+			}
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
 }
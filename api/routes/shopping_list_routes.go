@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupShoppingListRoutes(db *sql.DB, router *gin.Engine) {
+	userStore := postgres.NewUserStore(db)
+	shoppingListService := service.NewShoppingListService(postgres.NewPantryStore(db), postgres.NewShoppingListStore(db))
+
+	shoppingList := router.Group("/api/shopping-list")
+	{
+		shoppingList.GET("", func(c *gin.Context) {
+			handlers.HandleGetShoppingList(userStore, shoppingListService, c)
+		})
+		shoppingList.POST("/items", func(c *gin.Context) {
+			handlers.HandleAddShoppingListItem(shoppingListService, c)
+		})
+		shoppingList.PUT("/items/:id/check", func(c *gin.Context) {
+			handlers.HandleCheckShoppingListItem(shoppingListService, c)
+		})
+		shoppingList.DELETE("/items/:id", func(c *gin.Context) {
+			handlers.HandleDeleteShoppingListItem(shoppingListService, c)
+		})
+		shoppingList.PUT("/pantry/:id/check", func(c *gin.Context) {
+			handlers.HandleCheckShoppingListPantryItem(shoppingListService, c)
+		})
+		shoppingList.POST("/purchase", func(c *gin.Context) {
+			handlers.HandlePurchaseShoppingList(userStore, shoppingListService, c)
+		})
+	}
+}
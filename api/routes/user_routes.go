@@ -4,17 +4,47 @@ import (
 	"database/sql"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupUserRoutes(db *sql.DB, router *gin.Engine) {
+	userStore := postgres.NewUserStore(db)
+	userService := service.NewUserService(userStore)
+	profileService := service.NewProfileService(userStore, postgres.NewProfileStore(db))
+
 	users := router.Group("/api/users")
 	{
 		users.GET("", func(c *gin.Context) {
-			handlers.HandleGetUsers(db, c)
+			handlers.HandleGetUsers(userService, c)
 		})
 		users.POST("", func(c *gin.Context) {
-			handlers.HandleCreateUser(db, c)
+			handlers.HandleCreateUser(userService, c)
+		})
+		users.GET("/:id", func(c *gin.Context) {
+			handlers.HandleGetUser(userService, c)
+		})
+		users.PUT("/:id/email", func(c *gin.Context) {
+			handlers.HandleUpdateUserEmail(userService, c)
+		})
+		users.PUT("/:id/password", func(c *gin.Context) {
+			handlers.HandleUpdateUserPassword(userService, c)
+		})
+		users.DELETE("/:id", func(c *gin.Context) {
+			handlers.HandleDeleteUser(userService, c)
+		})
+		users.GET("/:id/profile", func(c *gin.Context) {
+			handlers.HandleGetUserProfile(profileService, c)
+		})
+		users.PUT("/:id/profile", func(c *gin.Context) {
+			handlers.HandleUpdateUserProfile(profileService, c)
+		})
+		users.GET("/:id/deletion", func(c *gin.Context) {
+			handlers.HandleGetAccountDeletion(userService, c)
+		})
+		users.DELETE("/:id/deletion", func(c *gin.Context) {
+			handlers.HandleCancelAccountDeletion(userService, c)
 		})
 	}
 }
@@ -4,33 +4,42 @@ import (
 	"database/sql"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupWorkoutRoutes(db *sql.DB, router *gin.Engine) {
+	userStore := postgres.NewUserStore(db)
+	workoutService := service.NewWorkoutService(postgres.NewWorkoutStore(db))
+
 	workouts := router.Group("/api/workouts")
 	{
 		workouts.GET("", func(c *gin.Context) {
-			handlers.HandleGetUserWorkouts(db, c)
+			handlers.HandleGetUserWorkouts(workoutService, c)
 		})
 
 		workouts.POST("", func(c *gin.Context) {
-			handlers.HandleCreateWorkoutWithExercises(db, c)
+			handlers.HandleCreateWorkoutWithExercises(workoutService, c)
 		})
 
 		workouts.POST("/:sessionId/exercises", func(c *gin.Context) {
-			handlers.HandleAddWorkoutExercise(db, c)
+			handlers.HandleAddWorkoutExercise(workoutService, c)
+		})
+
+		workouts.GET("/daily", func(c *gin.Context) {
+			handlers.HandleGetDailyWorkoutSummary(userStore, workoutService, c)
 		})
 
 		workouts.GET("history/:exercise_id/:equipment_id", func(c *gin.Context) {
-			handlers.HandleGetExerciseHistory(db, c)
+			handlers.HandleGetExerciseHistory(workoutService, c)
 		})
 		workouts.GET("latest/:exercise_id/:equipment_id", func(c *gin.Context) {
-			handlers.HandleGetLatestExercise(db, c)
+			handlers.HandleGetLatestExercise(workoutService, c)
 		})
 
 		workouts.GET("/:id", func(c *gin.Context) {
-			handlers.HandleGetWorkoutWithExercises(db, c)
+			handlers.HandleGetWorkoutWithExercises(workoutService, c)
 		})
 	}
 }
@@ -0,0 +1,20 @@
+// Package db embeds the database schema and its migrations into the binary.
+package db
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrations holds the versioned migrations, named
+// NNNN_description.up.sql and NNNN_description.down.sql.
+var Migrations, _ = fs.Sub(migrations, "migrations")
+
+// Schema is the complete schema, which must match the state reached by
+// applying every migration.
+//
+//go:embed schema.sql
+var Schema string
@@ -1,9 +1,11 @@
-DROP VIEW shopping_list;
-DROP TABLE meal_ingredients;
-DROP TABLE meals;
-DROP TABLE pantry_items;
-DROP TABLE workout_sessions;
-DROP TABLE equipment;
-DROP TABLE exercises;
-DROP TABLE gyms;
-DROP TABLE users;
\ No newline at end of file
+DROP VIEW IF EXISTS shopping_list;
+DROP TABLE IF EXISTS meal_ingredients;
+DROP TABLE IF EXISTS meals;
+DROP TABLE IF EXISTS pantry_items;
+DROP TABLE IF EXISTS workout_exercises;
+DROP TABLE IF EXISTS workout_sessions;
+DROP TABLE IF EXISTS gym_equipment;
+DROP TABLE IF EXISTS equipment_types;
+DROP TABLE IF EXISTS exercises;
+DROP TABLE IF EXISTS gyms;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS user_profiles (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    bodyweight_kg DECIMAL NULL,
+    height_cm DECIMAL NULL,
+    birth_year INTEGER NULL,
+    sex VARCHAR(16) NULL CHECK (sex IN ('male', 'female', 'other')),
+    preferred_units VARCHAR(16) NOT NULL DEFAULT 'metric' CHECK (preferred_units IN ('metric', 'imperial')),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS body_measurements (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    measured_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    bodyweight_kg DECIMAL NULL,
+    body_fat_pct DECIMAL NULL,
+    waist_cm DECIMAL NULL,
+    arms_cm DECIMAL NULL,
+    chest_cm DECIMAL NULL,
+    notes VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_body_measurements_user_measured_at
+    ON body_measurements (user_id, measured_at);
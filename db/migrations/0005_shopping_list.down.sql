@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS shopping_list_checks;
+DROP TABLE IF EXISTS shopping_list_items;
+
+DROP VIEW IF EXISTS shopping_list;
+CREATE VIEW shopping_list AS
+SELECT 
+    u.id AS user_id,
+    pi.name,
+    (pi.threshold - pi.quantity) AS quantity_needed,
+    pi.unit
+FROM pantry_items pi
+JOIN users u ON pi.user_id = u.id
+WHERE pi.quantity < pi.threshold;
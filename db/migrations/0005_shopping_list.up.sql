@@ -0,0 +1,30 @@
+-- Expose the pantry item behind each row so it can be checked off and restocked.
+CREATE OR REPLACE VIEW shopping_list AS
+SELECT 
+    u.id AS user_id,
+    pi.name,
+    (pi.threshold - pi.quantity) AS quantity_needed,
+    pi.unit,
+    pi.id AS pantry_item_id
+FROM pantry_items pi
+JOIN users u ON pi.user_id = u.id
+WHERE pi.quantity < pi.threshold;
+
+CREATE TABLE IF NOT EXISTS shopping_list_items (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    pantry_item_id INTEGER NULL REFERENCES pantry_items(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    quantity DECIMAL NULL,
+    unit VARCHAR(50) NULL,
+    checked BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_shopping_list_items_user_id
+    ON shopping_list_items (user_id);
+
+CREATE TABLE IF NOT EXISTS shopping_list_checks (
+    pantry_item_id INTEGER PRIMARY KEY REFERENCES pantry_items(id) ON DELETE CASCADE,
+    checked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
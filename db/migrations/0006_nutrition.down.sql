@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS nutrition_targets;
+
+ALTER TABLE pantry_items
+    DROP COLUMN IF EXISTS fiber_per_unit,
+    DROP COLUMN IF EXISTS fat_per_unit,
+    DROP COLUMN IF EXISTS carbs_per_unit;
@@ -0,0 +1,14 @@
+ALTER TABLE pantry_items
+    ADD COLUMN IF NOT EXISTS carbs_per_unit DECIMAL NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fat_per_unit DECIMAL NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fiber_per_unit DECIMAL NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS nutrition_targets (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    calories DECIMAL NULL,
+    protein DECIMAL NULL,
+    carbs DECIMAL NULL,
+    fat DECIMAL NULL,
+    fiber DECIMAL NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1 @@
+ALTER TABLE workout_sessions DROP COLUMN IF EXISTS cardio_minutes;
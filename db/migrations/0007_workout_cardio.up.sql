@@ -0,0 +1,2 @@
+ALTER TABLE workout_sessions
+    ADD COLUMN IF NOT EXISTS cardio_minutes INTEGER NULL CHECK (cardio_minutes >= 0);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS recipes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    servings DECIMAL NOT NULL CHECK (servings > 0),
+    notes TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_ingredients (
+    recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+    pantry_item_id INTEGER REFERENCES pantry_items(id),
+    quantity DECIMAL NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (recipe_id, pantry_item_id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS meal_plan_ingredients;
+DROP TABLE IF EXISTS meal_plan_entries;
@@ -0,0 +1,21 @@
+-- A planned entry is either servings of a recipe or a list of ingredients.
+CREATE TABLE IF NOT EXISTS meal_plan_entries (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    planned_date DATE NOT NULL,
+    recipe_id INTEGER NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    servings DECIMAL NULL CHECK (servings > 0),
+    notes TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((recipe_id IS NULL) = (servings IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_meal_plan_entries_user_date
+    ON meal_plan_entries (user_id, planned_date);
+
+CREATE TABLE IF NOT EXISTS meal_plan_ingredients (
+    entry_id INTEGER REFERENCES meal_plan_entries(id) ON DELETE CASCADE,
+    pantry_item_id INTEGER REFERENCES pantry_items(id),
+    quantity DECIMAL NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (entry_id, pantry_item_id)
+);
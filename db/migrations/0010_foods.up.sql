@@ -0,0 +1,25 @@
+-- Shared food catalogue loaded from Open Food Facts, USDA or similar dumps.
+-- Nutrients are per 100 g (or 100 ml when basis_unit is ml).
+CREATE TABLE IF NOT EXISTS foods (
+    id SERIAL PRIMARY KEY,
+    source VARCHAR(50) NOT NULL,
+    source_id VARCHAR(100) NOT NULL,
+    barcode VARCHAR(14) NULL,
+    name VARCHAR(255) NOT NULL,
+    brand VARCHAR(255) NULL,
+    basis_unit VARCHAR(2) NOT NULL DEFAULT 'g' CHECK (basis_unit IN ('g', 'ml')),
+    serving_size DECIMAL NULL CHECK (serving_size > 0),
+    calories_per_100 DECIMAL NULL,
+    protein_per_100 DECIMAL NULL,
+    carbs_per_100 DECIMAL NULL,
+    fat_per_100 DECIMAL NULL,
+    fiber_per_100 DECIMAL NULL,
+    search_vector TSVECTOR GENERATED ALWAYS AS (
+        to_tsvector('simple', name || ' ' || COALESCE(brand, ''))
+    ) STORED,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(source, source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_foods_barcode ON foods (barcode);
+CREATE INDEX IF NOT EXISTS idx_foods_search ON foods USING GIN (search_vector);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS meal_ingredient_batches;
+DROP TABLE IF EXISTS pantry_batches;
+ALTER TABLE pantry_items DROP COLUMN IF EXISTS grams_per_item;
@@ -0,0 +1,45 @@
+-- Lets items counted by the piece also be measured by weight.
+ALTER TABLE pantry_items
+    ADD COLUMN IF NOT EXISTS grams_per_item DECIMAL NULL CHECK (grams_per_item > 0);
+
+-- Dated stock. Quantity not covered by a batch has no expiry date.
+CREATE TABLE IF NOT EXISTS pantry_batches (
+    id SERIAL PRIMARY KEY,
+    pantry_item_id INTEGER REFERENCES pantry_items(id) ON DELETE CASCADE,
+    quantity DECIMAL NOT NULL CHECK (quantity > 0),
+    expires_on DATE NOT NULL,
+    received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pantry_batches_item_expiry
+    ON pantry_batches (pantry_item_id, expires_on);
+
+-- The dated batches each meal drew from, so they can be put back.
+CREATE TABLE IF NOT EXISTS meal_ingredient_batches (
+    meal_id INTEGER REFERENCES meals(id) ON DELETE CASCADE,
+    pantry_item_id INTEGER REFERENCES pantry_items(id) ON DELETE CASCADE,
+    expires_on DATE NOT NULL,
+    quantity DECIMAL NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_meal_ingredient_batches_meal_id
+    ON meal_ingredient_batches (meal_id);
+
+-- Units used to be free text. Rewrite the spellings of stock units to the
+-- canonical names unit conversion works with; anything else is left for
+-- the user to correct.
+UPDATE pantry_items p
+SET unit = a.unit
+FROM (VALUES
+    ('g', 'g'), ('gram', 'g'), ('grams', 'g'), ('gramme', 'g'), ('grammes', 'g'),
+    ('kg', 'kg'), ('kilogram', 'kg'), ('kilograms', 'kg'), ('kgs', 'kg'),
+    ('ml', 'ml'), ('milliliter', 'ml'), ('milliliters', 'ml'), ('millilitre', 'ml'), ('millilitres', 'ml'),
+    ('l', 'l'), ('liter', 'l'), ('liters', 'l'), ('litre', 'l'), ('litres', 'l'),
+    ('item', 'item'), ('items', 'item'), ('piece', 'item'), ('pieces', 'item'), ('pc', 'item'), ('pcs', 'item'), ('each', 'item')
+) AS a (spelling, unit)
+WHERE lower(trim(p.unit)) = a.spelling AND p.unit <> a.unit;
+
+UPDATE shopping_list_items s
+SET unit = p.unit
+FROM pantry_items p
+WHERE s.pantry_item_id = p.id AND s.unit IS DISTINCT FROM p.unit;
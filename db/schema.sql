@@ -4,6 +4,26 @@ CREATE TABLE IF NOT EXISTS users (
     email VARCHAR(255) UNIQUE NOT NULL,
     password_hash VARCHAR(255) NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deletion_requested_at TIMESTAMP NULL,  -- set when the user asks to delete their account
+    deletion_scheduled_for TIMESTAMP NULL,  -- hard purge after the grace period
+    disabled_at TIMESTAMP NULL  -- set when an administrator disables the account
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_for
+    ON users (deletion_scheduled_for)
+    WHERE deletion_scheduled_for IS NOT NULL;
+
+-- User Profiles (one row per user; measurements are stored in metric)
+CREATE TABLE IF NOT EXISTS user_profiles (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    bodyweight_kg DECIMAL NULL,
+    height_cm DECIMAL NULL,
+    birth_year INTEGER NULL,
+    sex VARCHAR(16) NULL CHECK (sex IN ('male', 'female', 'other')),
+    preferred_units VARCHAR(16) NOT NULL DEFAULT 'metric' CHECK (preferred_units IN ('metric', 'imperial')),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',  -- IANA name, used to bucket sessions into days
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -42,6 +62,7 @@ CREATE TABLE IF NOT EXISTS workout_sessions (
     id SERIAL PRIMARY KEY,
     user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
     gym_id INTEGER REFERENCES gyms(id) ON DELETE CASCADE,
+    cardio_minutes INTEGER NULL CHECK (cardio_minutes >= 0),  -- Optional conditioning work done in the session
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -57,21 +78,65 @@ CREATE TABLE IF NOT EXISTS workout_exercises (
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Body Measurements (bodyweight, body fat and tape measurements over time)
+CREATE TABLE IF NOT EXISTS body_measurements (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    measured_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    bodyweight_kg DECIMAL NULL,
+    body_fat_pct DECIMAL NULL,
+    waist_cm DECIMAL NULL,
+    arms_cm DECIMAL NULL,
+    chest_cm DECIMAL NULL,
+    notes VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_body_measurements_user_measured_at
+    ON body_measurements (user_id, measured_at);
+
 -- Pantry Items
 CREATE TABLE IF NOT EXISTS pantry_items (
     id SERIAL PRIMARY KEY,
     user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
     name VARCHAR(255) NOT NULL,
     quantity DECIMAL NOT NULL,
-    unit VARCHAR(50) NOT NULL,  -- grams, items, liters
+    unit VARCHAR(50) NOT NULL,  -- g, kg, ml, l or item
     threshold DECIMAL NOT NULL,  -- minimum required quantity
     calories_per_unit DECIMAL NOT NULL,
     protein_per_unit DECIMAL NOT NULL,
+    carbs_per_unit DECIMAL NOT NULL DEFAULT 0,
+    fat_per_unit DECIMAL NOT NULL DEFAULT 0,
+    fiber_per_unit DECIMAL NOT NULL DEFAULT 0,
+    grams_per_item DECIMAL NULL CHECK (grams_per_item > 0),  -- lets items also be measured by weight
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     UNIQUE(user_id, name)
 );
 
+-- Pantry Batches (dated stock; quantity not covered by a batch has no expiry date)
+CREATE TABLE IF NOT EXISTS pantry_batches (
+    id SERIAL PRIMARY KEY,
+    pantry_item_id INTEGER REFERENCES pantry_items(id) ON DELETE CASCADE,
+    quantity DECIMAL NOT NULL CHECK (quantity > 0),
+    expires_on DATE NOT NULL,
+    received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pantry_batches_item_expiry
+    ON pantry_batches (pantry_item_id, expires_on);
+
+-- Nutrition Targets (daily macro goals, one row per user)
+CREATE TABLE IF NOT EXISTS nutrition_targets (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    calories DECIMAL NULL,
+    protein DECIMAL NULL,
+    carbs DECIMAL NULL,
+    fat DECIMAL NULL,
+    fiber DECIMAL NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Meals
 CREATE TABLE IF NOT EXISTS meals (
     id SERIAL PRIMARY KEY,
@@ -87,13 +152,114 @@ CREATE TABLE IF NOT EXISTS meal_ingredients (
     PRIMARY KEY (meal_id, pantry_item_id)
 );
 
+-- Meal Ingredient Batches (the dated batches each meal drew from, so they can be put back)
+CREATE TABLE IF NOT EXISTS meal_ingredient_batches (
+    meal_id INTEGER REFERENCES meals(id) ON DELETE CASCADE,
+    pantry_item_id INTEGER REFERENCES pantry_items(id) ON DELETE CASCADE,
+    expires_on DATE NOT NULL,
+    quantity DECIMAL NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_meal_ingredient_batches_meal_id
+    ON meal_ingredient_batches (meal_id);
+
+-- Recipes (a meal cooked repeatedly, made for a number of servings)
+CREATE TABLE IF NOT EXISTS recipes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    servings DECIMAL NOT NULL CHECK (servings > 0),
+    notes TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, name)
+);
+
+-- Recipe Ingredients (pantry items and the amount used for the whole recipe)
+CREATE TABLE IF NOT EXISTS recipe_ingredients (
+    recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+    pantry_item_id INTEGER REFERENCES pantry_items(id),
+    quantity DECIMAL NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (recipe_id, pantry_item_id)
+);
+
+-- Meal Plan Entries (servings of a recipe or a list of ingredients planned for a day)
+CREATE TABLE IF NOT EXISTS meal_plan_entries (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    planned_date DATE NOT NULL,
+    recipe_id INTEGER NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    servings DECIMAL NULL CHECK (servings > 0),
+    notes TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((recipe_id IS NULL) = (servings IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_meal_plan_entries_user_date
+    ON meal_plan_entries (user_id, planned_date);
+
+-- Meal Plan Ingredients (pantry items of entries planned without a recipe)
+CREATE TABLE IF NOT EXISTS meal_plan_ingredients (
+    entry_id INTEGER REFERENCES meal_plan_entries(id) ON DELETE CASCADE,
+    pantry_item_id INTEGER REFERENCES pantry_items(id),
+    quantity DECIMAL NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (entry_id, pantry_item_id)
+);
+
 -- Shopping List View (items below threshold)
 CREATE OR REPLACE VIEW shopping_list AS
 SELECT 
     u.id AS user_id,
     pi.name,
     (pi.threshold - pi.quantity) AS quantity_needed,
-    pi.unit
+    pi.unit,
+    pi.id AS pantry_item_id
 FROM pantry_items pi
 JOIN users u ON pi.user_id = u.id
-WHERE pi.quantity < pi.threshold;
\ No newline at end of file
+WHERE pi.quantity < pi.threshold;
+
+-- Shopping List Items (entries added by hand, optionally tied to a pantry item)
+CREATE TABLE IF NOT EXISTS shopping_list_items (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    pantry_item_id INTEGER NULL REFERENCES pantry_items(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    quantity DECIMAL NULL,
+    unit VARCHAR(50) NULL,
+    checked BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_shopping_list_items_user_id
+    ON shopping_list_items (user_id);
+
+-- Shopping List Checks (check-off state for rows of the shopping_list view)
+CREATE TABLE IF NOT EXISTS shopping_list_checks (
+    pantry_item_id INTEGER PRIMARY KEY REFERENCES pantry_items(id) ON DELETE CASCADE,
+    checked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Foods (shared catalogue imported from nutrition databases; nutrients per 100 g or 100 ml)
+CREATE TABLE IF NOT EXISTS foods (
+    id SERIAL PRIMARY KEY,
+    source VARCHAR(50) NOT NULL,
+    source_id VARCHAR(100) NOT NULL,
+    barcode VARCHAR(14) NULL,
+    name VARCHAR(255) NOT NULL,
+    brand VARCHAR(255) NULL,
+    basis_unit VARCHAR(2) NOT NULL DEFAULT 'g' CHECK (basis_unit IN ('g', 'ml')),
+    serving_size DECIMAL NULL CHECK (serving_size > 0),
+    calories_per_100 DECIMAL NULL,
+    protein_per_100 DECIMAL NULL,
+    carbs_per_100 DECIMAL NULL,
+    fat_per_100 DECIMAL NULL,
+    fiber_per_100 DECIMAL NULL,
+    search_vector TSVECTOR GENERATED ALWAYS AS (
+        to_tsvector('simple', name || ' ' || COALESCE(brand, ''))
+    ) STORED,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(source, source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_foods_barcode ON foods (barcode);
+CREATE INDEX IF NOT EXISTS idx_foods_search ON foods USING GIN (search_vector);
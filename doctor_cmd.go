@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	dbfiles "github.com/Ross1116/gym-tracker-backend/db"
+	"github.com/Ross1116/gym-tracker-backend/internal/config"
+	"github.com/Ross1116/gym-tracker-backend/internal/doctor"
+	"github.com/Ross1116/gym-tracker-backend/internal/migrate"
+	"github.com/urfave/cli/v2"
+)
+
+var doctorCommand = &cli.Command{
+	Name:  "doctor",
+	Usage: "check database connectivity, schema version and orphaned rows",
+	Action: func(c *cli.Context) error {
+		cfg, err := config.Load(c.String("config"))
+		if err != nil {
+			return err
+		}
+		migrations, err := migrate.Load(dbfiles.Migrations)
+		if err != nil {
+			return err
+		}
+
+		// Unlike other commands, an unreachable database is reported as a
+		// failed check rather than an error.
+		var results []doctor.Result
+		db, err := openDB(c.Context, cfg.Database)
+		if err != nil {
+			results = []doctor.Result{{Check: "database", Status: doctor.Fail, Detail: err.Error()}}
+		} else {
+			defer db.Close()
+			results = doctor.Run(c.Context, db, migrations)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		failed := 0
+		for _, r := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", r.Status, r.Check, r.Detail)
+			if r.Status == doctor.Fail {
+				failed++
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d check(s) failed", failed)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/config"
+	"github.com/Ross1116/gym-tracker-backend/internal/export"
+	"github.com/urfave/cli/v2"
+)
+
+// exportCommand writes a user's data as a JSON document or a zip of CSV
+// files.
+var exportCommand = &cli.Command{
+	Name:  "export",
+	Usage: "export a user's data",
+	Flags: []cli.Flag{
+		&cli.IntFlag{Name: "user", Usage: "ID of the user to export", Required: true},
+		&cli.StringFlag{Name: "format", Value: "json", Usage: "export format: json or csv (zip archive)"},
+		&cli.StringFlag{Name: "out", Value: "-", Usage: "output file, or - for stdout"},
+	},
+	Action: withDB(func(c *cli.Context, cfg config.Config, db *sql.DB) error {
+		write := export.WriteJSON
+		switch format := c.String("format"); format {
+		case "json":
+		case "csv":
+			write = export.WriteCSVZip
+		default:
+			return fmt.Errorf("unsupported export format %q", format)
+		}
+
+		var w io.Writer = os.Stdout
+		if out := c.String("out"); out != "-" {
+			f, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+
+		return write(c.Context, db, c.Int("user"), w)
+	}),
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/config"
+	"github.com/Ross1116/gym-tracker-backend/internal/foods"
+	"github.com/urfave/cli/v2"
+)
+
+// foodsCommand holds the food catalogue commands. `foods import` loads an
+// Open Food Facts, USDA or similar CSV/JSONL dump (optionally gzipped) into
+// the shared food catalogue and prints the import report as JSON.
+var foodsCommand = &cli.Command{
+	Name:  "foods",
+	Usage: "manage the shared food catalogue",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "import",
+			Usage: "import a food database dump",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "file", Usage: "CSV or JSONL dump to import, .gz allowed, or - for stdin", Required: true},
+				&cli.StringFlag{Name: "source", Usage: "name of the dataset, e.g. off or usda", Required: true},
+				&cli.StringFlag{Name: "format", Value: "auto", Usage: "dump format: auto, csv or jsonl"},
+			},
+			Action: withDB(func(c *cli.Context, cfg config.Config, db *sql.DB) error {
+				return runFoodsImport(c.Context, db, c.String("file"), c.String("source"), c.String("format"))
+			}),
+		},
+	},
+}
+
+func runFoodsImport(ctx context.Context, db *sql.DB, path, source, format string) error {
+	opts := foods.ImportOptions{Source: source}
+	var err error
+	if opts.Format, err = foods.ParseFormat(format); err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(in)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		defer gz.Close()
+		in = gz
+	}
+
+	report, err := foods.Import(ctx, db, in, opts)
+	if err != nil {
+		return err
+	}
+	return printJSON(report)
+}
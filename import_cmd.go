@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/account"
+	"github.com/Ross1116/gym-tracker-backend/internal/config"
+	"github.com/Ross1116/gym-tracker-backend/internal/export"
+	"github.com/Ross1116/gym-tracker-backend/internal/importer"
+	"github.com/urfave/cli/v2"
+)
+
+// importCommand loads a Strong, Hevy or FitNotes CSV export (or, with
+// --format archive, a file written by `export`) for a user and prints the
+// import report as JSON.
+var importCommand = &cli.Command{
+	Name:  "import",
+	Usage: "import a workout app export or an export archive for a user",
+	Flags: []cli.Flag{
+		&cli.IntFlag{Name: "user", Usage: "ID of the user to import into", Required: true},
+		&cli.StringFlag{Name: "file", Usage: "CSV export to import, or - for stdin", Required: true},
+		&cli.StringFlag{Name: "format", Value: "auto", Usage: "export format: auto, strong, hevy, fitnotes or archive"},
+		&cli.IntFlag{Name: "gym", Usage: "gym to attach sessions to (default: placeholder gym per format)"},
+		&cli.StringFlag{Name: "mapping", Usage: "JSON file with exercise/equipment mapping rules"},
+		&cli.BoolFlag{Name: "dry-run", Usage: "report what would be imported without writing"},
+		&cli.BoolFlag{Name: "interactive", Usage: "prompt for exercises that cannot be mapped"},
+		&cli.StringFlag{Name: "tz", Usage: "time zone of timestamps in the export (default: the user's profile time zone)"},
+	},
+	Action: withDB(func(c *cli.Context, cfg config.Config, db *sql.DB) error {
+		return runImport(c, db)
+	}),
+}
+
+func runImport(c *cli.Context, db *sql.DB) error {
+	ctx := c.Context
+	userID, path := c.Int("user"), c.String("file")
+	if c.String("format") == "archive" {
+		return importArchive(ctx, db, userID, path)
+	}
+	if c.Bool("interactive") && path == "-" {
+		return errors.New("--interactive cannot be used when reading the export from stdin")
+	}
+
+	opts := importer.Options{GymID: c.Int("gym"), DryRun: c.Bool("dry-run")}
+	var err error
+	if opts.Format, err = importer.ParseFormat(c.String("format")); err != nil {
+		return err
+	}
+	if tz := c.String("tz"); tz == "" {
+		opts.Location, err = account.Location(ctx, db, userID)
+	} else {
+		opts.Location, err = time.LoadLocation(tz)
+	}
+	if err != nil {
+		return err
+	}
+
+	if mappingPath := c.String("mapping"); mappingPath != "" {
+		f, err := os.Open(mappingPath)
+		if err != nil {
+			return err
+		}
+		opts.Mapping, err = importer.LoadMapping(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("reading mapping file: %w", err)
+		}
+	}
+
+	if c.Bool("interactive") {
+		stdin := bufio.NewReader(os.Stdin)
+		opts.Resolve = func(name string) (string, error) {
+			fmt.Fprintf(os.Stderr, "No exercise matches %q. Enter an exercise name to use (blank to skip): ", name)
+			answer, err := stdin.ReadString('\n')
+			if err != nil && err != io.EOF {
+				return "", err
+			}
+			return strings.TrimSpace(answer), nil
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	report, err := importer.Import(ctx, db, userID, in, opts)
+	if report != nil {
+		if encErr := printJSON(report); encErr != nil {
+			return encErr
+		}
+	}
+	if errors.Is(err, importer.ErrUnmapped) {
+		return fmt.Errorf("%w; add them to a --mapping file, set create_missing, or use --interactive", err)
+	}
+	return err
+}
+
+func importArchive(ctx context.Context, db *sql.DB, userID int, path string) error {
+	if path == "-" {
+		return errors.New("archive imports must be read from a file")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	report, err := export.Import(ctx, db, userID, f, info.Size())
+	if err != nil {
+		return err
+	}
+	return printJSON(report)
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
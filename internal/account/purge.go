@@ -0,0 +1,48 @@
+// Package account holds account lifecycle rules shared by the HTTP handlers
+// and the command line, such as the deletion grace period and hard purge.
+package account
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+)
+
+// DeletionGracePeriod is how long a deletion request can be cancelled, and
+// the user's data exported, before the account is purged.
+const DeletionGracePeriod = 30 * 24 * time.Hour
+
+// PurgeDeletedUsers hard-deletes every account whose grace period has
+// elapsed. Gyms, workouts, pantry items and meals are removed through the
+// ON DELETE CASCADE foreign keys on users.
+func PurgeDeletedUsers(ctx context.Context, db *sql.DB) (int64, error) {
+	result, err := db.ExecContext(ctx,
+		"DELETE FROM users WHERE deletion_scheduled_for IS NOT NULL AND deletion_scheduled_for <= CURRENT_TIMESTAMP",
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RunPurger calls PurgeDeletedUsers every interval until ctx is done.
+func RunPurger(ctx context.Context, db *sql.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := PurgeDeletedUsers(ctx, db)
+		if err != nil {
+			slog.ErrorContext(ctx, "account purge failed", "error", err)
+		} else if n > 0 {
+			slog.InfoContext(ctx, "purged deleted accounts", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
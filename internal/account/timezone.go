@@ -0,0 +1,30 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Timezone returns the IANA time zone name from the user's profile, or
+// "UTC" when the user has not set one.
+func Timezone(ctx context.Context, db *sql.DB, userID int) (string, error) {
+	var tz string
+	err := db.QueryRowContext(ctx, "SELECT timezone FROM user_profiles WHERE user_id = $1", userID).Scan(&tz)
+	if err == sql.ErrNoRows {
+		return "UTC", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return tz, nil
+}
+
+// Location is Timezone resolved to a *time.Location.
+func Location(ctx context.Context, db *sql.DB, userID int) (*time.Location, error) {
+	tz, err := Timezone(ctx, db, userID)
+	if err != nil {
+		return nil, err
+	}
+	return time.LoadLocation(tz)
+}
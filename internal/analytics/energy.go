@@ -0,0 +1,111 @@
+package analytics
+
+import "fmt"
+
+// Defaults for the energy calculator. Each can be overridden per request.
+const (
+	// DefaultActivityFactor is the multiplier for daily life outside
+	// training (roughly "sedentary to lightly active"), since logged
+	// training is added separately.
+	DefaultActivityFactor = 1.3
+	// DefaultKcalPerSetPerKg approximates the cost of one working set of
+	// resistance training, scaled by bodyweight: about 8 kcal for 80 kg.
+	DefaultKcalPerSetPerKg = 0.1
+	// DefaultCardioMET is moderate steady-state cardio.
+	DefaultCardioMET = 7.0
+	// DefaultProteinPerKg sits in the 1.6-2.2 g/kg range shown to support
+	// muscle gain in resistance-trained people.
+	DefaultProteinPerKg = 1.8
+)
+
+type EnergyInputs struct {
+	BodyweightKg        float64
+	HeightCm            float64
+	Age                 int
+	Sex                 string // male, female, or anything else for the midpoint
+	ActivityFactor      float64
+	SetsPerDay          float64
+	CardioMinutesPerDay float64
+	KcalPerSetPerKg     float64
+	CardioMET           float64
+	ProteinPerKg        float64
+	AdjustmentKcal      float64
+}
+
+type EnergyEstimate struct {
+	BMR         float64
+	Baseline    float64
+	Training    float64
+	Cardio      float64
+	Maintenance float64
+	Target      float64
+	ProteinG    float64
+	Reasoning   []string
+}
+
+// MifflinStJeor returns resting energy expenditure in kcal/day. Sexes other
+// than male and female use the midpoint of the two offsets.
+func MifflinStJeor(weightKg, heightCm float64, age int, sex string) float64 {
+	offset := -78.0
+	switch sex {
+	case "male":
+		offset = 5
+	case "female":
+		offset = -161
+	}
+	return 10*weightKg + 6.25*heightCm - 5*float64(age) + offset
+}
+
+// EstimateEnergy derives maintenance calories as BMR × activity factor plus
+// the average daily cost of logged resistance training and cardio, then
+// applies the requested surplus or deficit. Each step is described in
+// Reasoning so the result can be checked and any factor overridden.
+func EstimateEnergy(in EnergyInputs) EnergyEstimate {
+	var e EnergyEstimate
+
+	e.BMR = MifflinStJeor(in.BodyweightKg, in.HeightCm, in.Age, in.Sex)
+	e.Reasoning = append(e.Reasoning, fmt.Sprintf(
+		"BMR (Mifflin-St Jeor) = 10 × %.1f kg + 6.25 × %.1f cm − 5 × %d years %s = %.0f kcal",
+		in.BodyweightKg, in.HeightCm, in.Age, sexOffsetText(in.Sex), e.BMR))
+
+	e.Baseline = e.BMR * in.ActivityFactor
+	e.Reasoning = append(e.Reasoning, fmt.Sprintf(
+		"Daily activity outside training: %.0f × %.2f = %.0f kcal", e.BMR, in.ActivityFactor, e.Baseline))
+
+	e.Training = in.SetsPerDay * in.KcalPerSetPerKg * in.BodyweightKg
+	e.Reasoning = append(e.Reasoning, fmt.Sprintf(
+		"Resistance training: %.1f sets/day × %.2f kcal/kg × %.1f kg = %.0f kcal",
+		in.SetsPerDay, in.KcalPerSetPerKg, in.BodyweightKg, e.Training))
+
+	// MET × kg × hours, less the 1 MET already counted in the baseline.
+	e.Cardio = max(in.CardioMET-1, 0) * in.BodyweightKg * in.CardioMinutesPerDay / 60
+	e.Reasoning = append(e.Reasoning, fmt.Sprintf(
+		"Cardio: (%.1f − 1) MET × %.1f kg × %.1f min/day ÷ 60 = %.0f kcal",
+		in.CardioMET, in.BodyweightKg, in.CardioMinutesPerDay, e.Cardio))
+
+	e.Maintenance = e.Baseline + e.Training + e.Cardio
+	e.Reasoning = append(e.Reasoning, fmt.Sprintf(
+		"Maintenance = %.0f + %.0f + %.0f = %.0f kcal", e.Baseline, e.Training, e.Cardio, e.Maintenance))
+
+	e.Target = e.Maintenance + in.AdjustmentKcal
+	if in.AdjustmentKcal != 0 {
+		e.Reasoning = append(e.Reasoning, fmt.Sprintf(
+			"Target = maintenance %+.0f kcal = %.0f kcal", in.AdjustmentKcal, e.Target))
+	}
+
+	e.ProteinG = in.ProteinPerKg * in.BodyweightKg
+	e.Reasoning = append(e.Reasoning, fmt.Sprintf(
+		"Protein: %.1f g/kg × %.1f kg = %.0f g", in.ProteinPerKg, in.BodyweightKg, e.ProteinG))
+
+	return e
+}
+
+func sexOffsetText(sex string) string {
+	switch sex {
+	case "male":
+		return "+ 5"
+	case "female":
+		return "− 161"
+	}
+	return "− 78 (midpoint of the male and female offsets)"
+}
@@ -0,0 +1,119 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMifflinStJeor(t *testing.T) {
+	tests := []struct {
+		name     string
+		weightKg float64
+		heightCm float64
+		age      int
+		sex      string
+		want     float64
+	}{
+		{"male", 80, 180, 30, "male", 1780},
+		{"female", 60, 165, 25, "female", 1345.25},
+		{"other takes the midpoint", 70, 170, 40, "other", 1484.5},
+		{"unspecified takes the midpoint", 70, 170, 40, "unspecified", 1484.5},
+		{"empty takes the midpoint", 70, 170, 40, "", 1484.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MifflinStJeor(tt.weightKg, tt.heightCm, tt.age, tt.sex); !near(got, tt.want) {
+				t.Errorf("MifflinStJeor(%v, %v, %d, %q) = %v, want %v", tt.weightKg, tt.heightCm, tt.age, tt.sex, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateEnergy(t *testing.T) {
+	base := EnergyInputs{
+		BodyweightKg:    80,
+		HeightCm:        180,
+		Age:             30,
+		Sex:             "male",
+		ActivityFactor:  DefaultActivityFactor,
+		KcalPerSetPerKg: DefaultKcalPerSetPerKg,
+		CardioMET:       DefaultCardioMET,
+		ProteinPerKg:    DefaultProteinPerKg,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*EnergyInputs)
+		want   EnergyEstimate
+	}{
+		{
+			name: "no logged training",
+			want: EnergyEstimate{BMR: 1780, Baseline: 2314, Maintenance: 2314, Target: 2314, ProteinG: 144},
+		},
+		{
+			name: "training, cardio and a deficit",
+			modify: func(in *EnergyInputs) {
+				in.SetsPerDay = 10
+				in.CardioMinutesPerDay = 20
+				in.AdjustmentKcal = -500
+			},
+			want: EnergyEstimate{BMR: 1780, Baseline: 2314, Training: 80, Cardio: 160, Maintenance: 2554, Target: 2054, ProteinG: 144},
+		},
+		{
+			// Activity below resting adds nothing, as the baseline already
+			// counts 1 MET.
+			name: "cardio at resting MET",
+			modify: func(in *EnergyInputs) {
+				in.CardioMinutesPerDay = 60
+				in.CardioMET = 1
+			},
+			want: EnergyEstimate{BMR: 1780, Baseline: 2314, Maintenance: 2314, Target: 2314, ProteinG: 144},
+		},
+		{
+			name: "unknown sex and a surplus",
+			modify: func(in *EnergyInputs) {
+				in.Sex = "unspecified"
+				in.AdjustmentKcal = 250
+			},
+			want: EnergyEstimate{BMR: 1697, Baseline: 2206.1, Maintenance: 2206.1, Target: 2456.1, ProteinG: 144},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := base
+			if tt.modify != nil {
+				tt.modify(&in)
+			}
+			got := EstimateEnergy(in)
+
+			for _, f := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"BMR", got.BMR, tt.want.BMR},
+				{"Baseline", got.Baseline, tt.want.Baseline},
+				{"Training", got.Training, tt.want.Training},
+				{"Cardio", got.Cardio, tt.want.Cardio},
+				{"Maintenance", got.Maintenance, tt.want.Maintenance},
+				{"Target", got.Target, tt.want.Target},
+				{"ProteinG", got.ProteinG, tt.want.ProteinG},
+			} {
+				if !near(f.got, f.want) {
+					t.Errorf("%s = %v, want %v", f.name, f.got, f.want)
+				}
+			}
+
+			// The target is only explained when it differs from maintenance.
+			wantSteps := 6
+			if in.AdjustmentKcal != 0 {
+				wantSteps = 7
+			}
+			if len(got.Reasoning) != wantSteps {
+				t.Errorf("reasoning has %d steps, want %d: %q", len(got.Reasoning), wantSteps, got.Reasoning)
+			}
+			if !strings.Contains(got.Reasoning[0], sexOffsetText(in.Sex)) {
+				t.Errorf("BMR step %q does not give the sex offset %q", got.Reasoning[0], sexOffsetText(in.Sex))
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package analytics holds the pure calculations behind the trend and
+// summary endpoints, kept free of database and HTTP concerns.
+package analytics
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultAlpha is the smoothing factor applied per day of elapsed time,
+// the value popularised by The Hacker's Diet for daily bodyweight.
+const DefaultAlpha = 0.1
+
+const day = 24 * time.Hour
+
+type Sample struct {
+	At    time.Time
+	Value float64
+}
+
+type TrendPoint struct {
+	At    time.Time
+	Value float64
+	Trend float64
+	// WeeklyRate is the change in trend per 7 days measured against the
+	// latest point at least a week older, or nil when there is none yet.
+	WeeklyRate *float64
+}
+
+// EWMA smooths samples, which must be sorted by time, with an exponentially
+// weighted moving average. Irregular logging is handled by compounding the
+// smoothing factor over the days elapsed since the previous sample, so a
+// weigh-in after a two week gap pulls the trend further than one taken the
+// next morning.
+func EWMA(samples []Sample, alpha float64) []TrendPoint {
+	if alpha <= 0 || alpha > 1 {
+		alpha = DefaultAlpha
+	}
+
+	points := make([]TrendPoint, len(samples))
+	for i, s := range samples {
+		points[i] = TrendPoint{At: s.At, Value: s.Value, Trend: s.Value}
+		if i == 0 {
+			continue
+		}
+		prev := points[i-1]
+		days := math.Max(s.At.Sub(prev.At).Hours()/24, 1)
+		weight := 1 - math.Pow(1-alpha, days)
+		points[i].Trend = prev.Trend + weight*(s.Value-prev.Trend)
+		points[i].WeeklyRate = weeklyRate(points[:i+1])
+	}
+	return points
+}
+
+func weeklyRate(points []TrendPoint) *float64 {
+	last := points[len(points)-1]
+	for j := len(points) - 2; j >= 0; j-- {
+		elapsed := last.At.Sub(points[j].At)
+		if elapsed >= 7*day {
+			rate := (last.Trend - points[j].Trend) / elapsed.Hours() * 24 * 7
+			return &rate
+		}
+	}
+	return nil
+}
+
+// EstimatedOneRepMax estimates the heaviest single of a set with the Epley
+// formula; a single rep returns the weight lifted.
+func EstimatedOneRepMax(weight float64, reps int) float64 {
+	if reps <= 1 {
+		return weight
+	}
+	return weight * (1 + float64(reps)/30)
+}
@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2025, 3, 3, 7, 0, 0, 0, time.UTC)
+
+// at returns the time the given number of days after start.
+func at(days float64) time.Time {
+	return start.Add(time.Duration(days * float64(day)))
+}
+
+func TestEWMA(t *testing.T) {
+	rate := func(r float64) *float64 { return &r }
+
+	tests := []struct {
+		name      string
+		samples   []Sample
+		alpha     float64
+		wantTrend []float64
+		wantRate  []*float64
+	}{
+		{
+			name:      "single sample",
+			samples:   []Sample{{at(0), 80}},
+			alpha:     0.1,
+			wantTrend: []float64{80},
+			wantRate:  []*float64{nil},
+		},
+		{
+			// Weigh-ins on the same day count as a day apart.
+			name:      "same day",
+			samples:   []Sample{{at(0), 80}, {at(0.1), 81}},
+			alpha:     0.1,
+			wantTrend: []float64{80, 80.1},
+			wantRate:  []*float64{nil, nil},
+		},
+		{
+			// The smoothing factor compounds over the days between
+			// weigh-ins, and the rate is measured against the latest point
+			// at least a week older.
+			name:      "irregular gaps",
+			samples:   []Sample{{at(0), 80}, {at(2), 82}, {at(12), 78}},
+			alpha:     0.1,
+			wantTrend: []float64{80, 80.38, 78.829854687438},
+			wantRate:  []*float64{nil, nil, rate(-1.0851017187933962)},
+		},
+		{
+			name:      "exactly a week",
+			samples:   []Sample{{at(0), 80}, {at(7), 81}},
+			alpha:     0.1,
+			wantTrend: []float64{80, 80.5217031},
+			wantRate:  []*float64{nil, rate(0.5217031)},
+		},
+		{
+			name:      "alpha out of range uses the default",
+			samples:   []Sample{{at(0), 80}, {at(1), 81}},
+			alpha:     0,
+			wantTrend: []float64{80, 80.1},
+			wantRate:  []*float64{nil, nil},
+		},
+		{
+			name:      "no samples",
+			wantTrend: []float64{},
+			wantRate:  []*float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := EWMA(tt.samples, tt.alpha)
+			if len(points) != len(tt.wantTrend) {
+				t.Fatalf("got %d points, want %d", len(points), len(tt.wantTrend))
+			}
+			for i, p := range points {
+				if p.At != tt.samples[i].At || p.Value != tt.samples[i].Value {
+					t.Errorf("point %d = %v %v, want the sample %v %v", i, p.At, p.Value, tt.samples[i].At, tt.samples[i].Value)
+				}
+				if !near(p.Trend, tt.wantTrend[i]) {
+					t.Errorf("point %d trend = %v, want %v", i, p.Trend, tt.wantTrend[i])
+				}
+				switch want := tt.wantRate[i]; {
+				case want == nil && p.WeeklyRate != nil:
+					t.Errorf("point %d weekly rate = %v, want none", i, *p.WeeklyRate)
+				case want != nil && (p.WeeklyRate == nil || !near(*p.WeeklyRate, *want)):
+					t.Errorf("point %d weekly rate = %v, want %v", i, p.WeeklyRate, *want)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimatedOneRepMax(t *testing.T) {
+	tests := []struct {
+		weight float64
+		reps   int
+		want   float64
+	}{
+		{100, 1, 100},
+		{100, 0, 100},
+		{100, 5, 100 * (1 + 5.0/30)},
+		{60, 10, 80},
+	}
+	for _, tt := range tests {
+		if got := EstimatedOneRepMax(tt.weight, tt.reps); !near(got, tt.want) {
+			t.Errorf("EstimatedOneRepMax(%v, %d) = %v, want %v", tt.weight, tt.reps, got, tt.want)
+		}
+	}
+}
+
+func near(got, want float64) bool {
+	return math.Abs(got-want) < 1e-9
+}
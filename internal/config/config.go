@@ -0,0 +1,340 @@
+// Package config loads the server's settings. Defaults are overridden by an
+// optional YAML or TOML file, which is in turn overridden by environment
+// variables, so that deployments can keep secrets out of the file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable holding the path of the config
+// file used when none is given explicitly.
+const FileEnv = "GYM_CONFIG"
+
+type Config struct {
+	Database Database `yaml:"database" toml:"database"`
+	HTTP     HTTP     `yaml:"http" toml:"http"`
+	Auth     Auth     `yaml:"auth" toml:"auth"`
+	Log      Log      `yaml:"log" toml:"log"`
+	Tracing  Tracing  `yaml:"tracing" toml:"tracing"`
+}
+
+type Database struct {
+	// DSN is a lib/pq connection string or postgres:// URL.
+	DSN             string   `yaml:"dsn" toml:"dsn"`
+	MaxOpenConns    int      `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int      `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+}
+
+type HTTP struct {
+	// Addr is the address the server listens on.
+	Addr string `yaml:"addr" toml:"addr"`
+	// PublicHost is the host and port clients reach the API on, as shown
+	// in the Swagger docs.
+	PublicHost  string   `yaml:"public_host" toml:"public_host"`
+	CORSOrigins []string `yaml:"cors_origins" toml:"cors_origins"`
+
+	ReadTimeout  Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout  Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	// RequestTimeout bounds the database work done for one request. Exports
+	// and imports are exempt, as are their connections from WriteTimeout.
+	RequestTimeout Duration `yaml:"request_timeout" toml:"request_timeout"`
+	// ShutdownTimeout is how long in-flight requests are given to finish
+	// once the server is asked to stop.
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+type Auth struct {
+	// TokenSecret signs issued tokens. It is optional until a feature
+	// issuing tokens is enabled.
+	TokenSecret string `yaml:"token_secret" toml:"token_secret"`
+}
+
+type Log struct {
+	// Level is one of debug, info, warn or error.
+	Level string `yaml:"level" toml:"level"`
+}
+
+type Tracing struct {
+	// Exporter is where spans are sent: none, stdout, or otlp for an
+	// OpenTelemetry collector. stdout writes them to standard error, next
+	// to the logs, so that command output such as exports is unaffected.
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// Endpoint is the collector's OTLP/HTTP URL, such as
+	// http://localhost:4318. When empty the standard OTEL_EXPORTER_OTLP_*
+	// environment variables apply.
+	Endpoint    string `yaml:"endpoint" toml:"endpoint"`
+	ServiceName string `yaml:"service_name" toml:"service_name"`
+	// SampleRatio is the fraction of new traces recorded, from 0 to 1.
+	// Requests that arrive as part of a sampled trace are always recorded.
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// Duration is a time.Duration written as a string such as "30m" in config
+// files and environment variables.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+const minTokenSecretLength = 32
+
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+var tracingExporters = []string{"none", "stdout", "otlp"}
+
+// Default returns the settings used for anything not configured. There is
+// no default DSN: credentials must be supplied.
+func Default() Config {
+	return Config{
+		Database: Database{
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: Duration(30 * time.Minute),
+		},
+		HTTP: HTTP{
+			Addr:        ":9000",
+			PublicHost:  "localhost:9000",
+			CORSOrigins: []string{"http://localhost:3000"},
+
+			ReadTimeout:     Duration(30 * time.Second),
+			WriteTimeout:    Duration(60 * time.Second),
+			IdleTimeout:     Duration(2 * time.Minute),
+			RequestTimeout:  Duration(30 * time.Second),
+			ShutdownTimeout: Duration(20 * time.Second),
+		},
+		Log: Log{Level: "info"},
+		Tracing: Tracing{
+			Exporter:    "none",
+			ServiceName: "gym-tracker-backend",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Load reads the config file at path, or at $GYM_CONFIG when path is empty,
+// applies environment overrides and validates the result. Having no config
+// file at all is fine.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv(FileEnv)
+	}
+	if path != "" {
+		if err := readFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+	if err := applyEnv(&cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// readFile decodes the file into cfg, choosing the format by extension.
+// Unknown keys are rejected so that typos do not go unnoticed.
+func readFile(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(f)
+		dec.KnownFields(true)
+		err = dec.Decode(cfg)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".toml":
+		dec := toml.NewDecoder(f)
+		dec.DisallowUnknownFields()
+		err = dec.Decode(cfg)
+	default:
+		return fmt.Errorf("config: %s: unsupported file type %q (use .yaml, .yml or .toml)", path, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+	return nil
+}
+
+// envVars lists the environment variables read by applyEnv.
+var envVars = []struct {
+	name string
+	set  func(cfg *Config, value string) error
+}{
+	{"GYM_DATABASE_DSN", func(cfg *Config, v string) error { cfg.Database.DSN = v; return nil }},
+	{"GYM_DATABASE_MAX_OPEN_CONNS", func(cfg *Config, v string) error { return setInt(&cfg.Database.MaxOpenConns, v) }},
+	{"GYM_DATABASE_MAX_IDLE_CONNS", func(cfg *Config, v string) error { return setInt(&cfg.Database.MaxIdleConns, v) }},
+	{"GYM_DATABASE_CONN_MAX_LIFETIME", func(cfg *Config, v string) error { return cfg.Database.ConnMaxLifetime.UnmarshalText([]byte(v)) }},
+	{"GYM_HTTP_ADDR", func(cfg *Config, v string) error { cfg.HTTP.Addr = v; return nil }},
+	{"GYM_HTTP_PUBLIC_HOST", func(cfg *Config, v string) error { cfg.HTTP.PublicHost = v; return nil }},
+	{"GYM_HTTP_CORS_ORIGINS", func(cfg *Config, v string) error { cfg.HTTP.CORSOrigins = splitList(v); return nil }},
+	{"GYM_HTTP_READ_TIMEOUT", func(cfg *Config, v string) error { return cfg.HTTP.ReadTimeout.UnmarshalText([]byte(v)) }},
+	{"GYM_HTTP_WRITE_TIMEOUT", func(cfg *Config, v string) error { return cfg.HTTP.WriteTimeout.UnmarshalText([]byte(v)) }},
+	{"GYM_HTTP_IDLE_TIMEOUT", func(cfg *Config, v string) error { return cfg.HTTP.IdleTimeout.UnmarshalText([]byte(v)) }},
+	{"GYM_HTTP_REQUEST_TIMEOUT", func(cfg *Config, v string) error { return cfg.HTTP.RequestTimeout.UnmarshalText([]byte(v)) }},
+	{"GYM_HTTP_SHUTDOWN_TIMEOUT", func(cfg *Config, v string) error { return cfg.HTTP.ShutdownTimeout.UnmarshalText([]byte(v)) }},
+	{"GYM_AUTH_TOKEN_SECRET", func(cfg *Config, v string) error { cfg.Auth.TokenSecret = v; return nil }},
+	{"GYM_LOG_LEVEL", func(cfg *Config, v string) error { cfg.Log.Level = v; return nil }},
+	{"GYM_TRACING_EXPORTER", func(cfg *Config, v string) error { cfg.Tracing.Exporter = v; return nil }},
+	{"GYM_TRACING_ENDPOINT", func(cfg *Config, v string) error { cfg.Tracing.Endpoint = v; return nil }},
+	{"GYM_TRACING_SERVICE_NAME", func(cfg *Config, v string) error { cfg.Tracing.ServiceName = v; return nil }},
+	{"GYM_TRACING_SAMPLE_RATIO", func(cfg *Config, v string) error { return setFloat(&cfg.Tracing.SampleRatio, v) }},
+}
+
+func applyEnv(cfg *Config) error {
+	for _, env := range envVars {
+		v, ok := os.LookupEnv(env.name)
+		if !ok {
+			continue
+		}
+		if err := env.set(cfg, v); err != nil {
+			return fmt.Errorf("config: %s: %w", env.name, err)
+		}
+	}
+	return nil
+}
+
+func setInt(dst *int, v string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return fmt.Errorf("%q is not an integer", v)
+	}
+	*dst = n
+	return nil
+}
+
+func setFloat(dst *float64, v string) error {
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", v)
+	}
+	*dst = f
+	return nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(v string) []string {
+	var list []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Database.DSN == "" {
+		fail("database.dsn is required (set GYM_DATABASE_DSN)")
+	}
+	if c.Database.MaxOpenConns < 0 {
+		fail("database.max_open_conns must not be negative")
+	}
+	if c.Database.MaxIdleConns < 0 {
+		fail("database.max_idle_conns must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		fail("database.max_idle_conns must not exceed database.max_open_conns")
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		fail("database.conn_max_lifetime must not be negative")
+	}
+
+	if _, _, err := net.SplitHostPort(c.HTTP.Addr); err != nil {
+		fail("http.addr %q is not a host:port address", c.HTTP.Addr)
+	}
+	if c.HTTP.PublicHost == "" {
+		fail("http.public_host is required")
+	}
+	for _, t := range []struct {
+		name string
+		d    Duration
+	}{
+		{"http.read_timeout", c.HTTP.ReadTimeout},
+		{"http.write_timeout", c.HTTP.WriteTimeout},
+		{"http.idle_timeout", c.HTTP.IdleTimeout},
+		{"http.request_timeout", c.HTTP.RequestTimeout},
+		{"http.shutdown_timeout", c.HTTP.ShutdownTimeout},
+	} {
+		if t.d <= 0 {
+			fail("%s must be positive", t.name)
+		}
+	}
+	if c.HTTP.WriteTimeout > 0 && c.HTTP.RequestTimeout > c.HTTP.WriteTimeout {
+		fail("http.request_timeout must not exceed http.write_timeout")
+	}
+	if len(c.HTTP.CORSOrigins) == 0 {
+		fail("http.cors_origins must list at least one origin")
+	}
+	for _, origin := range c.HTTP.CORSOrigins {
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+			fail("http.cors_origins: %q is not an origin such as https://example.com", origin)
+		}
+	}
+
+	if s := c.Auth.TokenSecret; s != "" && len(s) < minTokenSecretLength {
+		fail("auth.token_secret must be at least %d characters", minTokenSecretLength)
+	}
+
+	if !slices.Contains(logLevels, c.Log.Level) {
+		fail("log.level must be one of %s", strings.Join(logLevels, ", "))
+	}
+
+	if !slices.Contains(tracingExporters, c.Tracing.Exporter) {
+		fail("tracing.exporter must be one of %s", strings.Join(tracingExporters, ", "))
+	}
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("tracing.endpoint: %q is not a URL such as http://localhost:4318", c.Tracing.Endpoint)
+		}
+	}
+	if c.Tracing.ServiceName == "" {
+		fail("tracing.service_name is required")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("tracing.sample_ratio must be between 0 and 1")
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("config: invalid settings:\n%w", err)
+	}
+	return nil
+}
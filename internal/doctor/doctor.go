@@ -0,0 +1,142 @@
+// Package doctor checks a deployment's database for problems an operator
+// should know about: whether it can be reached, whether its schema is up to
+// date, and whether it holds rows that no longer belong to anything.
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/migrate"
+)
+
+type Status int
+
+const (
+	OK Status = iota
+	Warn
+	Fail
+)
+
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "ok"
+	case Warn:
+		return "warn"
+	}
+	return "fail"
+}
+
+type Result struct {
+	Check  string
+	Status Status
+	Detail string
+}
+
+// orphanChecks count rows whose owner column is NULL. The foreign keys keep
+// them from pointing at missing rows, but the columns are nullable, and
+// such rows are invisible to every user.
+var orphanChecks = []struct{ table, column string }{
+	{"gyms", "user_id"},
+	{"gym_equipment", "gym_id"},
+	{"workout_sessions", "user_id"},
+	{"workout_exercises", "workout_session_id"},
+	{"body_measurements", "user_id"},
+	{"pantry_items", "user_id"},
+	{"pantry_batches", "pantry_item_id"},
+	{"meals", "user_id"},
+	{"meal_ingredients", "meal_id"},
+	{"recipes", "user_id"},
+	{"meal_plan_entries", "user_id"},
+	{"shopping_list_items", "user_id"},
+}
+
+// Run performs every check. The later checks are skipped when the database
+// cannot be reached or its schema is behind.
+func Run(ctx context.Context, db *sql.DB, migrations []migrate.Migration) []Result {
+	var results []Result
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SHOW server_version").Scan(&version); err != nil {
+		return append(results, Result{"database", Fail, err.Error()})
+	}
+	results = append(results, Result{"database", OK, "PostgreSQL " + version})
+
+	schema := checkSchema(ctx, db, migrations)
+	results = append(results, schema)
+	if schema.Status == Fail {
+		return results
+	}
+
+	return append(results, checkOrphans(ctx, db), checkOverduePurge(ctx, db))
+}
+
+func checkSchema(ctx context.Context, db *sql.DB, migrations []migrate.Migration) Result {
+	statuses, err := migrate.New(db, migrations).Inspect(ctx)
+	if errors.Is(err, migrate.ErrNotInitialised) {
+		return Result{"schema", Fail, "not initialised; run `migrate up`"}
+	} else if err != nil {
+		return Result{"schema", Fail, err.Error()}
+	}
+
+	var version int64
+	var pending, unknown []string
+	for _, s := range statuses {
+		name := fmt.Sprintf("%04d_%s", s.Version, s.Name)
+		switch {
+		case s.AppliedAt == nil:
+			pending = append(pending, name)
+		case s.Up == "":
+			unknown = append(unknown, name)
+			version = s.Version
+		default:
+			version = s.Version
+		}
+	}
+
+	switch {
+	case len(pending) > 0:
+		return Result{"schema", Fail, fmt.Sprintf("%d pending migration(s): %s; run `migrate up`",
+			len(pending), strings.Join(pending, ", "))}
+	case len(unknown) > 0:
+		return Result{"schema", Warn, fmt.Sprintf("applied migration(s) unknown to this build: %s; is it out of date?",
+			strings.Join(unknown, ", "))}
+	}
+	return Result{"schema", OK, fmt.Sprintf("at version %04d", version)}
+}
+
+func checkOrphans(ctx context.Context, db *sql.DB) Result {
+	var found []string
+	for _, o := range orphanChecks {
+		var n int
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s IS NULL", o.table, o.column)
+		if err := db.QueryRowContext(ctx, query).Scan(&n); err != nil {
+			return Result{"orphaned rows", Fail, err.Error()}
+		}
+		if n > 0 {
+			found = append(found, fmt.Sprintf("%d in %s without %s", n, o.table, o.column))
+		}
+	}
+	if len(found) > 0 {
+		return Result{"orphaned rows", Warn, strings.Join(found, "; ")}
+	}
+	return Result{"orphaned rows", OK, "none"}
+}
+
+func checkOverduePurge(ctx context.Context, db *sql.DB) Result {
+	var n int
+	err := db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM users WHERE deletion_scheduled_for <= CURRENT_TIMESTAMP",
+	).Scan(&n)
+	switch {
+	case err != nil:
+		return Result{"account purge", Fail, err.Error()}
+	case n > 0:
+		return Result{"account purge", Warn, fmt.Sprintf("%d account(s) past their deletion date; is `serve` running, or run `purge-users`", n)}
+	}
+	return Result{"account purge", OK, "no overdue deletions"}
+}
@@ -0,0 +1,201 @@
+// Package export writes a user's data as a versioned JSON document or as a
+// zip of CSV files, and loads either format back into the database.
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Version is the export document format version. It is bumped whenever a
+// section or column is added so that older files can still be recognised
+// on import.
+const Version = 8
+
+var ErrUserNotFound = errors.New("user not found")
+
+// Manifest is the header of a JSON export and the manifest.json entry of a
+// CSV archive.
+type Manifest struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	UserID     int       `json:"user_id"`
+	Email      string    `json:"email"`
+	Sections   []string  `json:"sections"`
+}
+
+// sink receives rows section by section so that exports are written as they
+// are read rather than being buffered in memory.
+type sink interface {
+	begin(s section) error
+	row(s section, v any) error
+	end(s section) error
+}
+
+// WriteJSON streams the user's data to w as a single JSON document.
+func WriteJSON(ctx context.Context, db *sql.DB, userID int, w io.Writer) error {
+	return walk(ctx, db, userID, func(m Manifest) (sink, func() error, error) {
+		js := &jsonSink{w: w}
+		header, err := json.Marshal(m)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Write the manifest fields first, leaving the object open for the
+		// sections that follow.
+		if _, err := w.Write(header[:len(header)-1]); err != nil {
+			return nil, nil, err
+		}
+		return js, func() error {
+			_, err := io.WriteString(w, "}\n")
+			return err
+		}, nil
+	})
+}
+
+// WriteCSVZip streams the user's data to w as a zip archive with one CSV
+// file per section plus a manifest.json.
+func WriteCSVZip(ctx context.Context, db *sql.DB, userID int, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	return walk(ctx, db, userID, func(m Manifest) (sink, func() error, error) {
+		f, err := zw.Create("manifest.json")
+		if err != nil {
+			return nil, nil, err
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(m); err != nil {
+			return nil, nil, err
+		}
+		return &csvSink{zw: zw}, zw.Close, nil
+	})
+}
+
+func walk(ctx context.Context, db *sql.DB, userID int, open func(Manifest) (sink, func() error, error)) error {
+	// A repeatable read snapshot keeps the sections consistent with each
+	// other while the export is streamed out.
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	m := Manifest{Version: Version, ExportedAt: time.Now().UTC(), UserID: userID}
+	err = tx.QueryRowContext(ctx, "SELECT email FROM users WHERE id = $1", userID).Scan(&m.Email)
+	if err == sql.ErrNoRows {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var present []section
+	for _, s := range sections {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", s.table).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			present = append(present, s)
+			m.Sections = append(m.Sections, s.name)
+		}
+	}
+
+	out, closeFn, err := open(m)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range present {
+		if err := writeSection(ctx, tx, userID, s, out); err != nil {
+			return fmt.Errorf("exporting %s: %w", s.name, err)
+		}
+	}
+
+	return closeFn()
+}
+
+func writeSection(ctx context.Context, tx *sql.Tx, userID int, s section, out sink) error {
+	rows, err := tx.QueryContext(ctx, s.query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := out.begin(s); err != nil {
+		return err
+	}
+	for rows.Next() {
+		v, err := s.scan(rows)
+		if err != nil {
+			return err
+		}
+		if err := out.row(s, v); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return out.end(s)
+}
+
+type jsonSink struct {
+	w     io.Writer
+	first bool
+}
+
+func (j *jsonSink) begin(s section) error {
+	j.first = true
+	_, err := fmt.Fprintf(j.w, ",%q:[", s.name)
+	return err
+}
+
+func (j *jsonSink) row(_ section, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if !j.first {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.first = false
+	_, err = j.w.Write(b)
+	return err
+}
+
+func (j *jsonSink) end(section) error {
+	_, err := io.WriteString(j.w, "]")
+	return err
+}
+
+type csvSink struct {
+	zw *zip.Writer
+	cw *csv.Writer
+}
+
+func (c *csvSink) begin(s section) error {
+	f, err := c.zw.Create(s.name + ".csv")
+	if err != nil {
+		return err
+	}
+	c.cw = csv.NewWriter(f)
+	return c.cw.Write(s.header)
+}
+
+func (c *csvSink) row(s section, v any) error {
+	return c.cw.Write(s.record(v))
+}
+
+func (c *csvSink) end(section) error {
+	c.cw.Flush()
+	return c.cw.Error()
+}
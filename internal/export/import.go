@@ -0,0 +1,530 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+var (
+	ErrInvalidArchive     = errors.New("invalid export file")
+	ErrUnsupportedVersion = errors.New("unsupported export version")
+)
+
+// loader inserts exported rows for a user, translating the ids found in the
+// export to the ids assigned by the database.
+type loader struct {
+	tx             *sql.Tx
+	userID         int
+	report         *models.ArchiveImportReport
+	gyms           map[int]int
+	equipment      map[int]int
+	sessions       map[int]int
+	pantryItems    map[int]int
+	meals          map[int]int
+	recipes        map[int]int
+	planEntries    map[int]int
+	exercises      map[string]int
+	equipmentTypes map[string]int
+}
+
+// Import loads a JSON export or CSV archive produced by WriteJSON or
+// WriteCSVZip into userID's account in a single transaction. Global lookup
+// rows (exercises, equipment types) are matched by name and created when
+// missing.
+func Import(ctx context.Context, db *sql.DB, userID int, r io.ReaderAt, size int64) (*models.ArchiveImportReport, error) {
+	head := make([]byte, 4)
+	n, _ := r.ReadAt(head, 0)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", userID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+
+	l := &loader{
+		tx:             tx,
+		userID:         userID,
+		report:         &models.ArchiveImportReport{Rows: map[string]int{}},
+		gyms:           map[int]int{},
+		equipment:      map[int]int{},
+		sessions:       map[int]int{},
+		pantryItems:    map[int]int{},
+		meals:          map[int]int{},
+		recipes:        map[int]int{},
+		planEntries:    map[int]int{},
+		exercises:      map[string]int{},
+		equipmentTypes: map[string]int{},
+	}
+
+	if bytes.Equal(head[:n], []byte("PK\x03\x04")) {
+		l.report.Format = "csv"
+		err = l.readZip(ctx, r, size)
+	} else {
+		l.report.Format = "json"
+		err = l.readJSON(ctx, io.NewSectionReader(r, 0, size))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return l.report, nil
+}
+
+func (l *loader) checkVersion(v int) error {
+	if v < 1 || v > Version {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, v)
+	}
+	l.report.Version = v
+	return nil
+}
+
+func (l *loader) readJSON(ctx context.Context, r io.Reader) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		key, _ := tok.(string)
+
+		if key == "version" {
+			var v int
+			if err := dec.Decode(&v); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+			}
+			if err := l.checkVersion(v); err != nil {
+				return err
+			}
+			continue
+		}
+
+		s, ok := sectionByName(key)
+		if !ok {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+			}
+			continue
+		}
+		if l.report.Version == 0 {
+			return fmt.Errorf("%w: version must precede %s", ErrInvalidArchive, key)
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			v := s.newRow()
+			if err := dec.Decode(v); err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, s.name, err)
+			}
+			if err := l.insert(ctx, s, v); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+
+	if l.report.Version == 0 {
+		return fmt.Errorf("%w: missing version", ErrInvalidArchive)
+	}
+	return nil
+}
+
+func (l *loader) readZip(ctx context.Context, r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	mf, ok := files["manifest.json"]
+	if !ok {
+		return fmt.Errorf("%w: missing manifest.json", ErrInvalidArchive)
+	}
+	var m Manifest
+	if err := decodeZipJSON(mf, &m); err != nil {
+		return err
+	}
+	if err := l.checkVersion(m.Version); err != nil {
+		return err
+	}
+
+	for _, s := range sections {
+		f, ok := files[s.name+".csv"]
+		if !ok {
+			continue
+		}
+		if err := l.readCSV(ctx, s, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *loader) readCSV(ctx context.Context, s section, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer rc.Close()
+
+	cr := csv.NewReader(rc)
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, f.Name, err)
+	}
+	// Columns are only ever appended to a section, so archives written by
+	// older versions carry a prefix of the current header. The missing
+	// trailing fields are read as empty.
+	if len(header) > len(s.header) || strings.Join(header, ",") != strings.Join(s.header[:len(header)], ",") {
+		return fmt.Errorf("%w: %s: unexpected header", ErrInvalidArchive, f.Name)
+	}
+	missing := make([]string, len(s.header)-len(header))
+
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, f.Name, err)
+		}
+		v, err := s.parse(append(rec, missing...))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if err := l.insert(ctx, s, v); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *loader) insert(ctx context.Context, s section, v any) error {
+	if err := s.insert(l, ctx, v); err != nil {
+		return fmt.Errorf("importing %s: %w", s.name, err)
+	}
+	l.report.Rows[s.name]++
+	return nil
+}
+
+func (l *loader) insertUserProfile(ctx context.Context, p *UserProfile) error {
+	_, err := l.tx.ExecContext(ctx, `
+        INSERT INTO user_profiles (user_id, bodyweight_kg, height_cm, birth_year, sex, preferred_units, timezone)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (user_id) DO UPDATE SET
+            bodyweight_kg = EXCLUDED.bodyweight_kg,
+            height_cm = EXCLUDED.height_cm,
+            birth_year = EXCLUDED.birth_year,
+            sex = EXCLUDED.sex,
+            preferred_units = EXCLUDED.preferred_units,
+            timezone = EXCLUDED.timezone,
+            updated_at = CURRENT_TIMESTAMP`,
+		l.userID, p.BodyweightKg, p.HeightCm, p.BirthYear, p.Sex, p.PreferredUnits, p.Timezone,
+	)
+	return err
+}
+
+func (l *loader) insertNutritionTargets(ctx context.Context, t *NutritionTargets) error {
+	_, err := l.tx.ExecContext(ctx, `
+        INSERT INTO nutrition_targets (user_id, calories, protein, carbs, fat, fiber)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (user_id) DO UPDATE SET
+            calories = EXCLUDED.calories,
+            protein = EXCLUDED.protein,
+            carbs = EXCLUDED.carbs,
+            fat = EXCLUDED.fat,
+            fiber = EXCLUDED.fiber,
+            updated_at = CURRENT_TIMESTAMP`,
+		l.userID, t.Calories, t.Protein, t.Carbs, t.Fat, t.Fiber,
+	)
+	return err
+}
+
+func (l *loader) insertBodyMeasurement(ctx context.Context, m *BodyMeasurement) error {
+	_, err := l.tx.ExecContext(ctx, `
+        INSERT INTO body_measurements
+        (user_id, measured_at, bodyweight_kg, body_fat_pct, waist_cm, arms_cm, chest_cm, notes)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		l.userID, m.MeasuredAt, m.BodyweightKg, m.BodyFatPct, m.WaistCm, m.ArmsCm, m.ChestCm, m.Notes,
+	)
+	return err
+}
+
+func (l *loader) insertGym(ctx context.Context, g *Gym) error {
+	var id int
+	err := l.tx.QueryRowContext(ctx,
+		"INSERT INTO gyms (user_id, name, created_at) VALUES ($1, $2, $3) RETURNING id",
+		l.userID, g.Name, g.CreatedAt,
+	).Scan(&id)
+	l.gyms[g.ID] = id
+	return err
+}
+
+func (l *loader) insertGymEquipment(ctx context.Context, e *GymEquipment) error {
+	gymID, err := mapped(l.gyms, e.GymID, "gym")
+	if err != nil {
+		return err
+	}
+	typeID, err := l.lookup(ctx, l.equipmentTypes, "equipment_types", e.EquipmentType)
+	if err != nil {
+		return err
+	}
+	var id int
+	err = l.tx.QueryRowContext(ctx,
+		"INSERT INTO gym_equipment (gym_id, equipment_type_id, weight, notes) VALUES ($1, $2, $3, $4) RETURNING id",
+		gymID, typeID, e.Weight, e.Notes,
+	).Scan(&id)
+	l.equipment[e.ID] = id
+	return err
+}
+
+func (l *loader) insertWorkoutSession(ctx context.Context, s *WorkoutSession) error {
+	gymID, err := mapped(l.gyms, s.GymID, "gym")
+	if err != nil {
+		return err
+	}
+	var id int
+	err = l.tx.QueryRowContext(ctx,
+		"INSERT INTO workout_sessions (user_id, gym_id, created_at, cardio_minutes) VALUES ($1, $2, $3, $4) RETURNING id",
+		l.userID, gymID, s.CreatedAt, s.CardioMinutes,
+	).Scan(&id)
+	l.sessions[s.ID] = id
+	return err
+}
+
+func (l *loader) insertWorkoutExercise(ctx context.Context, w *WorkoutExercise) error {
+	sessionID, err := mapped(l.sessions, w.WorkoutSessionID, "workout session")
+	if err != nil {
+		return err
+	}
+	equipmentID, err := mapped(l.equipment, w.GymEquipmentID, "gym equipment")
+	if err != nil {
+		return err
+	}
+	exerciseID, err := l.lookup(ctx, l.exercises, "exercises", w.Exercise)
+	if err != nil {
+		return err
+	}
+	_, err = l.tx.ExecContext(ctx,
+		`INSERT INTO workout_exercises
+        (workout_session_id, exercise_id, gym_equipment_id, weight, reps, sets, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		sessionID, exerciseID, equipmentID, w.Weight, w.Reps, w.Sets, w.CreatedAt,
+	)
+	return err
+}
+
+func (l *loader) insertPantryItem(ctx context.Context, p *PantryItem) error {
+	var id int
+	err := l.tx.QueryRowContext(ctx,
+		`INSERT INTO pantry_items
+        (user_id, name, quantity, unit, threshold, calories_per_unit, protein_per_unit, created_at, updated_at,
+         carbs_per_unit, fat_per_unit, fiber_per_unit, grams_per_item)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        RETURNING id`,
+		l.userID, p.Name, p.Quantity, p.Unit, p.Threshold, p.CaloriesPerUnit, p.ProteinPerUnit, p.CreatedAt, p.UpdatedAt,
+		p.CarbsPerUnit, p.FatPerUnit, p.FiberPerUnit, p.GramsPerItem,
+	).Scan(&id)
+	l.pantryItems[p.ID] = id
+	return err
+}
+
+func (l *loader) insertPantryBatch(ctx context.Context, b *PantryBatch) error {
+	pantryItemID, err := mapped(l.pantryItems, b.PantryItemID, "pantry item")
+	if err != nil {
+		return err
+	}
+	_, err = l.tx.ExecContext(ctx,
+		"INSERT INTO pantry_batches (pantry_item_id, quantity, expires_on, received_at) VALUES ($1, $2, $3, $4)",
+		pantryItemID, b.Quantity, b.ExpiresOn, b.ReceivedAt,
+	)
+	return err
+}
+
+func (l *loader) insertMeal(ctx context.Context, m *Meal) error {
+	var id int
+	err := l.tx.QueryRowContext(ctx,
+		"INSERT INTO meals (user_id, created_at) VALUES ($1, $2) RETURNING id",
+		l.userID, m.CreatedAt,
+	).Scan(&id)
+	l.meals[m.ID] = id
+	return err
+}
+
+func (l *loader) insertMealIngredient(ctx context.Context, mi *MealIngredient) error {
+	mealID, err := mapped(l.meals, mi.MealID, "meal")
+	if err != nil {
+		return err
+	}
+	pantryItemID, err := mapped(l.pantryItems, mi.PantryItemID, "pantry item")
+	if err != nil {
+		return err
+	}
+	_, err = l.tx.ExecContext(ctx,
+		"INSERT INTO meal_ingredients (meal_id, pantry_item_id, quantity_used) VALUES ($1, $2, $3)",
+		mealID, pantryItemID, mi.QuantityUsed,
+	)
+	return err
+}
+
+func (l *loader) insertMealIngredientBatch(ctx context.Context, mb *MealIngredientBatch) error {
+	mealID, err := mapped(l.meals, mb.MealID, "meal")
+	if err != nil {
+		return err
+	}
+	pantryItemID, err := mapped(l.pantryItems, mb.PantryItemID, "pantry item")
+	if err != nil {
+		return err
+	}
+	_, err = l.tx.ExecContext(ctx,
+		"INSERT INTO meal_ingredient_batches (meal_id, pantry_item_id, expires_on, quantity) VALUES ($1, $2, $3, $4)",
+		mealID, pantryItemID, mb.ExpiresOn, mb.Quantity,
+	)
+	return err
+}
+
+func (l *loader) insertRecipe(ctx context.Context, r *Recipe) error {
+	var id int
+	err := l.tx.QueryRowContext(ctx,
+		`INSERT INTO recipes (user_id, name, servings, notes, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id`,
+		l.userID, r.Name, r.Servings, r.Notes, r.CreatedAt, r.UpdatedAt,
+	).Scan(&id)
+	l.recipes[r.ID] = id
+	return err
+}
+
+func (l *loader) insertRecipeIngredient(ctx context.Context, ri *RecipeIngredient) error {
+	recipeID, err := mapped(l.recipes, ri.RecipeID, "recipe")
+	if err != nil {
+		return err
+	}
+	pantryItemID, err := mapped(l.pantryItems, ri.PantryItemID, "pantry item")
+	if err != nil {
+		return err
+	}
+	_, err = l.tx.ExecContext(ctx,
+		"INSERT INTO recipe_ingredients (recipe_id, pantry_item_id, quantity) VALUES ($1, $2, $3)",
+		recipeID, pantryItemID, ri.Quantity,
+	)
+	return err
+}
+
+func (l *loader) insertMealPlanEntry(ctx context.Context, e *MealPlanEntry) error {
+	var recipeID *int
+	if e.RecipeID != nil {
+		id, err := mapped(l.recipes, *e.RecipeID, "recipe")
+		if err != nil {
+			return err
+		}
+		recipeID = &id
+	}
+	var id int
+	err := l.tx.QueryRowContext(ctx,
+		`INSERT INTO meal_plan_entries (user_id, planned_date, recipe_id, servings, notes, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id`,
+		l.userID, e.PlannedDate, recipeID, e.Servings, e.Notes, e.CreatedAt,
+	).Scan(&id)
+	l.planEntries[e.ID] = id
+	return err
+}
+
+func (l *loader) insertMealPlanIngredient(ctx context.Context, mi *MealPlanIngredient) error {
+	entryID, err := mapped(l.planEntries, mi.EntryID, "meal plan entry")
+	if err != nil {
+		return err
+	}
+	pantryItemID, err := mapped(l.pantryItems, mi.PantryItemID, "pantry item")
+	if err != nil {
+		return err
+	}
+	_, err = l.tx.ExecContext(ctx,
+		"INSERT INTO meal_plan_ingredients (entry_id, pantry_item_id, quantity) VALUES ($1, $2, $3)",
+		entryID, pantryItemID, mi.Quantity,
+	)
+	return err
+}
+
+// lookup resolves a row of a global name-keyed table (exercises,
+// equipment_types), creating it when it does not exist yet.
+func (l *loader) lookup(ctx context.Context, cache map[string]int, table, name string) (int, error) {
+	key := strings.ToLower(name)
+	if id, ok := cache[key]; ok {
+		return id, nil
+	}
+	var id int
+	err := l.tx.QueryRowContext(ctx, "SELECT id FROM "+table+" WHERE LOWER(name) = LOWER($1)", name).Scan(&id)
+	if err == sql.ErrNoRows {
+		err = l.tx.QueryRowContext(ctx, "INSERT INTO "+table+" (name) VALUES ($1) RETURNING id", name).Scan(&id)
+	}
+	if err != nil {
+		return 0, err
+	}
+	cache[key] = id
+	return id, nil
+}
+
+func mapped(ids map[int]int, exported int, what string) (int, error) {
+	id, ok := ids[exported]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown %s id %d", ErrInvalidArchive, what, exported)
+	}
+	return id, nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("%w: expected %q", ErrInvalidArchive, want)
+	}
+	return nil
+}
+
+func decodeZipJSON(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, f.Name, err)
+	}
+	return nil
+}
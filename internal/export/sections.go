@@ -0,0 +1,743 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// section describes one table in an export: how it is read for a user, how
+// a row is written as CSV, and how a row is inserted again on import. Rows
+// reference each other by their exported ids, which the loader remaps to
+// the ids assigned on insert.
+type section struct {
+	name   string
+	table  string
+	header []string
+	query  string
+	scan   func(*sql.Rows) (any, error)
+	record func(any) []string
+	parse  func([]string) (any, error)
+	newRow func() any
+	insert func(*loader, context.Context, any) error
+}
+
+func newSection[T any](
+	name, table string,
+	header []string,
+	query string,
+	scan func(*sql.Rows, *T) error,
+	record func(*T) []string,
+	parse func([]string, *T) error,
+	insert func(*loader, context.Context, *T) error,
+) section {
+	return section{
+		name:   name,
+		table:  table,
+		header: header,
+		query:  query,
+		scan: func(rows *sql.Rows) (any, error) {
+			v := new(T)
+			return v, scan(rows, v)
+		},
+		record: func(v any) []string { return record(v.(*T)) },
+		parse: func(rec []string) (any, error) {
+			v := new(T)
+			if len(rec) != len(header) {
+				return nil, fmt.Errorf("%s: expected %d fields, got %d", name, len(header), len(rec))
+			}
+			if err := parse(rec, v); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			return v, nil
+		},
+		newRow: func() any { return new(T) },
+		insert: func(l *loader, ctx context.Context, v any) error { return insert(l, ctx, v.(*T)) },
+	}
+}
+
+type UserProfile struct {
+	BodyweightKg   *float64 `json:"bodyweight_kg"`
+	HeightCm       *float64 `json:"height_cm"`
+	BirthYear      *int     `json:"birth_year"`
+	Sex            *string  `json:"sex"`
+	PreferredUnits string   `json:"preferred_units"`
+	Timezone       string   `json:"timezone"`
+}
+
+type NutritionTargets struct {
+	Calories *float64 `json:"calories"`
+	Protein  *float64 `json:"protein"`
+	Carbs    *float64 `json:"carbs"`
+	Fat      *float64 `json:"fat"`
+	Fiber    *float64 `json:"fiber"`
+}
+
+type BodyMeasurement struct {
+	ID           int       `json:"id"`
+	MeasuredAt   time.Time `json:"measured_at"`
+	BodyweightKg *float64  `json:"bodyweight_kg"`
+	BodyFatPct   *float64  `json:"body_fat_pct"`
+	WaistCm      *float64  `json:"waist_cm"`
+	ArmsCm       *float64  `json:"arms_cm"`
+	ChestCm      *float64  `json:"chest_cm"`
+	Notes        *string   `json:"notes"`
+}
+
+type Gym struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GymEquipment struct {
+	ID            int      `json:"id"`
+	GymID         int      `json:"gym_id"`
+	EquipmentType string   `json:"equipment_type"`
+	Weight        *float64 `json:"weight"`
+	Notes         *string  `json:"notes"`
+}
+
+type WorkoutSession struct {
+	ID            int       `json:"id"`
+	GymID         int       `json:"gym_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	CardioMinutes *int      `json:"cardio_minutes"`
+}
+
+type WorkoutExercise struct {
+	ID               int       `json:"id"`
+	WorkoutSessionID int       `json:"workout_session_id"`
+	Exercise         string    `json:"exercise"`
+	GymEquipmentID   int       `json:"gym_equipment_id"`
+	Weight           float64   `json:"weight"`
+	Reps             int       `json:"reps"`
+	Sets             int       `json:"sets"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type PantryItem struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	Quantity        float64   `json:"quantity"`
+	Unit            string    `json:"unit"`
+	Threshold       float64   `json:"threshold"`
+	CaloriesPerUnit float64   `json:"calories_per_unit"`
+	ProteinPerUnit  float64   `json:"protein_per_unit"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	CarbsPerUnit    float64   `json:"carbs_per_unit"`
+	FatPerUnit      float64   `json:"fat_per_unit"`
+	FiberPerUnit    float64   `json:"fiber_per_unit"`
+	GramsPerItem    *float64  `json:"grams_per_item"`
+}
+
+type PantryBatch struct {
+	PantryItemID int       `json:"pantry_item_id"`
+	Quantity     float64   `json:"quantity"`
+	ExpiresOn    string    `json:"expires_on"`
+	ReceivedAt   time.Time `json:"received_at"`
+}
+
+type Meal struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MealIngredient struct {
+	MealID       int     `json:"meal_id"`
+	PantryItemID int     `json:"pantry_item_id"`
+	QuantityUsed float64 `json:"quantity_used"`
+}
+
+type MealIngredientBatch struct {
+	MealID       int     `json:"meal_id"`
+	PantryItemID int     `json:"pantry_item_id"`
+	ExpiresOn    string  `json:"expires_on"`
+	Quantity     float64 `json:"quantity"`
+}
+
+type Recipe struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Servings  float64   `json:"servings"`
+	Notes     *string   `json:"notes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RecipeIngredient struct {
+	RecipeID     int     `json:"recipe_id"`
+	PantryItemID int     `json:"pantry_item_id"`
+	Quantity     float64 `json:"quantity"`
+}
+
+type MealPlanEntry struct {
+	ID          int       `json:"id"`
+	PlannedDate string    `json:"planned_date"`
+	RecipeID    *int      `json:"recipe_id"`
+	Servings    *float64  `json:"servings"`
+	Notes       *string   `json:"notes"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type MealPlanIngredient struct {
+	EntryID      int     `json:"entry_id"`
+	PantryItemID int     `json:"pantry_item_id"`
+	Quantity     float64 `json:"quantity"`
+}
+
+// sections lists every exported table in dependency order; import relies on
+// parents being inserted before the rows that reference them.
+var sections = []section{
+	newSection("user_profile", "user_profiles",
+		[]string{"bodyweight_kg", "height_cm", "birth_year", "sex", "preferred_units", "timezone"},
+		`SELECT bodyweight_kg, height_cm, birth_year, sex, preferred_units, timezone
+         FROM user_profiles WHERE user_id = $1`,
+		func(rows *sql.Rows, p *UserProfile) error {
+			return rows.Scan(&p.BodyweightKg, &p.HeightCm, &p.BirthYear, &p.Sex, &p.PreferredUnits, &p.Timezone)
+		},
+		func(p *UserProfile) []string {
+			birthYear := ""
+			if p.BirthYear != nil {
+				birthYear = itoa(*p.BirthYear)
+			}
+			return []string{
+				formatOptFloat(p.BodyweightKg), formatOptFloat(p.HeightCm), birthYear,
+				formatOptString(p.Sex), p.PreferredUnits, p.Timezone,
+			}
+		},
+		func(rec []string, p *UserProfile) (err error) {
+			p.Sex = parseOptString(rec[3])
+			p.PreferredUnits, p.Timezone = rec[4], rec[5]
+			if p.BodyweightKg, err = parseOptFloat(rec[0]); err != nil {
+				return err
+			}
+			if p.HeightCm, err = parseOptFloat(rec[1]); err != nil {
+				return err
+			}
+			if rec[2] != "" {
+				year, err := strconv.Atoi(rec[2])
+				if err != nil {
+					return err
+				}
+				p.BirthYear = &year
+			}
+			return nil
+		},
+		(*loader).insertUserProfile,
+	),
+	newSection("nutrition_targets", "nutrition_targets",
+		[]string{"calories", "protein", "carbs", "fat", "fiber"},
+		`SELECT calories, protein, carbs, fat, fiber
+         FROM nutrition_targets WHERE user_id = $1`,
+		func(rows *sql.Rows, t *NutritionTargets) error {
+			return rows.Scan(&t.Calories, &t.Protein, &t.Carbs, &t.Fat, &t.Fiber)
+		},
+		func(t *NutritionTargets) []string {
+			return []string{
+				formatOptFloat(t.Calories), formatOptFloat(t.Protein), formatOptFloat(t.Carbs),
+				formatOptFloat(t.Fat), formatOptFloat(t.Fiber),
+			}
+		},
+		func(rec []string, t *NutritionTargets) (err error) {
+			for i, f := range []**float64{&t.Calories, &t.Protein, &t.Carbs, &t.Fat, &t.Fiber} {
+				if *f, err = parseOptFloat(rec[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		(*loader).insertNutritionTargets,
+	),
+	newSection("body_measurements", "body_measurements",
+		[]string{"id", "measured_at", "bodyweight_kg", "body_fat_pct", "waist_cm", "arms_cm", "chest_cm", "notes"},
+		`SELECT id, measured_at, bodyweight_kg, body_fat_pct, waist_cm, arms_cm, chest_cm, notes
+         FROM body_measurements WHERE user_id = $1 ORDER BY measured_at, id`,
+		func(rows *sql.Rows, m *BodyMeasurement) error {
+			return rows.Scan(&m.ID, &m.MeasuredAt, &m.BodyweightKg, &m.BodyFatPct, &m.WaistCm, &m.ArmsCm, &m.ChestCm, &m.Notes)
+		},
+		func(m *BodyMeasurement) []string {
+			return []string{
+				itoa(m.ID), formatTime(m.MeasuredAt), formatOptFloat(m.BodyweightKg), formatOptFloat(m.BodyFatPct),
+				formatOptFloat(m.WaistCm), formatOptFloat(m.ArmsCm), formatOptFloat(m.ChestCm), formatOptString(m.Notes),
+			}
+		},
+		func(rec []string, m *BodyMeasurement) (err error) {
+			m.Notes = parseOptString(rec[7])
+			if m.ID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if m.MeasuredAt, err = parseTime(rec[1]); err != nil {
+				return err
+			}
+			for i, f := range []**float64{&m.BodyweightKg, &m.BodyFatPct, &m.WaistCm, &m.ArmsCm, &m.ChestCm} {
+				if *f, err = parseOptFloat(rec[2+i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		(*loader).insertBodyMeasurement,
+	),
+	newSection("gyms", "gyms",
+		[]string{"id", "name", "created_at"},
+		"SELECT id, name, created_at FROM gyms WHERE user_id = $1 ORDER BY id",
+		func(rows *sql.Rows, g *Gym) error {
+			return rows.Scan(&g.ID, &g.Name, &g.CreatedAt)
+		},
+		func(g *Gym) []string {
+			return []string{itoa(g.ID), g.Name, formatTime(g.CreatedAt)}
+		},
+		func(rec []string, g *Gym) (err error) {
+			g.Name = rec[1]
+			if g.ID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			g.CreatedAt, err = parseTime(rec[2])
+			return err
+		},
+		(*loader).insertGym,
+	),
+	newSection("gym_equipment", "gym_equipment",
+		[]string{"id", "gym_id", "equipment_type", "weight", "notes"},
+		`SELECT ge.id, ge.gym_id, et.name, ge.weight, ge.notes
+         FROM gym_equipment ge
+         JOIN gyms g ON g.id = ge.gym_id
+         JOIN equipment_types et ON et.id = ge.equipment_type_id
+         WHERE g.user_id = $1
+         ORDER BY ge.id`,
+		func(rows *sql.Rows, e *GymEquipment) error {
+			return rows.Scan(&e.ID, &e.GymID, &e.EquipmentType, &e.Weight, &e.Notes)
+		},
+		func(e *GymEquipment) []string {
+			return []string{itoa(e.ID), itoa(e.GymID), e.EquipmentType, formatOptFloat(e.Weight), formatOptString(e.Notes)}
+		},
+		func(rec []string, e *GymEquipment) (err error) {
+			e.EquipmentType = rec[2]
+			e.Notes = parseOptString(rec[4])
+			if e.ID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if e.GymID, err = strconv.Atoi(rec[1]); err != nil {
+				return err
+			}
+			e.Weight, err = parseOptFloat(rec[3])
+			return err
+		},
+		(*loader).insertGymEquipment,
+	),
+	newSection("workout_sessions", "workout_sessions",
+		[]string{"id", "gym_id", "created_at", "cardio_minutes"},
+		"SELECT id, gym_id, created_at, cardio_minutes FROM workout_sessions WHERE user_id = $1 ORDER BY id",
+		func(rows *sql.Rows, s *WorkoutSession) error {
+			return rows.Scan(&s.ID, &s.GymID, &s.CreatedAt, &s.CardioMinutes)
+		},
+		func(s *WorkoutSession) []string {
+			return []string{itoa(s.ID), itoa(s.GymID), formatTime(s.CreatedAt), formatOptInt(s.CardioMinutes)}
+		},
+		func(rec []string, s *WorkoutSession) (err error) {
+			if s.ID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if s.GymID, err = strconv.Atoi(rec[1]); err != nil {
+				return err
+			}
+			if s.CreatedAt, err = parseTime(rec[2]); err != nil {
+				return err
+			}
+			s.CardioMinutes, err = parseOptInt(rec[3])
+			return err
+		},
+		(*loader).insertWorkoutSession,
+	),
+	newSection("workout_exercises", "workout_exercises",
+		[]string{"id", "workout_session_id", "exercise", "gym_equipment_id", "weight", "reps", "sets", "created_at"},
+		`SELECT we.id, we.workout_session_id, e.name, we.gym_equipment_id, we.weight, we.reps, we.sets, we.created_at
+         FROM workout_exercises we
+         JOIN workout_sessions ws ON ws.id = we.workout_session_id
+         JOIN exercises e ON e.id = we.exercise_id
+         WHERE ws.user_id = $1
+         ORDER BY we.id`,
+		func(rows *sql.Rows, w *WorkoutExercise) error {
+			return rows.Scan(&w.ID, &w.WorkoutSessionID, &w.Exercise, &w.GymEquipmentID, &w.Weight, &w.Reps, &w.Sets, &w.CreatedAt)
+		},
+		func(w *WorkoutExercise) []string {
+			return []string{
+				itoa(w.ID), itoa(w.WorkoutSessionID), w.Exercise, itoa(w.GymEquipmentID),
+				formatFloat(w.Weight), itoa(w.Reps), itoa(w.Sets), formatTime(w.CreatedAt),
+			}
+		},
+		func(rec []string, w *WorkoutExercise) (err error) {
+			w.Exercise = rec[2]
+			if w.ID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if w.WorkoutSessionID, err = strconv.Atoi(rec[1]); err != nil {
+				return err
+			}
+			if w.GymEquipmentID, err = strconv.Atoi(rec[3]); err != nil {
+				return err
+			}
+			if w.Weight, err = strconv.ParseFloat(rec[4], 64); err != nil {
+				return err
+			}
+			if w.Reps, err = strconv.Atoi(rec[5]); err != nil {
+				return err
+			}
+			if w.Sets, err = strconv.Atoi(rec[6]); err != nil {
+				return err
+			}
+			w.CreatedAt, err = parseTime(rec[7])
+			return err
+		},
+		(*loader).insertWorkoutExercise,
+	),
+	newSection("pantry_items", "pantry_items",
+		[]string{
+			"id", "name", "quantity", "unit", "threshold", "calories_per_unit", "protein_per_unit", "created_at", "updated_at",
+			"carbs_per_unit", "fat_per_unit", "fiber_per_unit", "grams_per_item",
+		},
+		`SELECT id, name, quantity, unit, threshold, calories_per_unit, protein_per_unit, created_at, updated_at,
+                carbs_per_unit, fat_per_unit, fiber_per_unit, grams_per_item
+         FROM pantry_items WHERE user_id = $1 ORDER BY id`,
+		func(rows *sql.Rows, p *PantryItem) error {
+			return rows.Scan(&p.ID, &p.Name, &p.Quantity, &p.Unit, &p.Threshold,
+				&p.CaloriesPerUnit, &p.ProteinPerUnit, &p.CreatedAt, &p.UpdatedAt,
+				&p.CarbsPerUnit, &p.FatPerUnit, &p.FiberPerUnit, &p.GramsPerItem)
+		},
+		func(p *PantryItem) []string {
+			return []string{
+				itoa(p.ID), p.Name, formatFloat(p.Quantity), p.Unit, formatFloat(p.Threshold),
+				formatFloat(p.CaloriesPerUnit), formatFloat(p.ProteinPerUnit),
+				formatTime(p.CreatedAt), formatTime(p.UpdatedAt),
+				formatFloat(p.CarbsPerUnit), formatFloat(p.FatPerUnit), formatFloat(p.FiberPerUnit),
+				formatOptFloat(p.GramsPerItem),
+			}
+		},
+		func(rec []string, p *PantryItem) (err error) {
+			p.Name, p.Unit = rec[1], rec[3]
+			if p.ID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if p.Quantity, err = strconv.ParseFloat(rec[2], 64); err != nil {
+				return err
+			}
+			if p.Threshold, err = strconv.ParseFloat(rec[4], 64); err != nil {
+				return err
+			}
+			if p.CaloriesPerUnit, err = strconv.ParseFloat(rec[5], 64); err != nil {
+				return err
+			}
+			if p.ProteinPerUnit, err = strconv.ParseFloat(rec[6], 64); err != nil {
+				return err
+			}
+			if p.CreatedAt, err = parseTime(rec[7]); err != nil {
+				return err
+			}
+			if p.UpdatedAt, err = parseTime(rec[8]); err != nil {
+				return err
+			}
+			// Archives before version 4 have no carb, fat or fiber columns.
+			if p.CarbsPerUnit, err = parseFloatOrZero(rec[9]); err != nil {
+				return err
+			}
+			if p.FatPerUnit, err = parseFloatOrZero(rec[10]); err != nil {
+				return err
+			}
+			if p.FiberPerUnit, err = parseFloatOrZero(rec[11]); err != nil {
+				return err
+			}
+			p.GramsPerItem, err = parseOptFloat(rec[12])
+			return err
+		},
+		(*loader).insertPantryItem,
+	),
+	newSection("pantry_batches", "pantry_batches",
+		[]string{"pantry_item_id", "quantity", "expires_on", "received_at"},
+		`SELECT b.pantry_item_id, b.quantity, to_char(b.expires_on, 'YYYY-MM-DD'), b.received_at
+         FROM pantry_batches b
+         JOIN pantry_items p ON p.id = b.pantry_item_id
+         WHERE p.user_id = $1
+         ORDER BY b.pantry_item_id, b.expires_on, b.received_at, b.id`,
+		func(rows *sql.Rows, b *PantryBatch) error {
+			return rows.Scan(&b.PantryItemID, &b.Quantity, &b.ExpiresOn, &b.ReceivedAt)
+		},
+		func(b *PantryBatch) []string {
+			return []string{itoa(b.PantryItemID), formatFloat(b.Quantity), b.ExpiresOn, formatTime(b.ReceivedAt)}
+		},
+		func(rec []string, b *PantryBatch) (err error) {
+			b.ExpiresOn = rec[2]
+			if b.PantryItemID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if b.Quantity, err = strconv.ParseFloat(rec[1], 64); err != nil {
+				return err
+			}
+			b.ReceivedAt, err = parseTime(rec[3])
+			return err
+		},
+		(*loader).insertPantryBatch,
+	),
+	newSection("meals", "meals",
+		[]string{"id", "created_at"},
+		"SELECT id, created_at FROM meals WHERE user_id = $1 ORDER BY id",
+		func(rows *sql.Rows, m *Meal) error {
+			return rows.Scan(&m.ID, &m.CreatedAt)
+		},
+		func(m *Meal) []string {
+			return []string{itoa(m.ID), formatTime(m.CreatedAt)}
+		},
+		func(rec []string, m *Meal) (err error) {
+			if m.ID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			m.CreatedAt, err = parseTime(rec[1])
+			return err
+		},
+		(*loader).insertMeal,
+	),
+	newSection("meal_ingredients", "meal_ingredients",
+		[]string{"meal_id", "pantry_item_id", "quantity_used"},
+		`SELECT mi.meal_id, mi.pantry_item_id, mi.quantity_used
+         FROM meal_ingredients mi
+         JOIN meals m ON m.id = mi.meal_id
+         WHERE m.user_id = $1
+         ORDER BY mi.meal_id, mi.pantry_item_id`,
+		func(rows *sql.Rows, mi *MealIngredient) error {
+			return rows.Scan(&mi.MealID, &mi.PantryItemID, &mi.QuantityUsed)
+		},
+		func(mi *MealIngredient) []string {
+			return []string{itoa(mi.MealID), itoa(mi.PantryItemID), formatFloat(mi.QuantityUsed)}
+		},
+		func(rec []string, mi *MealIngredient) (err error) {
+			if mi.MealID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if mi.PantryItemID, err = strconv.Atoi(rec[1]); err != nil {
+				return err
+			}
+			mi.QuantityUsed, err = strconv.ParseFloat(rec[2], 64)
+			return err
+		},
+		(*loader).insertMealIngredient,
+	),
+	newSection("meal_ingredient_batches", "meal_ingredient_batches",
+		[]string{"meal_id", "pantry_item_id", "expires_on", "quantity"},
+		`SELECT mb.meal_id, mb.pantry_item_id, to_char(mb.expires_on, 'YYYY-MM-DD'), mb.quantity
+         FROM meal_ingredient_batches mb
+         JOIN meals m ON m.id = mb.meal_id
+         WHERE m.user_id = $1
+         ORDER BY mb.meal_id, mb.pantry_item_id, mb.expires_on`,
+		func(rows *sql.Rows, mb *MealIngredientBatch) error {
+			return rows.Scan(&mb.MealID, &mb.PantryItemID, &mb.ExpiresOn, &mb.Quantity)
+		},
+		func(mb *MealIngredientBatch) []string {
+			return []string{itoa(mb.MealID), itoa(mb.PantryItemID), mb.ExpiresOn, formatFloat(mb.Quantity)}
+		},
+		func(rec []string, mb *MealIngredientBatch) (err error) {
+			mb.ExpiresOn = rec[2]
+			if mb.MealID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if mb.PantryItemID, err = strconv.Atoi(rec[1]); err != nil {
+				return err
+			}
+			mb.Quantity, err = strconv.ParseFloat(rec[3], 64)
+			return err
+		},
+		(*loader).insertMealIngredientBatch,
+	),
+	newSection("recipes", "recipes",
+		[]string{"id", "name", "servings", "notes", "created_at", "updated_at"},
+		`SELECT id, name, servings, notes, created_at, updated_at
+         FROM recipes WHERE user_id = $1 ORDER BY id`,
+		func(rows *sql.Rows, r *Recipe) error {
+			return rows.Scan(&r.ID, &r.Name, &r.Servings, &r.Notes, &r.CreatedAt, &r.UpdatedAt)
+		},
+		func(r *Recipe) []string {
+			return []string{
+				itoa(r.ID), r.Name, formatFloat(r.Servings), formatOptString(r.Notes),
+				formatTime(r.CreatedAt), formatTime(r.UpdatedAt),
+			}
+		},
+		func(rec []string, r *Recipe) (err error) {
+			r.Name, r.Notes = rec[1], parseOptString(rec[3])
+			if r.ID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if r.Servings, err = strconv.ParseFloat(rec[2], 64); err != nil {
+				return err
+			}
+			if r.CreatedAt, err = parseTime(rec[4]); err != nil {
+				return err
+			}
+			r.UpdatedAt, err = parseTime(rec[5])
+			return err
+		},
+		(*loader).insertRecipe,
+	),
+	newSection("recipe_ingredients", "recipe_ingredients",
+		[]string{"recipe_id", "pantry_item_id", "quantity"},
+		`SELECT ri.recipe_id, ri.pantry_item_id, ri.quantity
+         FROM recipe_ingredients ri
+         JOIN recipes r ON r.id = ri.recipe_id
+         WHERE r.user_id = $1
+         ORDER BY ri.recipe_id, ri.pantry_item_id`,
+		func(rows *sql.Rows, ri *RecipeIngredient) error {
+			return rows.Scan(&ri.RecipeID, &ri.PantryItemID, &ri.Quantity)
+		},
+		func(ri *RecipeIngredient) []string {
+			return []string{itoa(ri.RecipeID), itoa(ri.PantryItemID), formatFloat(ri.Quantity)}
+		},
+		func(rec []string, ri *RecipeIngredient) (err error) {
+			if ri.RecipeID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if ri.PantryItemID, err = strconv.Atoi(rec[1]); err != nil {
+				return err
+			}
+			ri.Quantity, err = strconv.ParseFloat(rec[2], 64)
+			return err
+		},
+		(*loader).insertRecipeIngredient,
+	),
+	newSection("meal_plan_entries", "meal_plan_entries",
+		[]string{"id", "planned_date", "recipe_id", "servings", "notes", "created_at"},
+		`SELECT id, to_char(planned_date, 'YYYY-MM-DD'), recipe_id, servings, notes, created_at
+         FROM meal_plan_entries WHERE user_id = $1 ORDER BY id`,
+		func(rows *sql.Rows, e *MealPlanEntry) error {
+			return rows.Scan(&e.ID, &e.PlannedDate, &e.RecipeID, &e.Servings, &e.Notes, &e.CreatedAt)
+		},
+		func(e *MealPlanEntry) []string {
+			return []string{
+				itoa(e.ID), e.PlannedDate, formatOptInt(e.RecipeID), formatOptFloat(e.Servings),
+				formatOptString(e.Notes), formatTime(e.CreatedAt),
+			}
+		},
+		func(rec []string, e *MealPlanEntry) (err error) {
+			e.PlannedDate, e.Notes = rec[1], parseOptString(rec[4])
+			if e.ID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if e.RecipeID, err = parseOptInt(rec[2]); err != nil {
+				return err
+			}
+			if e.Servings, err = parseOptFloat(rec[3]); err != nil {
+				return err
+			}
+			e.CreatedAt, err = parseTime(rec[5])
+			return err
+		},
+		(*loader).insertMealPlanEntry,
+	),
+	newSection("meal_plan_ingredients", "meal_plan_ingredients",
+		[]string{"entry_id", "pantry_item_id", "quantity"},
+		`SELECT mi.entry_id, mi.pantry_item_id, mi.quantity
+         FROM meal_plan_ingredients mi
+         JOIN meal_plan_entries e ON e.id = mi.entry_id
+         WHERE e.user_id = $1
+         ORDER BY mi.entry_id, mi.pantry_item_id`,
+		func(rows *sql.Rows, mi *MealPlanIngredient) error {
+			return rows.Scan(&mi.EntryID, &mi.PantryItemID, &mi.Quantity)
+		},
+		func(mi *MealPlanIngredient) []string {
+			return []string{itoa(mi.EntryID), itoa(mi.PantryItemID), formatFloat(mi.Quantity)}
+		},
+		func(rec []string, mi *MealPlanIngredient) (err error) {
+			if mi.EntryID, err = strconv.Atoi(rec[0]); err != nil {
+				return err
+			}
+			if mi.PantryItemID, err = strconv.Atoi(rec[1]); err != nil {
+				return err
+			}
+			mi.Quantity, err = strconv.ParseFloat(rec[2], 64)
+			return err
+		},
+		(*loader).insertMealPlanIngredient,
+	),
+}
+
+func sectionByName(name string) (section, bool) {
+	for _, s := range sections {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return section{}, false
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatOptFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return formatFloat(*f)
+}
+
+func formatOptInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return itoa(*i)
+}
+
+func formatOptString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+func parseOptFloat(s string) (*float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func parseFloatOrZero(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func parseOptInt(s string) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func parseOptString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,55 @@
+package foods
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidBarcode = errors.New("barcode must be a valid EAN-8, UPC-A, EAN-13 or GTIN-14 code")
+
+// NormalizeBarcode validates an EAN-8, UPC-A, EAN-13 or GTIN-14 code and
+// returns it in the form stored in foods.barcode: UPC-A codes gain the
+// leading zero that makes them EAN-13, and GTIN-14 codes with a zero
+// indicator digit drop it, so the same product scans identically whichever
+// form the dump or the scanner uses. Spaces and dashes are ignored.
+func NormalizeBarcode(s string) (string, error) {
+	s = strings.NewReplacer(" ", "", "-", "").Replace(s)
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidBarcode
+		}
+	}
+
+	switch len(s) {
+	case 8, 13:
+	case 12:
+		s = "0" + s
+	case 14:
+		if s[0] != '0' {
+			break
+		}
+		s = s[1:]
+	default:
+		return "", ErrInvalidBarcode
+	}
+
+	if !validCheckDigit(s) {
+		return "", ErrInvalidBarcode
+	}
+	return s, nil
+}
+
+// validCheckDigit applies the GS1 mod-10 check: digits are weighted 3 and 1
+// alternately from the right, starting with the digit before the check
+// digit.
+func validCheckDigit(s string) bool {
+	sum := 0
+	for i := len(s) - 2; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if (len(s)-2-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10-sum%10)%10 == int(s[len(s)-1]-'0')
+}
@@ -0,0 +1,324 @@
+// Package foods maintains the shared food catalogue: loading Open Food
+// Facts, USDA and similar dumps into the foods table, normalising barcodes
+// and converting catalogue nutrition into pantry units.
+package foods
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+type Format string
+
+const (
+	FormatAuto  Format = "auto"
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+var ErrInvalidFile = errors.New("invalid food dump")
+
+// kcalPerKJ converts energy given only in kilojoules.
+const kcalPerKJ = 1 / 4.184
+
+// fieldAliases lists, for each catalogue field, the column names (or JSON
+// keys) used by the dumps we know about, in order of preference: Open Food
+// Facts first, then USDA FoodData Central, then plain names.
+var fieldAliases = map[string][]string{
+	"source_id":    {"code", "fdc_id", "source_id", "id"},
+	"barcode":      {"code", "gtin_upc", "barcode", "ean", "upc"},
+	"name":         {"product_name", "description", "name"},
+	"brand":        {"brands", "brand_owner", "brand_name", "brand"},
+	"basis_unit":   {"basis_unit", "unit"},
+	"serving_size": {"serving_quantity", "serving_size_g", "serving_size"},
+	"calories":     {"energy-kcal_100g", "energy_kcal_100g", "calories_per_100", "calories", "kcal"},
+	"energy_kj":    {"energy-kj_100g", "energy_100g", "energy_kj"},
+	"protein":      {"proteins_100g", "protein_per_100", "protein"},
+	"carbs":        {"carbohydrates_100g", "carbs_per_100", "carbohydrate", "carbs"},
+	"fat":          {"fat_100g", "fat_per_100", "total_fat", "fat"},
+	"fiber":        {"fiber_100g", "fiber_per_100", "dietary_fiber", "fiber", "fibre"},
+}
+
+type ImportOptions struct {
+	// Source names the dataset, such as "off" or "usda". Rows are keyed by
+	// source and source id, so importing a newer dump of the same source
+	// updates existing foods instead of duplicating them.
+	Source string
+	Format Format
+}
+
+// ParseFormat accepts the user-facing format names, treating an empty
+// string as auto-detection.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "", FormatAuto:
+		return FormatAuto, nil
+	case FormatCSV, FormatJSONL:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported food dump format %q", s)
+	}
+}
+
+// Import streams a CSV (comma, semicolon or tab separated) or JSON Lines
+// dump into the foods table in a single transaction. Rows without a name or
+// an identifier are skipped; barcodes that fail validation are dropped but
+// the food is kept.
+func Import(ctx context.Context, db *sql.DB, r io.Reader, opts ImportOptions) (*models.FoodImportReport, error) {
+	source := strings.TrimSpace(opts.Source)
+	if source == "" || len(source) > 50 {
+		return nil, errors.New("source must be between 1 and 50 characters")
+	}
+
+	br := bufio.NewReaderSize(r, 1<<16)
+	first, err := br.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	first = bytes.TrimPrefix(first, []byte("\xef\xbb\xbf"))
+
+	format := opts.Format
+	if format == FormatAuto || format == "" {
+		format = FormatCSV
+		if t := bytes.TrimSpace(first); len(t) > 0 && t[0] == '{' {
+			format = FormatJSONL
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+        INSERT INTO foods
+        (source, source_id, barcode, name, brand, basis_unit, serving_size,
+         calories_per_100, protein_per_100, carbs_per_100, fat_per_100, fiber_per_100)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+        ON CONFLICT (source, source_id) DO UPDATE SET
+            barcode = EXCLUDED.barcode,
+            name = EXCLUDED.name,
+            brand = EXCLUDED.brand,
+            basis_unit = EXCLUDED.basis_unit,
+            serving_size = EXCLUDED.serving_size,
+            calories_per_100 = EXCLUDED.calories_per_100,
+            protein_per_100 = EXCLUDED.protein_per_100,
+            carbs_per_100 = EXCLUDED.carbs_per_100,
+            fat_per_100 = EXCLUDED.fat_per_100,
+            fiber_per_100 = EXCLUDED.fiber_per_100,
+            updated_at = CURRENT_TIMESTAMP`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	report := &models.FoodImportReport{Source: source, Format: string(format)}
+	load := func(rec map[string]string) error {
+		report.Rows++
+		f, invalidBarcode := toFood(rec)
+		if invalidBarcode {
+			report.InvalidBarcodes++
+		}
+		if f.Name == "" || f.SourceID == "" {
+			report.Skipped++
+			return nil
+		}
+		_, err := stmt.ExecContext(ctx, source, f.SourceID, f.Barcode, f.Name, f.Brand, f.BasisUnit, f.ServingSize,
+			f.CaloriesPer100, f.ProteinPer100, f.CarbsPer100, f.FatPer100, f.FiberPer100)
+		if err != nil {
+			return fmt.Errorf("row %d (%s): %w", report.Rows, f.SourceID, err)
+		}
+		report.Imported++
+		return nil
+	}
+
+	if format == FormatJSONL {
+		err = readJSONL(br, load)
+	} else {
+		err = readCSV(br, sniffDelimiter(first), load)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func readCSV(br *bufio.Reader, comma rune, load func(map[string]string) error) error {
+	if head, err := br.Peek(3); err == nil && bytes.Equal(head, []byte("\xef\xbb\xbf")) {
+		br.Discard(3)
+	}
+
+	reader := csv.NewReader(br)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%w: reading header: %v", ErrInvalidFile, err)
+	}
+	cols := make([]string, len(header))
+	for i, h := range header {
+		cols[i] = strings.ToLower(strings.TrimSpace(h))
+	}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		rec := make(map[string]string, len(cols))
+		for i, v := range row {
+			if i < len(cols) {
+				rec[cols[i]] = v
+			}
+		}
+		if err := load(rec); err != nil {
+			return err
+		}
+	}
+}
+
+// readJSONL reads one JSON object per line. Nested "nutriments" objects, as
+// found in Open Food Facts dumps, are flattened into the record.
+func readJSONL(br *bufio.Reader, load func(map[string]string) error) error {
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(b)) > 0 {
+			var obj map[string]any
+			dec := json.NewDecoder(bytes.NewReader(b))
+			dec.UseNumber()
+			if err := dec.Decode(&obj); err != nil {
+				return fmt.Errorf("%w: line %d: %v", ErrInvalidFile, line, err)
+			}
+			rec := map[string]string{}
+			flatten(rec, obj)
+			if n, ok := obj["nutriments"].(map[string]any); ok {
+				flatten(rec, n)
+			}
+			if err := load(rec); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func flatten(rec map[string]string, obj map[string]any) {
+	for k, v := range obj {
+		switch v := v.(type) {
+		case string:
+			rec[strings.ToLower(k)] = v
+		case json.Number:
+			rec[strings.ToLower(k)] = v.String()
+		case bool:
+			rec[strings.ToLower(k)] = strconv.FormatBool(v)
+		}
+	}
+}
+
+// toFood maps a record onto a catalogue row using fieldAliases. The second
+// result reports a barcode that was present but invalid.
+func toFood(rec map[string]string) (models.Food, bool) {
+	get := func(field string) string {
+		for _, alias := range fieldAliases[field] {
+			if v := strings.TrimSpace(rec[alias]); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	num := func(field string) *float64 {
+		f, err := strconv.ParseFloat(strings.ReplaceAll(get(field), ",", "."), 64)
+		if err != nil || f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil
+		}
+		return &f
+	}
+
+	f := models.Food{
+		SourceID:       truncate(get("source_id"), 100),
+		Name:           truncate(get("name"), 255),
+		BasisUnit:      "g",
+		CaloriesPer100: num("calories"),
+		ProteinPer100:  num("protein"),
+		CarbsPer100:    num("carbs"),
+		FatPer100:      num("fat"),
+		FiberPer100:    num("fiber"),
+	}
+	if brand := truncate(get("brand"), 255); brand != "" {
+		f.Brand = &brand
+	}
+	if u := strings.ToLower(get("basis_unit")); u == "ml" || u == "l" {
+		f.BasisUnit = "ml"
+	}
+	if s := num("serving_size"); s != nil && *s > 0 {
+		f.ServingSize = s
+	}
+	if f.CaloriesPer100 == nil {
+		if kj := num("energy_kj"); kj != nil {
+			kcal := *kj * kcalPerKJ
+			f.CaloriesPer100 = &kcal
+		}
+	}
+
+	invalid := false
+	if raw := get("barcode"); raw != "" {
+		if code, err := NormalizeBarcode(raw); err == nil {
+			f.Barcode = &code
+		} else {
+			invalid = true
+		}
+	}
+	if f.SourceID == "" && f.Barcode != nil {
+		f.SourceID = *f.Barcode
+	}
+	return f, invalid
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+func sniffDelimiter(sample []byte) rune {
+	line := sample
+	if i := bytes.IndexByte(sample, '\n'); i >= 0 {
+		line = sample[:i]
+	}
+	best, count := ',', bytes.Count(line, []byte(","))
+	for _, d := range []rune{';', '\t'} {
+		if n := bytes.Count(line, []byte(string(d))); n > count {
+			best, count = d, n
+		}
+	}
+	return best
+}
@@ -0,0 +1,46 @@
+package foods
+
+import (
+	"errors"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+var (
+	ErrUnitMismatch  = errors.New("unit does not match the food's basis: use g or kg for foods measured by weight, ml or l for foods measured by volume")
+	ErrNoServingSize = errors.New("food has no serving size, so it cannot be counted by item")
+)
+
+// PerUnit converts a food's nutrition per 100 g or 100 ml into nutrition
+// per pantry unit (g, kg, ml, l or item, where an item is one serving).
+// Nutrients missing from the catalogue count as zero.
+func PerUnit(f models.Food, unit string) (models.Macros, error) {
+	var factor float64
+	switch {
+	case unit == "item":
+		if f.ServingSize == nil {
+			return models.Macros{}, ErrNoServingSize
+		}
+		factor = *f.ServingSize / 100
+	case f.BasisUnit == "g" && unit == "g", f.BasisUnit == "ml" && unit == "ml":
+		factor = 1.0 / 100
+	case f.BasisUnit == "g" && unit == "kg", f.BasisUnit == "ml" && unit == "l":
+		factor = 10
+	default:
+		return models.Macros{}, ErrUnitMismatch
+	}
+
+	scale := func(v *float64) float64 {
+		if v == nil {
+			return 0
+		}
+		return *v * factor
+	}
+	return models.Macros{
+		Calories: scale(f.CaloriesPer100),
+		Protein:  scale(f.ProteinPer100),
+		Carbs:    scale(f.CarbsPer100),
+		Fat:      scale(f.FatPer100),
+		Fiber:    scale(f.FiberPer100),
+	}, nil
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/analytics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetBodyMeasurements godoc
+// @Summary Get body measurements
+// @Description Retrieve a user's bodyweight, body fat and tape measurements within a date range, oldest first
+// @Tags BodyMeasurements
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param from query string false "First day (YYYY-MM-DD), defaults to 89 days before to"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to today in the user's time zone"
+// @Success 200 {array} models.BodyMeasurement
+// @Failure 400 {object} models.ErrorResponse "User ID is required or invalid date"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /body-measurements [get]
+func HandleGetBodyMeasurements(users store.UserStore, measurements *service.BodyMeasurementService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	loc, ok := userLocation(users, c, userID)
+	if !ok {
+		return
+	}
+	from, to, ok := parseDateRange(c, loc, 90)
+	if !ok {
+		return
+	}
+
+	entries, err := measurements.List(ctx, userID, from, to)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, entries)
+}
+
+// HandleCreateBodyMeasurement godoc
+// @Summary Log body measurement
+// @Description Record bodyweight, body fat percentage and/or tape measurements. measured_at defaults to now.
+// @Tags BodyMeasurements
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param measurement body models.BodyMeasurementInput true "Measurement details"
+// @Success 201 {object} models.BodyMeasurement
+// @Failure 400 {object} models.ErrorResponse "User ID is required or invalid input"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /body-measurements [post]
+func HandleCreateBodyMeasurement(measurements *service.BodyMeasurementService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.BodyMeasurementInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	m, err := measurements.Create(ctx, userID, input)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, m)
+}
+
+// HandleUpdateBodyMeasurement godoc
+// @Summary Update body measurement
+// @Description Replace a logged body measurement
+// @Tags BodyMeasurements
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the measurement"
+// @Param user_id query int true "ID of the user"
+// @Param measurement body models.BodyMeasurementInput true "Measurement details"
+// @Success 200 {object} models.BodyMeasurement
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format or invalid input"
+// @Failure 404 {object} models.ErrorResponse "Measurement not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /body-measurements/{id} [put]
+func HandleUpdateBodyMeasurement(measurements *service.BodyMeasurementService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.BodyMeasurementInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	m, err := measurements.Update(ctx, userID, id, input)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, m)
+}
+
+// HandleDeleteBodyMeasurement godoc
+// @Summary Delete body measurement
+// @Description Delete a logged body measurement
+// @Tags BodyMeasurements
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the measurement"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.SuccessResponse "Measurement deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Measurement not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /body-measurements/{id} [delete]
+func HandleDeleteBodyMeasurement(measurements *service.BodyMeasurementService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := measurements.Delete(ctx, userID, id); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Measurement deleted successfully"})
+}
+
+// HandleGetBodyMeasurementTrend godoc
+// @Summary Get bodyweight trend
+// @Description Return raw measurements together with an exponentially weighted moving average of bodyweight, its weekly rate of change, and per-week training volume for comparison. Given an exercise_id, each week also carries the best estimated 1RM on that exercise.
+// @Tags BodyMeasurements
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param from query string false "First day (YYYY-MM-DD), defaults to 89 days before to"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to today in the user's time zone"
+// @Param alpha query number false "Smoothing factor per day (0-1]" default(0.1)
+// @Param exercise_id query int false "Exercise to report the weekly best estimated 1RM for"
+// @Success 200 {object} models.BodyMeasurementTrend
+// @Failure 400 {object} models.ErrorResponse "User ID is required or invalid input"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /body-measurements/trend [get]
+func HandleGetBodyMeasurementTrend(users store.UserStore, measurements *service.BodyMeasurementService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	alpha := analytics.DefaultAlpha
+	if s := c.Query("alpha"); s != "" {
+		a, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, "alpha must be a number in (0, 1]")
+			return
+		}
+		alpha = a
+	}
+
+	var exerciseID int
+	if s := c.Query("exercise_id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil || id <= 0 {
+			writeError(c, http.StatusBadRequest, "Invalid exercise ID format")
+			return
+		}
+		exerciseID = id
+	}
+
+	loc, ok := userLocation(users, c, userID)
+	if !ok {
+		return
+	}
+	from, to, ok := parseDateRange(c, loc, 90)
+	if !ok {
+		return
+	}
+
+	result, err := measurements.Trend(ctx, userID, loc, from, to, alpha, exerciseID)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
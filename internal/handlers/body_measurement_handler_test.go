@@ -0,0 +1,44 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/memory"
+)
+
+func TestBodyMeasurements(t *testing.T) {
+	s := newServer(t)
+
+	w := s.do(http.MethodPost, "/api/body-measurements", models.BodyMeasurementInput{})
+	wantError(t, w, http.StatusBadRequest, "invalid_request")
+
+	weight := 82.4
+	w = s.do(http.MethodPut, "/api/body-measurements/999", models.BodyMeasurementInput{BodyweightKg: &weight})
+	wantError(t, w, http.StatusNotFound, "not_found")
+}
+
+func TestBodyMeasurementTrendWeeks(t *testing.T) {
+	s := newServer(t)
+	s.db.Training[s.userID] = memory.Training{Weeks: map[string]models.BodyweightWeek{
+		"2025-03-17": {Sessions: 2, Sets: 12, Volume: 5400, BestEstimated1RM: ptr(120.0)},
+	}}
+	const path = "/api/body-measurements/trend?from=2025-03-10&to=2025-03-23"
+
+	// Without an exercise the weeks carry no estimated 1RM.
+	got := decode[models.BodyMeasurementTrend](t, s.do(http.MethodGet, path, nil), http.StatusOK)
+	if len(got.Weeks) != 2 || got.Weeks[1].Sessions != 2 || got.Weeks[1].BestEstimated1RM != nil {
+		t.Errorf("weeks = %+v, want two with the second's training and no 1RM", got.Weeks)
+	}
+
+	got = decode[models.BodyMeasurementTrend](t, s.do(http.MethodGet, path+"&exercise_id=3", nil), http.StatusOK)
+	if got.ExerciseID == nil || *got.ExerciseID != 3 {
+		t.Errorf("exercise ID = %v, want 3", got.ExerciseID)
+	}
+	if w := got.Weeks[1]; w.BestEstimated1RM == nil || *w.BestEstimated1RM != 120 {
+		t.Errorf("best estimated 1RM = %v, want 120", w.BestEstimated1RM)
+	}
+
+	wantError(t, s.do(http.MethodGet, path+"&exercise_id=squat", nil), http.StatusBadRequest, "invalid_request")
+}
@@ -1,11 +1,11 @@
 package handlers
 
 import (
-	"database/sql"
 	"net/http"
 	"strconv"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,55 +17,21 @@ import (
 // @Produce json
 // @Param gymId path int true "ID of the gym"
 // @Success 200 {array} models.GymEquipmentWithDetails
+// @Failure 400 {object} models.ErrorResponse "Invalid gym ID format"
 // @Failure 404 {object} models.ErrorResponse "No equipments found for this gym"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /gyms/{gymId}/equipment [get]
-func HandleGetAllGymEquipments(db *sql.DB, c *gin.Context) {
-	gymID := c.Param("gymId")
-
-	query := `
-			SELECT 
-					ge.id, 
-					ge.gym_id, 
-					ge.equipment_type_id, 
-					et.name AS equipment_name,
-					ge.weight,
-					ge.notes
-			FROM gym_equipment ge
-			JOIN equipment_types et ON ge.equipment_type_id = et.id
-			WHERE ge.gym_id = $1
-	`
-	rows, err := db.Query(query, gymID)
+func HandleGetAllGymEquipments(equipment *service.EquipmentService, c *gin.Context) {
+	ctx := c.Request.Context()
+	gymID, err := strconv.Atoi(c.Param("gymId"))
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, "Invalid gym ID format")
 		return
 	}
-	defer rows.Close()
-
-	var equipments []models.GymEquipmentWithDetails
-	for rows.Next() {
-		var equipment models.GymEquipmentWithDetails
-		if err := rows.Scan(
-			&equipment.ID,
-			&equipment.GymID,
-			&equipment.EquipmentTypeID,
-			&equipment.EquipmentName,
-			&equipment.Weight,
-			&equipment.Notes,
-		); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		equipments = append(equipments, equipment)
-	}
 
-	if err := rows.Err(); err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if len(equipments) == 0 {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No equipments found for this gym"})
+	equipments, err := equipment.ListByGym(ctx, gymID)
+	if err != nil {
+		writeServiceError(c, err)
 		return
 	}
 
@@ -84,48 +50,26 @@ func HandleGetAllGymEquipments(db *sql.DB, c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse "Invalid gym ID format or invalid input"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /gyms/{gymId}/equipment [post]
-func HandleAddNewGymEquipment(db *sql.DB, c *gin.Context) {
-	gymId := c.Param("gymId")
-
-	gymIDInt, err := strconv.Atoi(gymId)
+func HandleAddNewGymEquipment(equipment *service.EquipmentService, c *gin.Context) {
+	ctx := c.Request.Context()
+	gymID, err := strconv.Atoi(c.Param("gymId"))
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid gym ID format"})
+		writeError(c, http.StatusBadRequest, "Invalid gym ID format")
 		return
 	}
 
 	var input models.GymEquipmentInput
-	if err := c.BindJSON(&input); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
 		return
 	}
 
-	query := `
-                INSERT INTO gym_equipment (gym_id, equipment_type_id, weight, notes)
-                VALUES ($1, $2, $3, $4)
-                RETURNING id
-        `
-
-	var id int
-	err = db.QueryRow(
-		query,
-		gymIDInt,
-		input.EquipmentTypeID,
-		input.Weight,
-		input.Notes,
-	).Scan(&id)
+	newEquipment, err := equipment.Create(ctx, gymID, input)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err)
 		return
 	}
 
-	newEquipment := models.GymEquipment{
-		ID:              id,
-		GymID:           gymIDInt,
-		EquipmentTypeID: input.EquipmentTypeID,
-		Weight:          input.Weight,
-		Notes:           input.Notes,
-	}
-
 	c.IndentedJSON(http.StatusCreated, newEquipment)
 }
 
@@ -141,47 +85,21 @@ func HandleAddNewGymEquipment(db *sql.DB, c *gin.Context) {
 // @Failure 404 {object} models.ErrorResponse "Equipment not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /equipment/{id} [get]
-func HandleGetGymEquipment(db *sql.DB, c *gin.Context) {
-	id := c.Param("id")
-
-	idInt, err := strconv.Atoi(id)
+func HandleGetGymEquipment(equipment *service.EquipmentService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid equipment ID format"})
+		writeError(c, http.StatusBadRequest, "Invalid equipment ID format")
 		return
 	}
 
-	query := `
-			SELECT 
-					ge.id, 
-					ge.gym_id, 
-					ge.equipment_type_id, 
-					et.name AS equipment_name,
-					ge.weight,
-					ge.notes
-			FROM gym_equipment ge
-			JOIN equipment_types et ON ge.equipment_type_id = et.id
-			WHERE ge.id = $1
-	`
-
-	var equipment models.GymEquipmentWithDetails
-	err = db.QueryRow(query, idInt).Scan(
-		&equipment.ID,
-		&equipment.GymID,
-		&equipment.EquipmentTypeID,
-		&equipment.EquipmentName,
-		&equipment.Weight,
-		&equipment.Notes,
-	)
-
-	if err == sql.ErrNoRows {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Equipment not found"})
-		return
-	} else if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	item, err := equipment.Get(ctx, id)
+	if err != nil {
+		writeServiceError(c, err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, equipment)
+	c.IndentedJSON(http.StatusOK, item)
 }
 
 // HandleUpdateGymEquipment godoc
@@ -197,90 +115,23 @@ func HandleGetGymEquipment(db *sql.DB, c *gin.Context) {
 // @Failure 404 {object} models.ErrorResponse "Equipment not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /equipment/{id} [put]
-func HandleUpdateGymEquipment(db *sql.DB, c *gin.Context) {
-	id := c.Param("id")
-
-	idInt, err := strconv.Atoi(id)
+func HandleUpdateGymEquipment(equipment *service.EquipmentService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid equipment ID format"})
+		writeError(c, http.StatusBadRequest, "Invalid equipment ID format")
 		return
 	}
 
 	var input models.GymEquipmentInput
-	if err := c.BindJSON(&input); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	var exists bool
-	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM gym_equipment WHERE id = $1)", idInt).Scan(&exists)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if !exists {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Equipment not found"})
-		return
-	}
-
-	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM equipment_types WHERE id = $1)", input.EquipmentTypeID).Scan(&exists)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
 		return
 	}
 
-	if !exists {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Equipment type not found"})
-		return
-	}
-
-	query := `
-			UPDATE gym_equipment 
-			SET equipment_type_id = $1, weight = $2, notes = $3
-			WHERE id = $4
-			RETURNING gym_id
-	`
-
-	var gymID int
-	err = db.QueryRow(
-		query,
-		input.EquipmentTypeID,
-		input.Weight,
-		input.Notes,
-		idInt,
-	).Scan(&gymID)
-
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	detailsQuery := `
-			SELECT 
-					ge.id, 
-					ge.gym_id, 
-					ge.equipment_type_id, 
-					et.name AS equipment_name,
-					ge.weight,
-					ge.notes
-			FROM gym_equipment ge
-			JOIN equipment_types et ON ge.equipment_type_id = et.id
-			WHERE ge.id = $1
-	`
-
-	var updatedEquipment models.GymEquipmentWithDetails
-	err = db.QueryRow(detailsQuery, idInt).Scan(
-		&updatedEquipment.ID,
-		&updatedEquipment.GymID,
-		&updatedEquipment.EquipmentTypeID,
-		&updatedEquipment.EquipmentName,
-		&updatedEquipment.Weight,
-		&updatedEquipment.Notes,
-	)
-
+	updatedEquipment, err := equipment.Update(ctx, id, input)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err)
 		return
 	}
 
@@ -300,48 +151,18 @@ func HandleUpdateGymEquipment(db *sql.DB, c *gin.Context) {
 // @Failure 409 {object} models.ErrorResponse "Cannot delete equipment that is in use"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /equipment/{id} [delete]
-func HandleDeleteGymEquipment(db *sql.DB, c *gin.Context) {
-	id := c.Param("id")
-
-	idInt, err := strconv.Atoi(id)
-	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid equipment ID format"})
-		return
-	}
-
-	var inUse bool
-	err = db.QueryRow(`
-			SELECT EXISTS(
-					SELECT 1 FROM workout_equipment 
-					WHERE gym_equipment_id = $1
-			)`, idInt).Scan(&inUse)
-
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if inUse {
-		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Cannot delete equipment that is used in workout sessions"})
-		return
-	}
-
-	result, err := db.Exec("DELETE FROM gym_equipment WHERE id = $1", idInt)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	rowsAffected, err := result.RowsAffected()
+func HandleDeleteGymEquipment(equipment *service.EquipmentService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, "Invalid equipment ID format")
 		return
 	}
 
-	if rowsAffected == 0 {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Equipment not found"})
+	if err := equipment.Delete(ctx, id); err != nil {
+		writeServiceError(c, err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, gin.H{"message": "Equipment removed successfully"})
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Equipment removed successfully"})
 }
@@ -1,11 +1,11 @@
 package handlers
 
 import (
-	"database/sql"
 	"net/http"
 	"strconv"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,25 +18,15 @@ import (
 // @Success 200 {array} models.EquipmentType
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /equipment-types [get]
-func HandleGetAllEquipmentTypes(db *sql.DB, c *gin.Context) {
-	rows, err := db.Query("SELECT id, name FROM equipment_types ORDER BY name")
+func HandleGetAllEquipmentTypes(equipmentTypes *service.EquipmentTypeService, c *gin.Context) {
+	ctx := c.Request.Context()
+	list, err := equipmentTypes.List(ctx)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err)
 		return
 	}
-	defer rows.Close()
-
-	equipmentTypes := []models.EquipmentType{}
-	for rows.Next() {
-		var equipmentType models.EquipmentType
-		if err := rows.Scan(&equipmentType.ID, &equipmentType.Name); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		equipmentTypes = append(equipmentTypes, equipmentType)
-	}
 
-	c.IndentedJSON(http.StatusOK, equipmentTypes)
+	c.IndentedJSON(http.StatusOK, list)
 }
 
 // HandleGetEquipmentType godoc
@@ -51,26 +41,21 @@ func HandleGetAllEquipmentTypes(db *sql.DB, c *gin.Context) {
 // @Failure 404 {object} models.ErrorResponse "Equipment type not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /equipment-types/{id} [get]
-func HandleGetEquipmentType(db *sql.DB, c *gin.Context) {
+func HandleGetEquipmentType(equipmentTypes *service.EquipmentTypeService, c *gin.Context) {
+	ctx := c.Request.Context()
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
-	var equipmentType models.EquipmentType
-	err = db.QueryRow("SELECT id, name FROM equipment_types WHERE id = $1", id).Scan(
-		&equipmentType.ID, &equipmentType.Name)
-
-	if err == sql.ErrNoRows {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Equipment type not found"})
-		return
-	} else if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	item, err := equipmentTypes.Get(ctx, id)
+	if err != nil {
+		writeServiceError(c, err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, equipmentType)
+	c.IndentedJSON(http.StatusOK, item)
 }
 
 // HandleCreateEquipmentType godoc
@@ -85,40 +70,21 @@ func HandleGetEquipmentType(db *sql.DB, c *gin.Context) {
 // @Failure 409 {object} models.ErrorResponse "Equipment type with this name already exists"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /equipment-types [post]
-func HandleCreateEquipmentType(db *sql.DB, c *gin.Context) {
+func HandleCreateEquipmentType(equipmentTypes *service.EquipmentTypeService, c *gin.Context) {
+	ctx := c.Request.Context()
 	var input models.EquipmentTypeInput
-	if err := c.BindJSON(&input); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	var exists bool
-	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM equipment_types WHERE name = $1)", input.Name).Scan(&exists)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if exists {
-		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Equipment type with this name already exists"})
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
 		return
 	}
 
-	query := "INSERT INTO equipment_types (name) VALUES ($1) RETURNING id"
-
-	var id int
-	err = db.QueryRow(query, input.Name).Scan(&id)
+	created, err := equipmentTypes.Create(ctx, input.Name)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err)
 		return
 	}
 
-	newEquipmentType := models.EquipmentType{
-		ID:   id,
-		Name: input.Name,
-	}
-
-	c.IndentedJSON(http.StatusCreated, newEquipmentType)
+	c.IndentedJSON(http.StatusCreated, created)
 }
 
 // HandleUpdateEquipmentType godoc
@@ -135,55 +101,27 @@ func HandleCreateEquipmentType(db *sql.DB, c *gin.Context) {
 // @Failure 409 {object} models.ErrorResponse "Equipment type with this name already exists"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /equipment-types/{id} [put]
-func HandleUpdateEquipmentType(db *sql.DB, c *gin.Context) {
+func HandleUpdateEquipmentType(equipmentTypes *service.EquipmentTypeService, c *gin.Context) {
+	ctx := c.Request.Context()
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	var input models.EquipmentTypeInput
-	if err := c.BindJSON(&input); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
 		return
 	}
 
-	var exists bool
-	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM equipment_types WHERE id = $1)", id).Scan(&exists)
+	updated, err := equipmentTypes.Update(ctx, id, input.Name)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if !exists {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Equipment type not found"})
+		writeServiceError(c, err)
 		return
 	}
 
-	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM equipment_types WHERE name = $1 AND id != $2)",
-		input.Name, id).Scan(&exists)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if exists {
-		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Equipment type with this name already exists"})
-		return
-	}
-
-	_, err = db.Exec("UPDATE equipment_types SET name = $1 WHERE id = $2", input.Name, id)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	updatedEquipmentType := models.EquipmentType{
-		ID:   id,
-		Name: input.Name,
-	}
-
-	c.IndentedJSON(http.StatusOK, updatedEquipmentType)
+	c.IndentedJSON(http.StatusOK, updated)
 }
 
 // HandleDeleteEquipmentType godoc
@@ -199,41 +137,18 @@ func HandleUpdateEquipmentType(db *sql.DB, c *gin.Context) {
 // @Failure 409 {object} models.ErrorResponse "Cannot delete equipment type that is in use"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /equipment-types/{id} [delete]
-func HandleDeleteEquipmentType(db *sql.DB, c *gin.Context) {
+func HandleDeleteEquipmentType(equipmentTypes *service.EquipmentTypeService, c *gin.Context) {
+	ctx := c.Request.Context()
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
-	var inUse bool
-	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM gym_equipment WHERE equipment_type_id = $1)", id).Scan(&inUse)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if inUse {
-		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Cannot delete equipment type that is in use"})
-		return
-	}
-
-	result, err := db.Exec("DELETE FROM equipment_types WHERE id = $1", id)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
-	if rowsAffected == 0 {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Equipment type not found"})
+	if err := equipmentTypes.Delete(ctx, id); err != nil {
+		writeServiceError(c, err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, gin.H{"message": "Equipment type deleted successfully"})
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Equipment type deleted successfully"})
 }
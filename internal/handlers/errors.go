@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+	"strings"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/requestid"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+)
+
+// Error codes are part of the API: clients may switch on them, so existing
+// codes must not be renamed.
+const (
+	codeInvalidRequest    = "invalid_request"
+	codeInvalidJSON       = "invalid_json"
+	codeValidationFailed  = "validation_failed"
+	codeUnauthorized      = "unauthorized"
+	codeForbidden         = "forbidden"
+	codeNotFound          = "not_found"
+	codeConflict          = "conflict"
+	codeDuplicate         = "duplicate"
+	codeInUse             = "in_use"
+	codeInvalidReference  = "invalid_reference"
+	codeUnprocessable     = "unprocessable"
+	codeInsufficientStock = "insufficient_stock"
+	codeInternal          = "internal_error"
+)
+
+// statusCodes gives the code used for an error written with just a status.
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          codeInvalidRequest,
+	http.StatusUnauthorized:        codeUnauthorized,
+	http.StatusForbidden:           codeForbidden,
+	http.StatusNotFound:            codeNotFound,
+	http.StatusConflict:            codeConflict,
+	http.StatusUnprocessableEntity: codeUnprocessable,
+}
+
+// serviceStatus maps the kinds of service errors to HTTP status codes.
+var serviceStatus = map[service.Kind]int{
+	service.NotFound:     http.StatusNotFound,
+	service.Conflict:     http.StatusConflict,
+	service.Validation:   http.StatusBadRequest,
+	service.Unauthorized: http.StatusUnauthorized,
+	service.Forbidden:    http.StatusForbidden,
+}
+
+func init() {
+	// Report validation failures by the JSON names of fields.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return f.Name
+			}
+			return name
+		})
+	}
+}
+
+// errorResponse builds the envelope for an error on the current request.
+func errorResponse(c *gin.Context, code, message string) models.ErrorResponse {
+	return models.ErrorResponse{
+		Error:     message,
+		Code:      code,
+		RequestID: requestid.Get(c),
+		TraceID:   tracing.TraceID(c.Request.Context()),
+	}
+}
+
+// writeError writes an error response whose code follows from its status.
+func writeError(c *gin.Context, status int, message string) {
+	code, ok := statusCodes[status]
+	if !ok {
+		code = codeInternal
+	}
+	c.IndentedJSON(status, errorResponse(c, code, message))
+}
+
+// writeBindError writes the 400 response for a request body that failed to
+// bind, listing each field that failed validation.
+func writeBindError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	resp := errorResponse(c, codeValidationFailed, "Invalid input")
+	switch {
+	case errors.As(err, &validationErrs):
+		for _, fe := range validationErrs {
+			resp.Fields = append(resp.Fields, models.FieldError{Field: fieldPath(fe), Message: fieldMessage(fe)})
+		}
+	case errors.As(err, &typeErr):
+		resp.Fields = []models.FieldError{{Field: typeErr.Field, Message: "must be " + jsonType(typeErr.Type)}}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		resp.Code, resp.Error = codeInvalidJSON, "Request body is not valid JSON"
+	case errors.Is(err, io.EOF):
+		resp.Code, resp.Error = codeInvalidJSON, "Request body is required"
+	default:
+		resp.Code, resp.Error = codeInvalidRequest, err.Error()
+	}
+	c.IndentedJSON(http.StatusBadRequest, resp)
+}
+
+// writeFieldError writes the 400 response for a query parameter or other
+// input that was rejected outside of binding.
+func writeFieldError(c *gin.Context, field, message string) {
+	resp := errorResponse(c, codeValidationFailed, "Invalid input")
+	resp.Fields = []models.FieldError{{Field: field, Message: message}}
+	c.IndentedJSON(http.StatusBadRequest, resp)
+}
+
+// writeServiceError writes the response for an error returned by the
+// service layer.
+func writeServiceError(c *gin.Context, err error) {
+	status, ok := serviceStatus[service.KindOf(err)]
+	if !ok {
+		writeInternalError(c, err)
+		return
+	}
+	writeError(c, status, err.Error())
+}
+
+// writeInternalError writes the response for an unexpected error. Violated
+// database constraints are the client's doing and are reported as such;
+// anything else is attached to the request for the access log and answered
+// with a generic 500 so that database details do not leak.
+func writeInternalError(c *gin.Context, err error) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Name() {
+		case "unique_violation":
+			c.IndentedJSON(http.StatusConflict, errorResponse(c, codeDuplicate, "A record with these values already exists"))
+			return
+		case "foreign_key_violation":
+			if strings.Contains(pqErr.Detail, "still referenced") {
+				c.IndentedJSON(http.StatusConflict, errorResponse(c, codeInUse, "The record is still in use"))
+			} else {
+				c.IndentedJSON(http.StatusUnprocessableEntity, errorResponse(c, codeInvalidReference, "A referenced record does not exist"))
+			}
+			return
+		}
+	}
+	c.Error(err)
+	c.IndentedJSON(http.StatusInternalServerError, errorResponse(c, codeInternal, "Internal server error"))
+}
+
+// HandlePanic answers a request whose handler panicked with the 500
+// envelope. The panic and its stack are logged with the request's ID.
+func HandlePanic(c *gin.Context, recovered any) {
+	slog.ErrorContext(c.Request.Context(), "panic while handling request",
+		"request_id", requestid.Get(c),
+		"method", c.Request.Method,
+		"route", c.FullPath(),
+		"panic", fmt.Sprint(recovered),
+		"stack", string(debug.Stack()))
+	c.Error(fmt.Errorf("panic: %v", recovered))
+	c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(c, codeInternal, "Internal server error"))
+}
+
+// HandleNoRoute answers requests for unknown paths with the error envelope.
+func HandleNoRoute(c *gin.Context) {
+	writeError(c, http.StatusNotFound, "Route not found")
+}
+
+// fieldPath returns the JSON path of a field, dropping the name of the
+// top-level struct.
+func fieldPath(fe validator.FieldError) string {
+	_, path, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		return fe.Field()
+	}
+	return path
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	case "min":
+		if fe.Kind() == reflect.String {
+			return "must have at least " + fe.Param() + " character(s)"
+		} else if fe.Kind() == reflect.Slice {
+			return "must have at least " + fe.Param() + " element(s)"
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		if fe.Kind() == reflect.String {
+			return "must have at most " + fe.Param() + " character(s)"
+		} else if fe.Kind() == reflect.Slice {
+			return "must have at most " + fe.Param() + " element(s)"
+		}
+		return "must be at most " + fe.Param()
+	}
+	return fmt.Sprintf("failed the %s check", fe.Tag())
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Pointer:
+		return jsonType(t.Elem())
+	}
+	return "an object"
+}
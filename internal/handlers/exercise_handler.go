@@ -1,11 +1,11 @@
 package handlers
 
 import (
-	"database/sql"
 	"net/http"
 	"strconv"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,30 +18,15 @@ import (
 // @Success 200 {array} models.Exercise
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /exercises [get]
-func HandleGetAllExercises(db *sql.DB, c *gin.Context) {
-	rows, err := db.Query("SELECT id, name FROM exercises ORDER BY name")
+func HandleGetAllExercises(exercises *service.ExerciseService, c *gin.Context) {
+	ctx := c.Request.Context()
+	list, err := exercises.List(ctx)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err)
 		return
 	}
-	defer rows.Close()
 
-	var exercises []models.Exercise
-	for rows.Next() {
-		var exercise models.Exercise
-		if err := rows.Scan(&exercise.ID, &exercise.Name); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		exercises = append(exercises, exercise)
-	}
-
-	if err := rows.Err(); err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.IndentedJSON(http.StatusOK, exercises)
+	c.IndentedJSON(http.StatusOK, list)
 }
 
 // HandleCreateExercise godoc
@@ -56,38 +41,21 @@ func HandleGetAllExercises(db *sql.DB, c *gin.Context) {
 // @Failure 409 {object} models.ErrorResponse "Exercise with this name already exists"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /exercises [post]
-func HandleCreateExercise(db *sql.DB, c *gin.Context) {
+func HandleCreateExercise(exercises *service.ExerciseService, c *gin.Context) {
+	ctx := c.Request.Context()
 	var input models.ExerciseInput
-	if err := c.BindJSON(&input); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	var exists bool
-	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM exercises WHERE name = $1)", input.Name).Scan(&exists)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if exists {
-		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Exercise with this name already exists"})
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
 		return
 	}
 
-	var id int
-	err = db.QueryRow("INSERT INTO exercises (name) VALUES ($1) RETURNING id", input.Name).Scan(&id)
+	created, err := exercises.Create(ctx, input.Name)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err)
 		return
 	}
 
-	newExercise := models.Exercise{
-		ID:   id,
-		Name: input.Name,
-	}
-
-	c.IndentedJSON(http.StatusCreated, newExercise)
+	c.IndentedJSON(http.StatusCreated, created)
 }
 
 // HandleUpdateExercise godoc
@@ -104,55 +72,27 @@ func HandleCreateExercise(db *sql.DB, c *gin.Context) {
 // @Failure 409 {object} models.ErrorResponse "Exercise with this name already exists"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /exercises/{id} [put]
-func HandleUpdateExercise(db *sql.DB, c *gin.Context) {
+func HandleUpdateExercise(exercises *service.ExerciseService, c *gin.Context) {
+	ctx := c.Request.Context()
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	var input models.ExerciseInput
-	if err := c.BindJSON(&input); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
 		return
 	}
 
-	var exists bool
-	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM exercises WHERE id = $1)", id).Scan(&exists)
+	updated, err := exercises.Update(ctx, id, input.Name)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if !exists {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Exercise not found"})
+		writeServiceError(c, err)
 		return
 	}
 
-	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM exercises WHERE name = $1 AND id != $2)",
-		input.Name, id).Scan(&exists)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if exists {
-		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Exercise with this name already exists"})
-		return
-	}
-
-	_, err = db.Exec("UPDATE exercises SET name = $1 WHERE id = $2", input.Name, id)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	updatedExercise := models.Exercise{
-		ID:   id,
-		Name: input.Name,
-	}
-
-	c.IndentedJSON(http.StatusOK, updatedExercise)
+	c.IndentedJSON(http.StatusOK, updated)
 }
 
 // HandleDeleteExercise godoc
@@ -168,41 +108,18 @@ func HandleUpdateExercise(db *sql.DB, c *gin.Context) {
 // @Failure 409 {object} models.ErrorResponse "Cannot delete exercise that is used in workouts"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /exercises/{id} [delete]
-func HandleDeleteExercise(db *sql.DB, c *gin.Context) {
+func HandleDeleteExercise(exercises *service.ExerciseService, c *gin.Context) {
+	ctx := c.Request.Context()
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
-	var inUse bool
-	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM workout_exercises WHERE exercise_id = $1)", id).Scan(&inUse)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if inUse {
-		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Cannot delete exercise that is used in workouts"})
-		return
-	}
-
-	result, err := db.Exec("DELETE FROM exercises WHERE id = $1", id)
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
-	if rowsAffected == 0 {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Exercise not found"})
+	if err := exercises.Delete(ctx, id); err != nil {
+		writeServiceError(c, err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, gin.H{"message": "Exercise deleted successfully"})
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Exercise deleted successfully"})
 }
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/export"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleExportUserData godoc
+// @Summary Export user data
+// @Description Stream all of a user's profile, gyms, equipment, workouts, pantry items and meals as a versioned JSON document or a zip of CSV files
+// @Tags Export
+// @Produce json
+// @Produce application/zip
+// @Param user_id query int true "ID of the user"
+// @Param format query string false "Export format (json or csv)" default(json)
+// @Success 200 {file} file "Export document"
+// @Failure 400 {object} models.ErrorResponse "User ID is required or invalid format"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /export [get]
+func HandleExportUserData(db *sql.DB, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var exists bool
+	err := db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", userID).Scan(&exists)
+	if err != nil {
+		writeInternalError(c, err)
+		return
+	}
+	if !exists {
+		writeError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	stamp := time.Now().UTC().Format("20060102")
+	switch format := c.DefaultQuery("format", "json"); format {
+	case "json":
+		c.Header("Content-Type", "application/json")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="gym-tracker-export-%d-%s.json"`, userID, stamp))
+		err = export.WriteJSON(ctx, db, userID, c.Writer)
+	case "csv":
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="gym-tracker-export-%d-%s.zip"`, userID, stamp))
+		err = export.WriteCSVZip(ctx, db, userID, c.Writer)
+	default:
+		writeError(c, http.StatusBadRequest, "Invalid export format")
+		return
+	}
+
+	if err != nil && !c.Writer.Written() {
+		c.Header("Content-Disposition", "")
+		writeInternalError(c, err)
+		return
+	}
+	if err != nil {
+		// The body has already been partially streamed, so the status code
+		// can no longer change. The document is left unterminated (no
+		// closing brace or zip directory), which clients detect as invalid.
+		c.Error(err)
+		c.Abort()
+	}
+}
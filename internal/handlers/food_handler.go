@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleSearchFoods godoc
+// @Summary Search foods
+// @Description Full-text search of the food catalogue by name and brand. Every word must match, and the last word may be a prefix so results can follow typing.
+// @Tags Foods
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text"
+// @Param limit query int false "Maximum number of results (default 20, at most 100)"
+// @Success 200 {array} models.Food
+// @Failure 400 {object} models.ErrorResponse "Search text is required or invalid limit"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /foods [get]
+func HandleSearchFoods(foodService *service.FoodService, c *gin.Context) {
+	ctx := c.Request.Context()
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Limit must be between 1 and 100")
+		return
+	}
+
+	results, err := foodService.Search(ctx, c.Query("q"), limit)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, results)
+}
+
+// HandleGetFood godoc
+// @Summary Get food
+// @Description Retrieve a single food from the catalogue
+// @Tags Foods
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the food"
+// @Success 200 {object} models.Food
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Food not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /foods/{id} [get]
+func HandleGetFood(foodService *service.FoodService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	f, err := foodService.Get(ctx, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, f)
+}
+
+// HandleGetFoodByBarcode godoc
+// @Summary Look up food by barcode
+// @Description Find the food with an EAN-8, UPC-A, EAN-13 or GTIN-14 barcode. UPC-A and EAN-13 forms of the same code match each other. When several datasets list the barcode the most recently imported entry is returned.
+// @Tags Foods
+// @Accept json
+// @Produce json
+// @Param code path string true "Barcode digits"
+// @Success 200 {object} models.Food
+// @Failure 400 {object} models.ErrorResponse "Invalid barcode"
+// @Failure 404 {object} models.ErrorResponse "Food not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /foods/barcode/{code} [get]
+func HandleGetFoodByBarcode(foodService *service.FoodService, c *gin.Context) {
+	ctx := c.Request.Context()
+	f, err := foodService.FindByBarcode(ctx, c.Param("code"))
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, f)
+}
+
+// HandleCreatePantryItemFromFood godoc
+// @Summary Create pantry item from food
+// @Description Add a pantry item whose nutrition per unit is copied from a catalogue food. Foods measured by weight can be stocked in g or kg, foods measured by volume in ml or l, and foods with a serving size by item (one serving each).
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param item body models.PantryItemFromFoodInput true "Food and stock details"
+// @Success 201 {object} models.PantryItem
+// @Failure 400 {object} models.ErrorResponse "User ID is required, invalid input, unknown food or unit not available for the food"
+// @Failure 409 {object} models.ErrorResponse "Pantry item with this name already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry/from-food [post]
+func HandleCreatePantryItemFromFood(foodService *service.FoodService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PantryItemFromFoodInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	p, err := foodService.CreatePantryItem(ctx, userID, input)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, p)
+}
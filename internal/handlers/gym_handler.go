@@ -1,10 +1,11 @@
 package handlers
 
 import (
-	"database/sql"
 	"net/http"
+	"strconv"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,27 +16,17 @@ import (
 // @Accept json
 // @Produce json
 // @Success 200 {array} models.Gym
-// @Failure 500 {object} models.ErrorResponse "Error fetching data"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /gyms [get]
-func HandleGetGyms(db *sql.DB, c *gin.Context) {
-	rows, err := db.Query("SELECT id, user_id, name, created_at FROM gyms")
+func HandleGetGyms(gyms *service.GymService, c *gin.Context) {
+	ctx := c.Request.Context()
+	list, err := gyms.List(ctx)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Error fetching data"})
+		writeServiceError(c, err)
 		return
 	}
-	defer rows.Close()
-
-	var gyms []models.Gym
-	for rows.Next() {
-		var gym models.Gym
-		if err := rows.Scan(&gym.ID, &gym.UserID, &gym.Name, &gym.CreatedAt); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		gyms = append(gyms, gym)
-	}
 
-	c.JSON(http.StatusOK, gyms)
+	c.JSON(http.StatusOK, list)
 }
 
 // HandleCreateGym godoc
@@ -49,22 +40,17 @@ func HandleGetGyms(db *sql.DB, c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse "Invalid input or gym name is required"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /gyms [post]
-func HandleCreateGym(db *sql.DB, c *gin.Context) {
+func HandleCreateGym(gyms *service.GymService, c *gin.Context) {
+	ctx := c.Request.Context()
 	var gym models.Gym
-	if err := c.BindJSON(&gym); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBindJSON(&gym); err != nil {
+		writeBindError(c, err)
 		return
 	}
 
-	if gym.Name == "" {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Gym name is required"})
-		return
-	}
-
-	query := "INSERT INTO gyms (user_id, name) VALUES ($1, $2) RETURNING id, created_at"
-	err := db.QueryRow(query, gym.UserID, gym.Name).Scan(&gym.ID, &gym.CreatedAt)
+	gym, err := gyms.Create(ctx, gym)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err)
 		return
 	}
 
@@ -79,22 +65,21 @@ func HandleCreateGym(db *sql.DB, c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID of the gym"
 // @Success 200 {object} models.Gym
+// @Failure 400 {object} models.ErrorResponse "Invalid gym ID format"
 // @Failure 404 {object} models.ErrorResponse "Gym not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /gyms/{id} [get]
-func HandleGetGymByID(db *sql.DB, c *gin.Context) {
-	id := c.Param("id")
-
-	query := "SELECT id, user_id, name, created_at FROM gyms WHERE id=$1"
-	row := db.QueryRow(query, id)
+func HandleGetGymByID(gyms *service.GymService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid gym ID format")
+		return
+	}
 
-	var gym models.Gym
-	if err := row.Scan(&gym.ID, &gym.UserID, &gym.Name, &gym.CreatedAt); err != nil {
-		if err == sql.ErrNoRows {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Gym not found"})
-		} else {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+	gym, err := gyms.Get(ctx, id)
+	if err != nil {
+		writeServiceError(c, err)
 		return
 	}
 
@@ -109,41 +94,25 @@ func HandleGetGymByID(db *sql.DB, c *gin.Context) {
 // @Produce json
 // @Param user_id path int true "ID of the user"
 // @Success 200 {array} models.Gym
+// @Failure 400 {object} models.ErrorResponse "Invalid user ID format"
 // @Failure 404 {object} models.ErrorResponse "No gyms found for this user"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /users/{user_id}/gyms [get]
-func HandleGetGymsByUserID(db *sql.DB, c *gin.Context) {
-	id := c.Param("user_id")
-
-	query := "SELECT id, user_id, name, created_at FROM gyms WHERE user_id=$1"
-	rows, err := db.Query(query, id)
+func HandleGetGymsByUserID(gyms *service.GymService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, err := strconv.Atoi(c.Param("user_id"))
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	defer rows.Close()
-
-	var gyms []models.Gym
-	for rows.Next() {
-		var gym models.Gym
-		if err := rows.Scan(&gym.ID, &gym.UserID, &gym.Name, &gym.CreatedAt); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		gyms = append(gyms, gym)
-	}
-
-	if err := rows.Err(); err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
-	if len(gyms) == 0 {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No gyms found for this user"})
+	list, err := gyms.ListByUser(ctx, userID)
+	if err != nil {
+		writeServiceError(c, err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, gyms)
+	c.IndentedJSON(http.StatusOK, list)
 }
 
 // HandleUpdateGym godoc
@@ -155,26 +124,27 @@ func HandleGetGymsByUserID(db *sql.DB, c *gin.Context) {
 // @Param id path int true "ID of the gym to update"
 // @Param gym body models.Gym true "Updated gym details"
 // @Success 200 {object} models.Gym
-// @Failure 400 {object} models.ErrorResponse "Invalid input"
+// @Failure 400 {object} models.ErrorResponse "Invalid gym ID format or invalid input"
 // @Failure 404 {object} models.ErrorResponse "Gym not found"
-// @Failure 500 {object} models.ErrorResponse "Failed to update gym"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /gyms/{id} [put]
-func HandleUpdateGym(db *sql.DB, c *gin.Context) {
-	id := c.Param("id")
+func HandleUpdateGym(gyms *service.GymService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid gym ID format")
+		return
+	}
 
 	var gym models.Gym
-	if err := c.BindJSON(&gym); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBindJSON(&gym); err != nil {
+		writeBindError(c, err)
+		return
 	}
 
-	query := "UPDATE gyms SET user_id=$2, name=$3 WHERE id=$1 RETURNING id, user_id, name, created_at"
-	err := db.QueryRow(query, id, gym.UserID, gym.Name).Scan(&gym.ID, &gym.UserID, &gym.Name, &gym.CreatedAt)
+	gym, err = gyms.Update(ctx, id, gym)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Gym not found"})
-		} else {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update gym"})
-		}
+		writeServiceError(c, err)
 		return
 	}
 
@@ -188,31 +158,23 @@ func HandleUpdateGym(db *sql.DB, c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID of the gym to delete"
-// @Success 200 {string} string "Deleted gym successfully"
+// @Success 200 {object} models.SuccessResponse "Gym deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid gym ID format"
 // @Failure 404 {object} models.ErrorResponse "Gym not found"
-// @Failure 500 {object} models.ErrorResponse "Failed to delete gym"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /gyms/{id} [delete]
-func HandleDeleteGym(db *sql.DB, c *gin.Context) {
-	id := c.Param("id")
-
-	var gym models.Gym
-	getQuery := "SELECT id, user_id, name, created_at FROM gyms WHERE id=$1"
-	err := db.QueryRow(getQuery, id).Scan(&gym.ID, &gym.UserID, &gym.Name, &gym.CreatedAt)
+func HandleDeleteGym(gyms *service.GymService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Gym not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		writeError(c, http.StatusBadRequest, "Invalid gym ID format")
 		return
 	}
 
-	query := "DELETE FROM gyms WHERE id=$1"
-	_, err = db.Exec(query, id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete gym"})
+	if err := gyms.Delete(ctx, id); err != nil {
+		writeServiceError(c, err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, "Deleted gym sucessfully")
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Gym deleted successfully"})
 }
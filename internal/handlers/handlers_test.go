@@ -0,0 +1,162 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/handlers"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/memory"
+	"github.com/gin-gonic/gin"
+)
+
+// testNow is the fixed clock of the in-memory stores.
+var testNow = time.Date(2025, 3, 17, 12, 0, 0, 0, time.UTC)
+
+// server wires the handlers to in-memory stores the way api/routes wires
+// them to Postgres, with one user already created.
+type server struct {
+	t      *testing.T
+	db     *memory.DB
+	router *gin.Engine
+	userID int
+}
+
+func newServer(t *testing.T) *server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db := memory.New()
+	db.Now = func() time.Time { return testNow }
+	users := memory.NewUserStore(db)
+	profileStore := memory.NewProfileStore(db)
+	pantryStore := memory.NewPantryStore(db)
+	recipeStore := memory.NewRecipeStore(db)
+	pantryItems := service.NewPantryService(pantryStore)
+	meals := service.NewMealService(memory.NewMealStore(db))
+	recipes := service.NewRecipeService(recipeStore)
+	plans := service.NewMealPlanService(memory.NewMealPlanStore(db), recipeStore)
+	list := service.NewShoppingListService(pantryStore, memory.NewShoppingListStore(db))
+	measurementStore := memory.NewBodyMeasurementStore(db)
+	measurements := service.NewBodyMeasurementService(measurementStore)
+	nutrition := service.NewNutritionService(users, profileStore, measurementStore, memory.NewNutritionStore(db))
+	foods := service.NewFoodService(memory.NewFoodStore(db), pantryItems)
+	profiles := service.NewProfileService(users, profileStore)
+
+	user, err := users.Create(context.Background(), "user@example.com", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	r.GET("/api/pantry", func(c *gin.Context) { handlers.HandleGetPantryItems(pantryItems, c) })
+	r.POST("/api/pantry", func(c *gin.Context) { handlers.HandleCreatePantryItem(pantryItems, c) })
+	r.POST("/api/pantry/from-food", func(c *gin.Context) { handlers.HandleCreatePantryItemFromFood(foods, c) })
+	r.GET("/api/pantry/:id", func(c *gin.Context) { handlers.HandleGetPantryItem(pantryItems, c) })
+	r.PUT("/api/pantry/:id", func(c *gin.Context) { handlers.HandleUpdatePantryItem(pantryItems, c) })
+	r.POST("/api/pantry/:id/restock", func(c *gin.Context) { handlers.HandleRestockPantryItem(pantryItems, c) })
+	r.DELETE("/api/pantry/:id", func(c *gin.Context) { handlers.HandleDeletePantryItem(pantryItems, c) })
+
+	r.GET("/api/meals", func(c *gin.Context) { handlers.HandleGetMeals(users, meals, c) })
+	r.POST("/api/meals", func(c *gin.Context) { handlers.HandleCreateMeal(meals, c) })
+	r.GET("/api/meals/:id", func(c *gin.Context) { handlers.HandleGetMeal(meals, c) })
+	r.PUT("/api/meals/:id", func(c *gin.Context) { handlers.HandleUpdateMeal(meals, c) })
+	r.DELETE("/api/meals/:id", func(c *gin.Context) { handlers.HandleDeleteMeal(meals, c) })
+
+	r.GET("/api/recipes/:id", func(c *gin.Context) { handlers.HandleGetRecipe(recipes, c) })
+	r.POST("/api/recipes", func(c *gin.Context) { handlers.HandleCreateRecipe(recipes, c) })
+	r.PUT("/api/recipes/:id", func(c *gin.Context) { handlers.HandleUpdateRecipe(recipes, c) })
+	r.DELETE("/api/recipes/:id", func(c *gin.Context) { handlers.HandleDeleteRecipe(recipes, c) })
+	r.POST("/api/recipes/:id/log", func(c *gin.Context) { handlers.HandleLogRecipe(recipes, c) })
+
+	r.POST("/api/meal-plan", func(c *gin.Context) { handlers.HandleCreateMealPlanEntry(plans, c) })
+	r.GET("/api/meal-plan/requirements", func(c *gin.Context) { handlers.HandleGetMealPlanRequirements(users, plans, c) })
+	r.PUT("/api/meal-plan/:id", func(c *gin.Context) { handlers.HandleUpdateMealPlanEntry(plans, c) })
+
+	r.GET("/api/shopping-list", func(c *gin.Context) { handlers.HandleGetShoppingList(users, list, c) })
+	r.POST("/api/shopping-list/items", func(c *gin.Context) { handlers.HandleAddShoppingListItem(list, c) })
+	r.PUT("/api/shopping-list/pantry/:id/check", func(c *gin.Context) { handlers.HandleCheckShoppingListPantryItem(list, c) })
+	r.POST("/api/shopping-list/purchase", func(c *gin.Context) { handlers.HandlePurchaseShoppingList(users, list, c) })
+
+	r.POST("/api/body-measurements", func(c *gin.Context) { handlers.HandleCreateBodyMeasurement(measurements, c) })
+	r.PUT("/api/body-measurements/:id", func(c *gin.Context) { handlers.HandleUpdateBodyMeasurement(measurements, c) })
+	r.GET("/api/body-measurements/trend", func(c *gin.Context) { handlers.HandleGetBodyMeasurementTrend(users, measurements, c) })
+
+	r.GET("/api/nutrition/summary", func(c *gin.Context) { handlers.HandleGetNutritionSummary(users, nutrition, c) })
+	r.GET("/api/nutrition/today", func(c *gin.Context) { handlers.HandleGetNutritionToday(users, nutrition, c) })
+	r.PUT("/api/nutrition/targets", func(c *gin.Context) { handlers.HandleUpdateNutritionTargets(nutrition, c) })
+	r.GET("/api/nutrition/calculator", func(c *gin.Context) { handlers.HandleCalculateNutritionTargets(nutrition, c) })
+
+	r.GET("/api/users/:id/profile", func(c *gin.Context) { handlers.HandleGetUserProfile(profiles, c) })
+	r.PUT("/api/users/:id/profile", func(c *gin.Context) { handlers.HandleUpdateUserProfile(profiles, c) })
+
+	return &server{t: t, db: db, router: r, userID: user.ID}
+}
+
+// do sends a request for the server's user, encoding body as JSON unless
+// it is nil.
+func (s *server) do(method, path string, body any) *httptest.ResponseRecorder {
+	s.t.Helper()
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		r = bytes.NewReader(b)
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	req := httptest.NewRequest(method, fmt.Sprintf("%s%suser_id=%d", path, sep, s.userID), r)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// pantryItem creates a pantry item through the API and returns it.
+func (s *server) pantryItem(name string, quantity float64, unit string, caloriesPerUnit float64) models.PantryItem {
+	s.t.Helper()
+	w := s.do(http.MethodPost, "/api/pantry", models.PantryItemInput{
+		Name:            name,
+		Quantity:        quantity,
+		Unit:            unit,
+		CaloriesPerUnit: caloriesPerUnit,
+	})
+	return decode[models.PantryItem](s.t, w, http.StatusCreated)
+}
+
+// decode checks the response status and decodes its body.
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder, status int) T {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, status, w.Body)
+	}
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decoding %s: %v", w.Body, err)
+	}
+	return v
+}
+
+// wantError checks that the response is an error with the status and
+// code.
+func wantError(t *testing.T, w *httptest.ResponseRecorder, status int, code string) models.ErrorResponse {
+	t.Helper()
+	resp := decode[models.ErrorResponse](t, w, status)
+	if resp.Code != code {
+		t.Errorf("code = %q, want %q (error %q)", resp.Code, code, resp.Error)
+	}
+	return resp
+}
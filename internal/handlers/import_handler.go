@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/export"
+	"github.com/Ross1116/gym-tracker-backend/internal/importer"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleImportWorkouts godoc
+// @Summary Import workout history
+// @Description Import sessions from a Strong, Hevy or FitNotes CSV export. Unknown exercises are resolved with an optional JSON mapping file; with dry_run the import is rolled back and only the report is returned. With format=archive the file is a JSON or CSV zip export produced by GET /export.
+// @Tags Import
+// @Accept multipart/form-data
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param format query string false "Import format (auto, strong, hevy, fitnotes, archive)"
+// @Param gym_id query int false "Gym to attach sessions to; a placeholder gym is created when omitted"
+// @Param dry_run query bool false "Validate and report without writing"
+// @Param file formData file true "CSV export"
+// @Param mapping formData file false "JSON exercise/equipment mapping rules"
+// @Success 200 {object} models.ImportReport "Dry run report"
+// @Success 201 {object} models.ImportReport
+// @Failure 400 {object} models.ErrorResponse "Invalid input or unrecognised file"
+// @Failure 404 {object} models.ErrorResponse "User or gym not found"
+// @Failure 422 {object} models.ImportReport "Some exercises could not be mapped"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /import [post]
+func HandleImportWorkouts(db *sql.DB, users store.UserStore, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	if c.Query("format") == "archive" {
+		handleImportArchive(db, c, userID)
+		return
+	}
+
+	format, err := importer.ParseFormat(c.Query("format"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	opts := importer.Options{Format: format}
+	// Exports store wall-clock times, which are interpreted in the
+	// user's profile time zone.
+	if opts.Location, ok = userLocation(users, c, userID); !ok {
+		return
+	}
+	if gymID := c.Query("gym_id"); gymID != "" {
+		if opts.GymID, err = strconv.Atoi(gymID); err != nil {
+			writeError(c, http.StatusBadRequest, "Invalid gym ID format")
+			return
+		}
+	}
+	if dryRun := c.Query("dry_run"); dryRun != "" {
+		if opts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			writeError(c, http.StatusBadRequest, "Invalid dry_run value")
+			return
+		}
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Import file is required")
+		return
+	}
+
+	if mappingFile, err := c.FormFile("mapping"); err == nil {
+		f, err := mappingFile.Open()
+		if err != nil {
+			writeError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		defer f.Close()
+		if opts.Mapping, err = importer.LoadMapping(f); err != nil {
+			writeError(c, http.StatusBadRequest, "Invalid mapping file: "+err.Error())
+			return
+		}
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer f.Close()
+
+	report, err := importer.Import(ctx, db, userID, f, opts)
+	switch {
+	case errors.Is(err, importer.ErrUnmapped):
+		c.IndentedJSON(http.StatusUnprocessableEntity, report)
+		return
+	case errors.Is(err, importer.ErrUserNotFound), errors.Is(err, importer.ErrGymNotFound):
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, importer.ErrUnknownFormat), errors.Is(err, importer.ErrInvalidFile), errors.Is(err, importer.ErrNoSets):
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		writeInternalError(c, err)
+		return
+	}
+
+	if opts.DryRun {
+		c.IndentedJSON(http.StatusOK, report)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, report)
+}
+
+func handleImportArchive(db *sql.DB, c *gin.Context, userID int) {
+	ctx := c.Request.Context()
+	file, err := c.FormFile("file")
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Import file is required")
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer f.Close()
+
+	report, err := export.Import(ctx, db, userID, f, file.Size)
+	switch {
+	case errors.Is(err, export.ErrUserNotFound):
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, export.ErrInvalidArchive), errors.Is(err, export.ErrUnsupportedVersion):
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		writeInternalError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, report)
+}
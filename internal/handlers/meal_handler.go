@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetMeals godoc
+// @Summary Get meals
+// @Description Retrieve a user's logged meals with their ingredients and totals, oldest first
+// @Tags Meals
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param from query string false "First day (YYYY-MM-DD), defaults to 6 days before to"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to today in the user's time zone"
+// @Success 200 {array} models.Meal
+// @Failure 400 {object} models.ErrorResponse "User ID is required or invalid date"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meals [get]
+func HandleGetMeals(users store.UserStore, meals *service.MealService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	loc, ok := userLocation(users, c, userID)
+	if !ok {
+		return
+	}
+	from, to, ok := parseDateRange(c, loc, 7)
+	if !ok {
+		return
+	}
+
+	list, err := meals.List(ctx, userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, list)
+}
+
+// HandleGetMeal godoc
+// @Summary Get meal
+// @Description Retrieve a single logged meal with its ingredients and totals
+// @Tags Meals
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the meal"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.Meal
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Meal not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meals/{id} [get]
+func HandleGetMeal(meals *service.MealService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	meal, err := meals.Get(ctx, userID, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, meal)
+}
+
+// HandleCreateMeal godoc
+// @Summary Log meal
+// @Description Log a meal made from pantry items. The quantities used are deducted from the pantry in the same transaction; the meal is refused if any item does not hold enough stock.
+// @Tags Meals
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param meal body models.MealInput true "Meal details"
+// @Success 201 {object} models.Meal
+// @Failure 400 {object} models.ErrorResponse "User ID is required, invalid input, unknown pantry item or unit that does not convert"
+// @Failure 409 {object} models.InsufficientStockResponse "Insufficient pantry stock"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meals [post]
+func HandleCreateMeal(meals *service.MealService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.MealInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	eatenAt := time.Now()
+	if input.EatenAt != nil {
+		eatenAt = *input.EatenAt
+	}
+
+	meal, err := meals.Create(ctx, userID, eatenAt, input.Ingredients)
+	if err != nil {
+		writeMealError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, meal)
+}
+
+// HandleUpdateMeal godoc
+// @Summary Update meal
+// @Description Replace a meal's ingredients. The stock used by the previous ingredients is returned to the pantry before the new ones are deducted. An omitted eaten_at keeps the original time.
+// @Tags Meals
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the meal"
+// @Param user_id query int true "ID of the user"
+// @Param meal body models.MealInput true "Meal details"
+// @Success 200 {object} models.Meal
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format, invalid input, unknown pantry item or unit that does not convert"
+// @Failure 404 {object} models.ErrorResponse "Meal not found"
+// @Failure 409 {object} models.InsufficientStockResponse "Insufficient pantry stock"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meals/{id} [put]
+func HandleUpdateMeal(meals *service.MealService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.MealInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	meal, err := meals.Update(ctx, userID, id, input.EatenAt, input.Ingredients)
+	if err != nil {
+		writeMealError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, meal)
+}
+
+// HandleDeleteMeal godoc
+// @Summary Delete meal
+// @Description Delete a logged meal and return the stock it used to the pantry
+// @Tags Meals
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the meal"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.SuccessResponse "Meal deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Meal not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meals/{id} [delete]
+func HandleDeleteMeal(meals *service.MealService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	err = meals.Delete(ctx, userID, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Meal deleted successfully"})
+}
+
+// writeMealError writes the response for an error from a service that
+// records ingredients, reporting the items short of stock when a meal
+// cannot be deducted from the pantry.
+func writeMealError(c *gin.Context, err error) {
+	var stockErr *pantry.InsufficientStockError
+	if errors.As(err, &stockErr) {
+		c.IndentedJSON(http.StatusConflict, models.InsufficientStockResponse{
+			ErrorResponse: errorResponse(c, codeInsufficientStock, "Insufficient pantry stock"),
+			Shortfalls:    stockErr.Shortfalls,
+		})
+		return
+	}
+	writeServiceError(c, err)
+}
@@ -0,0 +1,137 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+func TestCreateMealDeductsStock(t *testing.T) {
+	s := newServer(t)
+	oats := s.pantryItem("Oats", 750, "g", 4)
+	milk := s.pantryItem("Milk", 1, "l", 600)
+
+	w := s.do(http.MethodPost, "/api/meals", models.MealInput{
+		Ingredients: []models.MealIngredientInput{
+			{PantryItemID: oats.ID, QuantityUsed: 80},
+			{PantryItemID: milk.ID, QuantityUsed: 250, Unit: "ml"},
+		},
+	})
+	meal := decode[models.Meal](t, w, http.StatusCreated)
+	if meal.TotalCalories != 80*4+0.25*600 {
+		t.Errorf("total calories = %v, want %v", meal.TotalCalories, 80*4+0.25*600)
+	}
+
+	for id, want := range map[int]float64{oats.ID: 670, milk.ID: 0.75} {
+		got := decode[models.PantryItem](t, s.do(http.MethodGet, fmt.Sprintf("/api/pantry/%d", id), nil), http.StatusOK)
+		if got.Quantity != want {
+			t.Errorf("%s quantity = %v, want %v", got.Name, got.Quantity, want)
+		}
+	}
+}
+
+func TestCreateMealRejected(t *testing.T) {
+	s := newServer(t)
+	oats := s.pantryItem("Oats", 50, "g", 4)
+	eggs := s.pantryItem("Eggs", 6, "item", 70)
+
+	tests := []struct {
+		name        string
+		ingredients []models.MealIngredientInput
+		status      int
+		code        string
+	}{
+		{
+			name: "insufficient stock",
+			ingredients: []models.MealIngredientInput{
+				{PantryItemID: eggs.ID, QuantityUsed: 2},
+				{PantryItemID: oats.ID, QuantityUsed: 80},
+			},
+			status: http.StatusConflict,
+			code:   "insufficient_stock",
+		},
+		{
+			name:        "unknown pantry item",
+			ingredients: []models.MealIngredientInput{{PantryItemID: 999, QuantityUsed: 1}},
+			status:      http.StatusBadRequest,
+			code:        "invalid_request",
+		},
+		{
+			name:        "unit that does not convert",
+			ingredients: []models.MealIngredientInput{{PantryItemID: eggs.ID, QuantityUsed: 100, Unit: "g"}},
+			status:      http.StatusBadRequest,
+			code:        "invalid_request",
+		},
+		{
+			name:   "no ingredients",
+			status: http.StatusBadRequest,
+			code:   "validation_failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPost, "/api/meals", models.MealInput{Ingredients: tt.ingredients})
+			wantError(t, w, tt.status, tt.code)
+		})
+	}
+
+	// Nothing is deducted when any ingredient falls short.
+	got := decode[models.PantryItem](t, s.do(http.MethodGet, fmt.Sprintf("/api/pantry/%d", eggs.ID), nil), http.StatusOK)
+	if got.Quantity != 6 {
+		t.Errorf("eggs quantity = %v, want 6", got.Quantity)
+	}
+}
+
+func TestCreateMealReportsShortfalls(t *testing.T) {
+	s := newServer(t)
+	oats := s.pantryItem("Oats", 50, "g", 4)
+
+	w := s.do(http.MethodPost, "/api/meals", models.MealInput{
+		Ingredients: []models.MealIngredientInput{{PantryItemID: oats.ID, QuantityUsed: 80}},
+	})
+	resp := decode[models.InsufficientStockResponse](t, w, http.StatusConflict)
+	want := models.StockShortfall{PantryItemID: oats.ID, Name: "Oats", Unit: "g", Required: 80, Available: 50}
+	if len(resp.Shortfalls) != 1 || resp.Shortfalls[0] != want {
+		t.Errorf("shortfalls = %+v, want [%+v]", resp.Shortfalls, want)
+	}
+}
+
+func TestUpdateAndDeleteMealReturnStock(t *testing.T) {
+	s := newServer(t)
+	oats := s.pantryItem("Oats", 100, "g", 4)
+	quantity := func() float64 {
+		t.Helper()
+		return decode[models.PantryItem](t, s.do(http.MethodGet, fmt.Sprintf("/api/pantry/%d", oats.ID), nil), http.StatusOK).Quantity
+	}
+
+	w := s.do(http.MethodPost, "/api/meals", models.MealInput{
+		Ingredients: []models.MealIngredientInput{{PantryItemID: oats.ID, QuantityUsed: 80}},
+	})
+	meal := decode[models.Meal](t, w, http.StatusCreated)
+
+	// The 80 g already eaten count towards what the new 90 g can use.
+	w = s.do(http.MethodPut, fmt.Sprintf("/api/meals/%d", meal.ID), models.MealInput{
+		Ingredients: []models.MealIngredientInput{{PantryItemID: oats.ID, QuantityUsed: 90}},
+	})
+	decode[models.Meal](t, w, http.StatusOK)
+	if got := quantity(); got != 10 {
+		t.Errorf("quantity after update = %v, want 10", got)
+	}
+
+	w = s.do(http.MethodPut, fmt.Sprintf("/api/meals/%d", meal.ID), models.MealInput{
+		Ingredients: []models.MealIngredientInput{{PantryItemID: oats.ID, QuantityUsed: 200}},
+	})
+	wantError(t, w, http.StatusConflict, "insufficient_stock")
+	if got := quantity(); got != 10 {
+		t.Errorf("quantity after refused update = %v, want 10", got)
+	}
+
+	decode[models.SuccessResponse](t, s.do(http.MethodDelete, fmt.Sprintf("/api/meals/%d", meal.ID), nil), http.StatusOK)
+	if got := quantity(); got != 100 {
+		t.Errorf("quantity after delete = %v, want 100", got)
+	}
+	wantError(t, s.do(http.MethodGet, fmt.Sprintf("/api/meals/%d", meal.ID), nil), http.StatusNotFound, "not_found")
+	wantError(t, s.do(http.MethodDelete, fmt.Sprintf("/api/meals/%d", meal.ID), nil), http.StatusNotFound, "not_found")
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetMealPlan godoc
+// @Summary Get meal plan
+// @Description Retrieve the meals planned for a range of days with the pantry items each will use
+// @Tags MealPlan
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param from query string false "First day (YYYY-MM-DD), defaults to today in the user's time zone"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to 6 days after from"
+// @Success 200 {array} models.MealPlanEntry
+// @Failure 400 {object} models.ErrorResponse "User ID is required or invalid date"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meal-plan [get]
+func HandleGetMealPlan(users store.UserStore, plans *service.MealPlanService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parsePlanRange(users, c, userID)
+	if !ok {
+		return
+	}
+
+	entries, err := plans.List(ctx, userID, from.Format(dateLayout), to.Format(dateLayout))
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, entries)
+}
+
+// HandleGetMealPlanEntry godoc
+// @Summary Get meal plan entry
+// @Description Retrieve a single planned meal with the pantry items it will use
+// @Tags MealPlan
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the meal plan entry"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.MealPlanEntry
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Meal plan entry not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meal-plan/{id} [get]
+func HandleGetMealPlanEntry(plans *service.MealPlanService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := plans.Get(ctx, userID, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, entry)
+}
+
+// HandleCreateMealPlanEntry godoc
+// @Summary Plan a meal
+// @Description Plan servings of a recipe, or a list of pantry items, for a day. Nothing is deducted from the pantry until the meal is logged.
+// @Tags MealPlan
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param entry body models.MealPlanEntryInput true "Planned meal"
+// @Success 201 {object} models.MealPlanEntry
+// @Failure 400 {object} models.ErrorResponse "User ID is required, invalid input, unknown recipe, unknown pantry item or unit that does not convert"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meal-plan [post]
+func HandleCreateMealPlanEntry(plans *service.MealPlanService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	input, ok := bindMealPlanEntryInput(c)
+	if !ok {
+		return
+	}
+
+	entry, err := plans.Create(ctx, userID, input)
+	if err != nil {
+		writeMealError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, entry)
+}
+
+// HandleUpdateMealPlanEntry godoc
+// @Summary Update planned meal
+// @Description Replace a planned meal's day, recipe or ingredients, servings and notes
+// @Tags MealPlan
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the meal plan entry"
+// @Param user_id query int true "ID of the user"
+// @Param entry body models.MealPlanEntryInput true "Planned meal"
+// @Success 200 {object} models.MealPlanEntry
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format, invalid input, unknown recipe, unknown pantry item or unit that does not convert"
+// @Failure 404 {object} models.ErrorResponse "Meal plan entry not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meal-plan/{id} [put]
+func HandleUpdateMealPlanEntry(plans *service.MealPlanService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	input, ok := bindMealPlanEntryInput(c)
+	if !ok {
+		return
+	}
+
+	entry, err := plans.Update(ctx, userID, id, input)
+	if err != nil {
+		writeMealError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, entry)
+}
+
+// HandleDeleteMealPlanEntry godoc
+// @Summary Delete planned meal
+// @Description Remove a meal from the plan
+// @Tags MealPlan
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the meal plan entry"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.SuccessResponse "Meal plan entry deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Meal plan entry not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meal-plan/{id} [delete]
+func HandleDeleteMealPlanEntry(plans *service.MealPlanService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	err = plans.Delete(ctx, userID, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Meal plan entry deleted successfully"})
+}
+
+// HandleGetMealPlanRequirements godoc
+// @Summary Get meal plan requirements
+// @Description Total the pantry items the meals planned for a range of days will use and compare them with current stock
+// @Tags MealPlan
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param from query string false "First day (YYYY-MM-DD), defaults to today in the user's time zone"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to 6 days after from"
+// @Success 200 {object} models.MealPlanRequirements
+// @Failure 400 {object} models.ErrorResponse "User ID is required or invalid date"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /meal-plan/requirements [get]
+func HandleGetMealPlanRequirements(users store.UserStore, plans *service.MealPlanService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parsePlanRange(users, c, userID)
+	if !ok {
+		return
+	}
+
+	plan := pantry.PlanWindow{From: from.Format(dateLayout), To: to.Format(dateLayout)}
+	items, err := plans.Requirements(ctx, userID, plan)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.MealPlanRequirements{From: plan.From, To: plan.To, Items: items})
+}
+
+// parsePlanRange reads the optional from/to query parameters as calendar
+// days in the user's time zone. Plans look ahead, so from defaults to today
+// and to to 6 days after from. A 400 or 500 response is written and false
+// returned on failure.
+func parsePlanRange(users store.UserStore, c *gin.Context, userID int) (from, to time.Time, ok bool) {
+	loc, ok := userLocation(users, c, userID)
+	if !ok {
+		return from, to, false
+	}
+
+	now := time.Now().In(loc)
+	from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	var err error
+	if s := c.Query("from"); s != "" {
+		if from, err = time.ParseInLocation(dateLayout, s, loc); err != nil {
+			writeError(c, http.StatusBadRequest, "Invalid from date, expected YYYY-MM-DD")
+			return from, to, false
+		}
+	}
+	to = from.AddDate(0, 0, 6)
+	if s := c.Query("to"); s != "" {
+		if to, err = time.ParseInLocation(dateLayout, s, loc); err != nil {
+			writeError(c, http.StatusBadRequest, "Invalid to date, expected YYYY-MM-DD")
+			return from, to, false
+		}
+	}
+	if from.After(to) {
+		writeError(c, http.StatusBadRequest, "from must not be after to")
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// planWindowParam reads plan_days, the number of days starting today in the
+// user's time zone whose meal plan feeds the shopping list. It defaults to
+// 7; 0 leaves the plan out and returns a nil window.
+func planWindowParam(users store.UserStore, c *gin.Context, userID int) (*pantry.PlanWindow, bool) {
+	days := 7
+	if s := c.Query("plan_days"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > 366 {
+			writeError(c, http.StatusBadRequest, "plan_days must be a whole number between 0 and 366")
+			return nil, false
+		}
+		days = n
+	}
+	if days == 0 {
+		return nil, true
+	}
+
+	loc, ok := userLocation(users, c, userID)
+	if !ok {
+		return nil, false
+	}
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	return &pantry.PlanWindow{
+		From: today.Format(dateLayout),
+		To:   today.AddDate(0, 0, days-1).Format(dateLayout),
+	}, true
+}
+
+// bindMealPlanEntryInput binds a planned meal, which must name either a
+// recipe or a list of ingredients. A 400 response is written on failure.
+func bindMealPlanEntryInput(c *gin.Context) (models.MealPlanEntryInput, bool) {
+	var input models.MealPlanEntryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return input, false
+	}
+
+	if _, err := time.Parse(dateLayout, input.Date); err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid date, expected YYYY-MM-DD")
+		return input, false
+	}
+
+	if (input.RecipeID == nil) == (len(input.Ingredients) == 0) {
+		writeError(c, http.StatusBadRequest, "Plan either a recipe_id or a list of ingredients")
+		return input, false
+	}
+	if input.RecipeID == nil && input.Servings != nil {
+		writeError(c, http.StatusBadRequest, "Servings can only be planned for a recipe")
+		return input, false
+	}
+	return input, true
+}
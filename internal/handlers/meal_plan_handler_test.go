@@ -0,0 +1,97 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+func TestCreateMealPlanEntry(t *testing.T) {
+	s := newServer(t)
+	rice := s.pantryItem("Rice", 500, "g", 1.3)
+	r := decode[models.Recipe](t, s.do(http.MethodPost, "/api/recipes", models.RecipeInput{
+		Name:        "Rice bowl",
+		Servings:    4,
+		Ingredients: []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 400}},
+	}), http.StatusCreated)
+	two := 2.0
+
+	tests := []struct {
+		name   string
+		input  models.MealPlanEntryInput
+		status int
+	}{
+		{"recipe", models.MealPlanEntryInput{Date: "2025-03-17", RecipeID: &r.ID, Servings: &two}, http.StatusCreated},
+		{"ingredients", models.MealPlanEntryInput{
+			Date:        "2025-03-17",
+			Ingredients: []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 0.1, Unit: "kg"}},
+		}, http.StatusCreated},
+		{"unknown recipe", models.MealPlanEntryInput{Date: "2025-03-17", RecipeID: ptr(999)}, http.StatusBadRequest},
+		{"neither", models.MealPlanEntryInput{Date: "2025-03-17"}, http.StatusBadRequest},
+		{"servings without recipe", models.MealPlanEntryInput{
+			Date:        "2025-03-17",
+			Servings:    &two,
+			Ingredients: []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 100}},
+		}, http.StatusBadRequest},
+		{"bad date", models.MealPlanEntryInput{Date: "17/03/2025", RecipeID: &r.ID}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPost, "/api/meal-plan", tt.input)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.status != http.StatusCreated {
+				return
+			}
+			e := decode[models.MealPlanEntry](t, w, http.StatusCreated)
+			if len(e.Ingredients) != 1 || e.Ingredients[0].Quantity != 200 && e.Ingredients[0].Quantity != 100 {
+				t.Errorf("ingredients = %+v", e.Ingredients)
+			}
+		})
+	}
+}
+
+func TestMealPlanRequirements(t *testing.T) {
+	s := newServer(t)
+	rice := s.pantryItem("Rice", 300, "g", 1.3)
+	r := decode[models.Recipe](t, s.do(http.MethodPost, "/api/recipes", models.RecipeInput{
+		Name:        "Rice bowl",
+		Servings:    2,
+		Ingredients: []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 200}},
+	}), http.StatusCreated)
+
+	today := time.Now().Format("2006-01-02")
+	later := time.Now().AddDate(0, 0, 30).Format("2006-01-02")
+	for _, date := range []string{today, today, later} {
+		decode[models.MealPlanEntry](t, s.do(http.MethodPost, "/api/meal-plan",
+			models.MealPlanEntryInput{Date: date, RecipeID: &r.ID}), http.StatusCreated)
+	}
+
+	w := s.do(http.MethodGet, "/api/meal-plan/requirements", nil)
+	got := decode[models.MealPlanRequirements](t, w, http.StatusOK)
+	want := models.MealPlanRequirement{PantryItemID: rice.ID, Name: "Rice", Unit: "g", Required: 400, Available: 300, Shortfall: 100}
+	if len(got.Items) != 1 || got.Items[0] != want {
+		t.Errorf("items = %+v, want [%+v]", got.Items, want)
+	}
+
+	w = s.do(http.MethodGet, fmt.Sprintf("/api/meal-plan/requirements?from=%s&to=%s", later, today), nil)
+	wantError(t, w, http.StatusBadRequest, "invalid_request")
+}
+
+func TestUpdateMealPlanEntryNotFound(t *testing.T) {
+	s := newServer(t)
+	rice := s.pantryItem("Rice", 300, "g", 1.3)
+	w := s.do(http.MethodPut, "/api/meal-plan/999", models.MealPlanEntryInput{
+		Date:        "2025-03-17",
+		Ingredients: []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 100}},
+	})
+	wantError(t, w, http.StatusNotFound, "not_found")
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
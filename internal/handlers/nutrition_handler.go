@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetNutritionSummary godoc
+// @Summary Get nutrition summary
+// @Description Total calories, protein, carbs, fat and fiber from logged meals per day or per week (Monday to Sunday), bucketed in the user's time zone. Days without meals are included with zero totals.
+// @Tags Nutrition
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param period query string false "day (default) or week"
+// @Param from query string false "First day (YYYY-MM-DD), defaults to 6 days before to"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to today in the user's time zone"
+// @Success 200 {object} models.NutritionSummary
+// @Failure 400 {object} models.ErrorResponse "User ID is required, invalid date, range over 366 days or unknown period"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /nutrition/summary [get]
+func HandleGetNutritionSummary(users store.UserStore, nutrition *service.NutritionService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	loc, ok := userLocation(users, c, userID)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parseDateRange(c, loc, 7)
+	if !ok {
+		return
+	}
+
+	summary, err := nutrition.Summary(ctx, userID, loc, c.DefaultQuery("period", "day"), from, to)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, summary)
+}
+
+// HandleGetNutritionToday godoc
+// @Summary Get today's nutrition
+// @Description What has been eaten so far today in the user's time zone, their macro targets and what remains of each target
+// @Tags Nutrition
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.NutritionToday
+// @Failure 400 {object} models.ErrorResponse "User ID is required"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /nutrition/today [get]
+func HandleGetNutritionToday(users store.UserStore, nutrition *service.NutritionService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	loc, ok := userLocation(users, c, userID)
+	if !ok {
+		return
+	}
+
+	today, err := nutrition.Today(ctx, userID, loc)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, today)
+}
+
+// HandleGetNutritionTargets godoc
+// @Summary Get nutrition targets
+// @Description Retrieve a user's daily calorie and macro targets. Targets that have not been set are omitted.
+// @Tags Nutrition
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.NutritionTargets
+// @Failure 400 {object} models.ErrorResponse "User ID is required"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /nutrition/targets [get]
+func HandleGetNutritionTargets(nutrition *service.NutritionService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	targets, err := nutrition.Targets(ctx, userID)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, targets)
+}
+
+// HandleUpdateNutritionTargets godoc
+// @Summary Update nutrition targets
+// @Description Create or replace a user's daily calorie and macro targets. Omitted targets are cleared.
+// @Tags Nutrition
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param targets body models.NutritionTargetsInput true "Daily targets"
+// @Success 200 {object} models.NutritionTargets
+// @Failure 400 {object} models.ErrorResponse "User ID is required or invalid input"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /nutrition/targets [put]
+func HandleUpdateNutritionTargets(nutrition *service.NutritionService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.NutritionTargetsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	targets, err := nutrition.SaveTargets(ctx, userID, input)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, targets)
+}
+
+// HandleCalculateNutritionTargets godoc
+// @Summary Calculate calorie and protein targets
+// @Description Recommend daily calories and protein from the user's profile, bodyweight trend and logged training. Maintenance is BMR (Mifflin-St Jeor) × activity factor plus the average daily cost of resistance sets and cardio over the lookback window. Every input is returned with its source and can be overridden with the query parameter of the same name.
+// @Tags Nutrition
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param bodyweight_kg query number false "Override bodyweight (defaults to the body log trend, then the profile)"
+// @Param height_cm query number false "Override height"
+// @Param age query int false "Override age (defaults to the profile birth year)"
+// @Param sex query string false "Override sex: male, female or other"
+// @Param activity_factor query number false "Multiplier for activity outside training, default 1.3"
+// @Param lookback_days query int false "Days of workouts to average, default 28"
+// @Param sets_per_day query number false "Override average working sets per day"
+// @Param cardio_minutes_per_day query number false "Override average cardio minutes per day"
+// @Param kcal_per_set_per_kg query number false "Energy per working set per kg bodyweight, default 0.1"
+// @Param cardio_met query number false "MET value of cardio sessions, default 7"
+// @Param protein_per_kg query number false "Protein per kg bodyweight, default 1.8"
+// @Param adjustment_kcal query number false "Surplus (positive) or deficit (negative) applied to maintenance, default 0"
+// @Success 200 {object} models.TargetCalculation
+// @Failure 400 {object} models.ErrorResponse "User ID is required, an input is missing, malformed or out of range"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /nutrition/calculator [get]
+func HandleCalculateNutritionTargets(nutrition *service.NutritionService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var o service.TargetOverrides
+	params := []struct {
+		name string
+		out  **float64
+	}{
+		{"bodyweight_kg", &o.BodyweightKg},
+		{"height_cm", &o.HeightCm},
+		{"age", &o.Age},
+		{"activity_factor", &o.ActivityFactor},
+		{"lookback_days", &o.LookbackDays},
+		{"sets_per_day", &o.SetsPerDay},
+		{"cardio_minutes_per_day", &o.CardioMinutesPerDay},
+		{"kcal_per_set_per_kg", &o.KcalPerSetPerKg},
+		{"cardio_met", &o.CardioMET},
+		{"protein_per_kg", &o.ProteinPerKg},
+		{"adjustment_kcal", &o.AdjustmentKcal},
+	}
+	for _, p := range params {
+		if *p.out, ok = floatParam(c, p.name); !ok {
+			return
+		}
+	}
+	if s := c.Query("sex"); s != "" {
+		o.Sex = &s
+	}
+
+	calculation, err := nutrition.CalculateTargets(ctx, userID, o)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, calculation)
+}
+
+// floatParam reads the optional query parameter name as a number, returning
+// nil when it is absent. A 400 response is written and false returned when
+// it is malformed.
+func floatParam(c *gin.Context, name string) (*float64, bool) {
+	s := c.Query(name)
+	if s == "" {
+		return nil, true
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, fmt.Sprintf("%s must be a number", name))
+		return nil, false
+	}
+	return &v, true
+}
@@ -0,0 +1,168 @@
+package handlers_test
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/memory"
+)
+
+func TestGetNutritionToday(t *testing.T) {
+	s := newServer(t)
+	oats := s.pantryItem("Oats", 750, "g", 4)
+	target := 2000.0
+	decode[models.NutritionTargets](t, s.do(http.MethodPut, "/api/nutrition/targets",
+		models.NutritionTargetsInput{Calories: &target}), http.StatusOK)
+
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	for _, at := range []*time.Time{&now, &now, &yesterday} {
+		decode[models.Meal](t, s.do(http.MethodPost, "/api/meals", models.MealInput{
+			EatenAt:     at,
+			Ingredients: []models.MealIngredientInput{{PantryItemID: oats.ID, QuantityUsed: 100}},
+		}), http.StatusCreated)
+	}
+
+	got := decode[models.NutritionToday](t, s.do(http.MethodGet, "/api/nutrition/today", nil), http.StatusOK)
+	if got.Meals != 2 || got.Consumed.Calories != 800 {
+		t.Errorf("today = %d meals, %v kcal; want 2 meals, 800 kcal", got.Meals, got.Consumed.Calories)
+	}
+	if got.Remaining.Calories == nil || *got.Remaining.Calories != 1200 {
+		t.Errorf("remaining calories = %v, want 1200", got.Remaining.Calories)
+	}
+}
+
+func TestNutritionSummaryRange(t *testing.T) {
+	s := newServer(t)
+
+	tests := []struct {
+		name     string
+		from, to string
+		periods  int // 0 when the range is rejected
+	}{
+		{"a leap year", "2024-01-01", "2024-12-31", 366},
+		{"a day too many", "2024-01-01", "2025-01-01", 0},
+		{"from the first year", "0001-01-01", "2025-03-17", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodGet, "/api/nutrition/summary?from="+tt.from+"&to="+tt.to, nil)
+			if tt.periods > 0 {
+				got := decode[models.NutritionSummary](t, w, http.StatusOK)
+				if len(got.Periods) != tt.periods {
+					t.Errorf("got %d periods, want %d", len(got.Periods), tt.periods)
+				}
+				return
+			}
+			resp := wantError(t, w, http.StatusBadRequest, "validation_failed")
+			if len(resp.Fields) != 1 || resp.Fields[0].Field != "from" {
+				t.Errorf("fields = %+v, want one for from", resp.Fields)
+			}
+		})
+	}
+}
+
+func TestUpdateNutritionTargetsUnknownUser(t *testing.T) {
+	s := newServer(t)
+	s.userID = 999
+	target := 2000.0
+	w := s.do(http.MethodPut, "/api/nutrition/targets", models.NutritionTargetsInput{Calories: &target})
+	wantError(t, w, http.StatusNotFound, "not_found")
+}
+
+func TestCalculateNutritionTargets(t *testing.T) {
+	s := newServer(t)
+	wantError(t, s.do(http.MethodGet, "/api/nutrition/calculator", nil), http.StatusBadRequest, "invalid_request")
+
+	height, birthYear, sex, weight := 180.0, time.Now().Year()-30, "male", 90.0
+	decode[models.UserProfile](t, s.do(http.MethodPut, "/api/users/1/profile", models.UserProfileInput{
+		BodyweightKg: &weight, HeightCm: &height, BirthYear: &birthYear, Sex: &sex, Timezone: "UTC",
+	}), http.StatusOK)
+
+	// A weigh-in in the body log is preferred over the profile.
+	logged := 80.0
+	decode[models.BodyMeasurement](t, s.do(http.MethodPost, "/api/body-measurements",
+		models.BodyMeasurementInput{BodyweightKg: &logged}), http.StatusCreated)
+	s.db.Training[s.userID] = memory.Training{Sets: 280, CardioMinutes: 0}
+
+	got := decode[models.TargetCalculation](t, s.do(http.MethodGet, "/api/nutrition/calculator", nil), http.StatusOK)
+	if w := got.Inputs.BodyweightKg; w.Value != 80 || w.Source != "body_log" {
+		t.Errorf("bodyweight = %+v, want 80 from body_log", w)
+	}
+	if got.Inputs.SetsPerDay.Value != 10 {
+		t.Errorf("sets per day = %v, want 10", got.Inputs.SetsPerDay.Value)
+	}
+	// Mifflin-St Jeor for an 80 kg, 180 cm, 30 year old man.
+	if got.BMR != 10*80+6.25*180-5*30+5 {
+		t.Errorf("bmr = %v, want %v", got.BMR, 10*80+6.25*180-5*30+5)
+	}
+
+	s.userID = 999
+	wantError(t, s.do(http.MethodGet, "/api/nutrition/calculator", nil), http.StatusNotFound, "not_found")
+}
+
+func TestCalculateNutritionTargetsFallbacks(t *testing.T) {
+	height, birthYear, weight := 170.0, time.Now().Year()-40, 70.0
+
+	tests := []struct {
+		name       string
+		profile    *models.UserProfileInput
+		query      string
+		status     int
+		wantBMR    float64
+		wantSex    string
+		wantSource string
+	}{
+		{
+			name:   "no profile",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "no height",
+			query:  "?bodyweight_kg=70&age=40",
+			status: http.StatusBadRequest,
+		},
+		{
+			// Everything missing from the profile can be passed instead.
+			name:       "no profile with overrides",
+			query:      "?bodyweight_kg=70&height_cm=170&age=40&sex=male",
+			status:     http.StatusOK,
+			wantBMR:    700 + 1062.5 - 200 + 5,
+			wantSex:    "male",
+			wantSource: "override",
+		},
+		{
+			// Without a sex on the profile the midpoint offset is used.
+			name:       "profile without sex",
+			profile:    &models.UserProfileInput{BodyweightKg: &weight, HeightCm: &height, BirthYear: &birthYear},
+			status:     http.StatusOK,
+			wantBMR:    700 + 1062.5 - 200 - 78,
+			wantSex:    "unspecified",
+			wantSource: "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t)
+			if tt.profile != nil {
+				decode[models.UserProfile](t, s.do(http.MethodPut, "/api/users/1/profile", *tt.profile), http.StatusOK)
+			}
+
+			w := s.do(http.MethodGet, "/api/nutrition/calculator"+tt.query, nil)
+			if tt.status != http.StatusOK {
+				wantError(t, w, tt.status, "invalid_request")
+				return
+			}
+			got := decode[models.TargetCalculation](t, w, http.StatusOK)
+			if got.BMR != math.Round(tt.wantBMR) {
+				t.Errorf("bmr = %v, want %v", got.BMR, math.Round(tt.wantBMR))
+			}
+			if got.Inputs.Sex != tt.wantSex || got.Inputs.SexSource != tt.wantSource {
+				t.Errorf("sex = %q from %q, want %q from %q", got.Inputs.Sex, got.Inputs.SexSource, tt.wantSex, tt.wantSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,507 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetPantryItems godoc
+// @Summary Get pantry items
+// @Description Retrieve a user's pantry, optionally only the items below their restock threshold
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param low_stock query bool false "Only return items below their threshold"
+// @Success 200 {array} models.PantryItem
+// @Failure 400 {object} models.ErrorResponse "User ID is required"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry [get]
+func HandleGetPantryItems(pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	items, err := pantryItems.List(ctx, userID, c.Query("low_stock") == "true")
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, items)
+}
+
+// HandleGetPantryItem godoc
+// @Summary Get pantry item
+// @Description Retrieve a single pantry item
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the pantry item"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.PantryItem
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Pantry item not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry/{id} [get]
+func HandleGetPantryItem(pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	p, err := pantryItems.Get(ctx, userID, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, p)
+}
+
+// HandleCreatePantryItem godoc
+// @Summary Add pantry item
+// @Description Add an item to a user's pantry. Units are g, kg, ml, l or item; quantities and nutrition values must not be negative. Giving grams_per_item lets recipes and meals measure the item by weight or by count, and expires_on records the starting quantity as expiring on that day.
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param item body models.PantryItemInput true "Pantry item details"
+// @Success 201 {object} models.PantryItem
+// @Failure 400 {object} models.ErrorResponse "User ID is required or invalid input"
+// @Failure 409 {object} models.ErrorResponse "Pantry item with this name already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry [post]
+func HandleCreatePantryItem(pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	input, ok := bindPantryItemInput(c)
+	if !ok {
+		return
+	}
+
+	p, err := pantryItems.Create(ctx, userID, input)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, p)
+}
+
+// HandleUpdatePantryItem godoc
+// @Summary Update pantry item
+// @Description Replace a pantry item's details, including its current quantity. Stock added by raising the quantity is dated with expires_on when given; lowering the quantity draws down the batches expiring first. Changing the unit converts the item's batches and the amounts of it recorded in meals, recipes, meal plans and the shopping list. A unit that does not convert is refused while the item is used there; otherwise its batches are left undated.
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the pantry item"
+// @Param user_id query int true "ID of the user"
+// @Param item body models.PantryItemInput true "Pantry item details"
+// @Success 200 {object} models.PantryItem
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format or invalid input"
+// @Failure 404 {object} models.ErrorResponse "Pantry item not found"
+// @Failure 409 {object} models.ErrorResponse "Pantry item with this name already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry/{id} [put]
+func HandleUpdatePantryItem(pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	input, ok := bindPantryItemInput(c)
+	if !ok {
+		return
+	}
+
+	p, err := pantryItems.Update(ctx, userID, id, input)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, p)
+}
+
+// HandleRestockPantryItem godoc
+// @Summary Restock pantry item
+// @Description Add to a pantry item's quantity. The increment is applied in a single statement so concurrent restocks and meals are not lost. With expires_on the added stock is recorded as a batch expiring on that day.
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the pantry item"
+// @Param user_id query int true "ID of the user"
+// @Param restock body models.PantryRestockInput true "Quantity to add, in the item's unit"
+// @Success 200 {object} models.PantryItem
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format or invalid input"
+// @Failure 404 {object} models.ErrorResponse "Pantry item not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry/{id}/restock [post]
+func HandleRestockPantryItem(pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PantryRestockInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	if !validExpiry(c, input.ExpiresOn) {
+		return
+	}
+
+	p, err := pantryItems.Restock(ctx, userID, id, input)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, p)
+}
+
+// HandleDeletePantryItem godoc
+// @Summary Delete pantry item
+// @Description Remove an item from a user's pantry. Items used in logged meals cannot be deleted.
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the pantry item"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.SuccessResponse "Pantry item deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Pantry item not found"
+// @Failure 409 {object} models.ErrorResponse "Pantry item is used in logged meals, recipes or meal plans"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry/{id} [delete]
+func HandleDeletePantryItem(pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	err = pantryItems.Delete(ctx, userID, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Pantry item deleted successfully"})
+}
+
+// HandleGetExpiringPantryItems godoc
+// @Summary Get expiring pantry stock
+// @Description List the batches of pantry stock expiring within the next few days, soonest first. Batches that have already expired are included with a negative days_left until they are used or discarded.
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param days query int false "Days ahead to look, counting from today in the user's time zone (default 3)"
+// @Success 200 {array} models.ExpiringBatch
+// @Failure 400 {object} models.ErrorResponse "User ID is required or invalid days"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry/expiring [get]
+func HandleGetExpiringPantryItems(users store.UserStore, pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "3"))
+	if err != nil || days < 0 || days > 366 {
+		writeError(c, http.StatusBadRequest, "days must be a whole number between 0 and 366")
+		return
+	}
+
+	loc, ok := userLocation(users, c, userID)
+	if !ok {
+		return
+	}
+	today := time.Now().In(loc).Format(dateLayout)
+
+	batches, err := pantryItems.Expiring(ctx, userID, today, days)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, batches)
+}
+
+// HandleGetPantryBatches godoc
+// @Summary Get pantry item batches
+// @Description Break a pantry item's stock down into dated batches, in the order meals will use them, and the undated remainder
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the pantry item"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.PantryItemBatches
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Pantry item not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry/{id}/batches [get]
+func HandleGetPantryBatches(pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := pantryItems.Batches(ctx, userID, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// HandleDiscardPantryBatch godoc
+// @Summary Discard pantry batch
+// @Description Throw away a batch of stock, typically once it has expired. The batch's quantity is removed from the pantry item.
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the pantry item"
+// @Param batch_id path int true "ID of the batch"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.SuccessResponse "Batch discarded successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Batch not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry/{id}/batches/{batch_id} [delete]
+func HandleDiscardPantryBatch(pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	batchID, err := strconv.Atoi(c.Param("batch_id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	err = pantryItems.DiscardBatch(ctx, userID, id, batchID)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Batch discarded successfully"})
+}
+
+// HandleGetPantryForecast godoc
+// @Summary Forecast pantry stock
+// @Description Project when each pantry item will run out from its average daily use in recent meals, soonest first, and suggest a threshold that leaves lead_days of use in stock when the item goes on the shopping list. Items with no recent use come last without a projection.
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param history_days query int false "Days of meal history to average over (default 28)"
+// @Param lead_days query number false "Days it takes to restock an item (default 3)"
+// @Success 200 {object} models.PantryForecastReport
+// @Failure 400 {object} models.ErrorResponse "User ID is required, invalid history_days or invalid lead_days"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry/forecast [get]
+func HandleGetPantryForecast(users store.UserStore, pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	outlook, leadDays, ok := forecastParams(users, c, userID)
+	if !ok {
+		return
+	}
+
+	items, err := pantryItems.Forecast(ctx, userID, outlook, leadDays)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.PantryForecastReport{
+		HistoryDays: outlook.History,
+		LeadDays:    leadDays,
+		Items:       items,
+	})
+}
+
+// HandleTunePantryThresholds godoc
+// @Summary Tune pantry thresholds
+// @Description Set the threshold of every pantry item with recent use to the one suggested by the forecast for the same history_days and lead_days. Items without recent use keep their threshold.
+// @Tags Pantry
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param history_days query int false "Days of meal history to average over (default 28)"
+// @Param lead_days query number false "Days it takes to restock an item (default 3)"
+// @Success 200 {array} models.PantryItem "Pantry items whose threshold changed"
+// @Failure 400 {object} models.ErrorResponse "User ID is required, invalid history_days or invalid lead_days"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /pantry/forecast/thresholds [post]
+func HandleTunePantryThresholds(users store.UserStore, pantryItems *service.PantryService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	outlook, leadDays, ok := forecastParams(users, c, userID)
+	if !ok {
+		return
+	}
+
+	items, err := pantryItems.TuneThresholds(ctx, userID, outlook, leadDays)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, items)
+}
+
+// bindPantryItemInput binds and validates a pantry item body, trimming the
+// name, normalising the unit and checking the expiry date. A 400 response is
+// written on failure.
+func bindPantryItemInput(c *gin.Context) (models.PantryItemInput, bool) {
+	var input models.PantryItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return input, false
+	}
+
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		writeError(c, http.StatusBadRequest, "Name is required")
+		return input, false
+	}
+
+	unit, ok := pantry.ParseUnit(input.Unit)
+	if !ok || !pantry.IsStockUnit(unit) {
+		writeError(c, http.StatusBadRequest, "Unit must be one of g, kg, ml, l or item")
+		return input, false
+	}
+	input.Unit = unit
+	return input, validExpiry(c, input.ExpiresOn)
+}
+
+// validExpiry checks an optional expiry date, writing a 400 response when it
+// is not a YYYY-MM-DD day.
+func validExpiry(c *gin.Context, expiresOn *string) bool {
+	if expiresOn == nil {
+		return true
+	}
+	if _, err := time.Parse(dateLayout, *expiresOn); err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid expires_on date, expected YYYY-MM-DD")
+		return false
+	}
+	return true
+}
+
+// historyDaysParam reads history_days, the number of days of meals that
+// average daily use is taken over. It defaults to 28.
+func historyDaysParam(c *gin.Context) (int, bool) {
+	days, err := strconv.Atoi(c.DefaultQuery("history_days", "28"))
+	if err != nil || days < 1 || days > 366 {
+		writeError(c, http.StatusBadRequest, "history_days must be a whole number between 1 and 366")
+		return 0, false
+	}
+	return days, true
+}
+
+// forecastParams reads history_days and lead_days for a forecast from now
+// in the user's time zone. A response is written and false returned on
+// failure.
+func forecastParams(users store.UserStore, c *gin.Context, userID int) (pantry.Outlook, float64, bool) {
+	history, ok := historyDaysParam(c)
+	if !ok {
+		return pantry.Outlook{}, 0, false
+	}
+	leadDays, err := strconv.ParseFloat(c.DefaultQuery("lead_days", "3"), 64)
+	if err != nil || leadDays < 0 || leadDays > 90 {
+		writeError(c, http.StatusBadRequest, "lead_days must be a number between 0 and 90")
+		return pantry.Outlook{}, 0, false
+	}
+
+	loc, ok := userLocation(users, c, userID)
+	if !ok {
+		return pantry.Outlook{}, 0, false
+	}
+	return pantry.Outlook{Now: time.Now().In(loc), History: history}, leadDays, true
+}
+
+// outlookParam reads run_out_days, the number of days of projected use the
+// shopping list should cover, and history_days. It defaults to 0, which
+// leaves the projection out and returns a nil outlook.
+func outlookParam(users store.UserStore, c *gin.Context, userID int) (*pantry.Outlook, bool) {
+	days, err := strconv.Atoi(c.DefaultQuery("run_out_days", "0"))
+	if err != nil || days < 0 || days > 366 {
+		writeError(c, http.StatusBadRequest, "run_out_days must be a whole number between 0 and 366")
+		return nil, false
+	}
+	history, ok := historyDaysParam(c)
+	if !ok || days == 0 {
+		return nil, ok
+	}
+
+	loc, ok := userLocation(users, c, userID)
+	if !ok {
+		return nil, false
+	}
+	return &pantry.Outlook{Now: time.Now().In(loc), History: history, Days: float64(days)}, true
+}
@@ -0,0 +1,128 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+func TestCreatePantryItemRejectsDuplicateName(t *testing.T) {
+	s := newServer(t)
+	s.pantryItem("Oats", 750, "g", 3.89)
+
+	w := s.do(http.MethodPost, "/api/pantry", models.PantryItemInput{Name: "Oats", Quantity: 100, Unit: "g"})
+	wantError(t, w, http.StatusConflict, "conflict")
+}
+
+func TestCreatePantryItemValidatesInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input models.PantryItemInput
+		code  string
+	}{
+		{"unknown unit", models.PantryItemInput{Name: "Oats", Unit: "bushel"}, "invalid_request"},
+		{"blank name", models.PantryItemInput{Name: "   ", Unit: "g"}, "invalid_request"},
+		{"negative quantity", models.PantryItemInput{Name: "Oats", Unit: "g", Quantity: -1}, "validation_failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t)
+			wantError(t, s.do(http.MethodPost, "/api/pantry", tt.input), http.StatusBadRequest, tt.code)
+		})
+	}
+}
+
+func TestGetPantryItemNotFound(t *testing.T) {
+	s := newServer(t)
+	wantError(t, s.do(http.MethodGet, "/api/pantry/999", nil), http.StatusNotFound, "not_found")
+	wantError(t, s.do(http.MethodGet, "/api/pantry/abc", nil), http.StatusBadRequest, "invalid_request")
+}
+
+func TestDeletePantryItem(t *testing.T) {
+	s := newServer(t)
+	oats := s.pantryItem("Oats", 750, "g", 3.89)
+	rice := s.pantryItem("Rice", 1000, "g", 1.3)
+
+	w := s.do(http.MethodPost, "/api/meals", models.MealInput{
+		Ingredients: []models.MealIngredientInput{{PantryItemID: oats.ID, QuantityUsed: 80}},
+	})
+	decode[models.Meal](t, w, http.StatusCreated)
+
+	t.Run("in use", func(t *testing.T) {
+		wantError(t, s.do(http.MethodDelete, fmt.Sprintf("/api/pantry/%d", oats.ID), nil), http.StatusConflict, "conflict")
+	})
+	t.Run("unused", func(t *testing.T) {
+		decode[models.SuccessResponse](t, s.do(http.MethodDelete, fmt.Sprintf("/api/pantry/%d", rice.ID), nil), http.StatusOK)
+		wantError(t, s.do(http.MethodGet, fmt.Sprintf("/api/pantry/%d", rice.ID), nil), http.StatusNotFound, "not_found")
+	})
+	t.Run("missing", func(t *testing.T) {
+		wantError(t, s.do(http.MethodDelete, "/api/pantry/999", nil), http.StatusNotFound, "not_found")
+	})
+}
+
+func TestUpdatePantryItemUnit(t *testing.T) {
+	s := newServer(t)
+	oats := s.pantryItem("Oats", 750, "g", 3.89)
+	eggs := s.pantryItem("Eggs", 12, "item", 70)
+	w := s.do(http.MethodPost, "/api/meals", models.MealInput{
+		Ingredients: []models.MealIngredientInput{
+			{PantryItemID: oats.ID, QuantityUsed: 250},
+			{PantryItemID: eggs.ID, QuantityUsed: 2},
+		},
+	})
+	meal := decode[models.Meal](t, w, http.StatusCreated)
+	w = s.do(http.MethodPost, "/api/shopping-list/items", models.ShoppingListItemInput{PantryItemID: &oats.ID, Quantity: ptr(500.0)})
+	entry := decode[models.ShoppingListItem](t, w, http.StatusCreated)
+
+	// Grams convert to kilograms, so the meal and the shopping list entry
+	// are restated in the new unit.
+	w = s.do(http.MethodPut, fmt.Sprintf("/api/pantry/%d", oats.ID), models.PantryItemInput{
+		Name: "Oats", Quantity: 0.5, Unit: "kg", CaloriesPerUnit: 3890,
+	})
+	if got := decode[models.PantryItem](t, w, http.StatusOK); got.Unit != "kg" || got.Quantity != 0.5 {
+		t.Errorf("updated item = %v %s, want 0.5 kg", got.Quantity, got.Unit)
+	}
+	got := decode[models.Meal](t, s.do(http.MethodGet, fmt.Sprintf("/api/meals/%d", meal.ID), nil), http.StatusOK)
+	for _, ing := range got.Ingredients {
+		if ing.PantryItemID == oats.ID && (ing.Unit != "kg" || ing.QuantityUsed != 0.25) {
+			t.Errorf("meal uses %v %s of oats, want 0.25 kg", ing.QuantityUsed, ing.Unit)
+		}
+	}
+	list := decode[[]models.ShoppingListItem](t, s.do(http.MethodGet, "/api/shopping-list?plan_days=0", nil), http.StatusOK)
+	found := false
+	for _, item := range list {
+		if item.ItemID != nil && *item.ItemID == *entry.ItemID {
+			found = true
+			if *item.Quantity != 0.5 || *item.Unit != "kg" {
+				t.Errorf("shopping list entry = %v %s, want 0.5 kg", *item.Quantity, *item.Unit)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("shopping list %+v lacks the entry added by hand", list)
+	}
+
+	// Items do not convert to millilitres, and a meal uses the eggs.
+	w = s.do(http.MethodPut, fmt.Sprintf("/api/pantry/%d", eggs.ID), models.PantryItemInput{Name: "Eggs", Quantity: 10, Unit: "ml"})
+	wantError(t, w, http.StatusConflict, "conflict")
+}
+
+func TestRestockPantryItem(t *testing.T) {
+	s := newServer(t)
+	oats := s.pantryItem("Oats", 100, "g", 3.89)
+	expires := "2025-04-01"
+
+	w := s.do(http.MethodPost, fmt.Sprintf("/api/pantry/%d/restock", oats.ID), models.PantryRestockInput{Quantity: 500, ExpiresOn: &expires})
+	got := decode[models.PantryItem](t, w, http.StatusOK)
+	if got.Quantity != 600 {
+		t.Errorf("quantity = %v, want 600", got.Quantity)
+	}
+	if got.NextExpiry == nil || *got.NextExpiry != expires {
+		t.Errorf("next expiry = %v, want %s", got.NextExpiry, expires)
+	}
+
+	w = s.do(http.MethodPost, "/api/pantry/999/restock", models.PantryRestockInput{Quantity: 1})
+	wantError(t, w, http.StatusNotFound, "not_found")
+}
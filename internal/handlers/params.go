@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/logging"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// requireUserID reads the user_id query parameter, writing a 400 response
+// and returning false when it is missing or malformed.
+func requireUserID(c *gin.Context) (int, bool) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		writeError(c, http.StatusBadRequest, "User ID is required")
+		return 0, false
+	}
+	userIDInt, err := strconv.Atoi(userID)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid user ID format")
+		return 0, false
+	}
+	logging.SetUserID(c, userIDInt)
+	return userIDInt, true
+}
+
+// userLocation loads the time zone from the user's profile, which decides
+// the calendar day things happen on, writing a 500 response on failure.
+func userLocation(users store.UserStore, c *gin.Context, userID int) (*time.Location, bool) {
+	tz, err := users.Timezone(c.Request.Context(), userID)
+	if err != nil {
+		writeInternalError(c, err)
+		return nil, false
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		writeInternalError(c, err)
+		return nil, false
+	}
+	return loc, true
+}
+
+const dateLayout = "2006-01-02"
+
+// maxRangeDays is the longest range parseDateRange accepts, which bounds
+// the work of handlers that report on every day in it.
+const maxRangeDays = 366
+
+// parseDateRange reads the optional from/to query parameters as calendar
+// days in loc. to defaults to today and from to defaultDays-1 days before
+// to. The range may span at most maxRangeDays days. A 400 response is
+// written and false returned on invalid input.
+func parseDateRange(c *gin.Context, loc *time.Location, defaultDays int) (from, to time.Time, ok bool) {
+	now := time.Now().In(loc)
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	var err error
+	if s := c.Query("to"); s != "" {
+		if to, err = time.ParseInLocation(dateLayout, s, loc); err != nil {
+			writeError(c, http.StatusBadRequest, "Invalid to date, expected YYYY-MM-DD")
+			return from, to, false
+		}
+	}
+	from = to.AddDate(0, 0, -(defaultDays - 1))
+	if s := c.Query("from"); s != "" {
+		if from, err = time.ParseInLocation(dateLayout, s, loc); err != nil {
+			writeError(c, http.StatusBadRequest, "Invalid from date, expected YYYY-MM-DD")
+			return from, to, false
+		}
+	}
+	if from.After(to) {
+		writeError(c, http.StatusBadRequest, "from must not be after to")
+		return from, to, false
+	}
+	if !to.Before(from.AddDate(0, 0, maxRangeDays)) {
+		writeFieldError(c, "from", fmt.Sprintf("must be at most %d days before to", maxRangeDays-1))
+		return from, to, false
+	}
+	return from, to, true
+}
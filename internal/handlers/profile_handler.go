@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetUserProfile godoc
+// @Summary Get user profile
+// @Description Retrieve a user's profile. Users without a saved profile get the defaults (metric units, UTC).
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Success 200 {object} models.UserProfile
+// @Failure 400 {object} models.ErrorResponse "Invalid user ID format"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users/{id}/profile [get]
+func HandleGetUserProfile(profiles *service.ProfileService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	profile, err := profiles.Get(ctx, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, profile)
+}
+
+// HandleUpdateUserProfile godoc
+// @Summary Update user profile
+// @Description Create or replace a user's profile. Omitted units default to metric and an omitted timezone to UTC.
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Param profile body models.UserProfileInput true "Profile details"
+// @Success 200 {object} models.UserProfile
+// @Failure 400 {object} models.ErrorResponse "Invalid input or unknown timezone"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users/{id}/profile [put]
+func HandleUpdateUserProfile(profiles *service.ProfileService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var input models.UserProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	profile, err := profiles.Save(ctx, id, input)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, profile)
+}
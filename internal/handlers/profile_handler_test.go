@@ -0,0 +1,31 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+func TestUserProfile(t *testing.T) {
+	s := newServer(t)
+
+	got := decode[models.UserProfile](t, s.do(http.MethodGet, "/api/users/1/profile", nil), http.StatusOK)
+	if got.Timezone != "UTC" || got.PreferredUnits != "metric" {
+		t.Errorf("default profile = %+v, want UTC and metric", got)
+	}
+
+	w := s.do(http.MethodPut, "/api/users/1/profile", models.UserProfileInput{Timezone: "Mars/Olympus"})
+	wantError(t, w, http.StatusBadRequest, "invalid_request")
+
+	w = s.do(http.MethodPut, "/api/users/1/profile", models.UserProfileInput{Timezone: "Australia/Melbourne"})
+	decode[models.UserProfile](t, w, http.StatusOK)
+	got = decode[models.UserProfile](t, s.do(http.MethodGet, "/api/users/1/profile", nil), http.StatusOK)
+	if got.Timezone != "Australia/Melbourne" {
+		t.Errorf("timezone = %q, want Australia/Melbourne", got.Timezone)
+	}
+
+	wantError(t, s.do(http.MethodGet, "/api/users/999/profile", nil), http.StatusNotFound, "not_found")
+	w = s.do(http.MethodPut, "/api/users/999/profile", models.UserProfileInput{Timezone: "UTC"})
+	wantError(t, w, http.StatusNotFound, "not_found")
+}
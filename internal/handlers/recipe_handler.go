@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetRecipes godoc
+// @Summary Get recipes
+// @Description Retrieve a user's recipes with their ingredients, total and per-serving nutrition
+// @Tags Recipes
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Success 200 {array} models.Recipe
+// @Failure 400 {object} models.ErrorResponse "User ID is required"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /recipes [get]
+func HandleGetRecipes(recipes *service.RecipeService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	list, err := recipes.List(ctx, userID)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, list)
+}
+
+// HandleGetRecipe godoc
+// @Summary Get recipe
+// @Description Retrieve a single recipe with its ingredients, total and per-serving nutrition
+// @Tags Recipes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the recipe"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.Recipe
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Recipe not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /recipes/{id} [get]
+func HandleGetRecipe(recipes *service.RecipeService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	recipe, err := recipes.Get(ctx, userID, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, recipe)
+}
+
+// HandleCreateRecipe godoc
+// @Summary Create recipe
+// @Description Create a recipe from pantry items. Ingredient quantities are for the whole recipe, which makes the given number of servings.
+// @Tags Recipes
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param recipe body models.RecipeInput true "Recipe details"
+// @Success 201 {object} models.Recipe
+// @Failure 400 {object} models.ErrorResponse "User ID is required, invalid input, unknown pantry item or unit that does not convert"
+// @Failure 409 {object} models.ErrorResponse "Recipe with this name already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /recipes [post]
+func HandleCreateRecipe(recipes *service.RecipeService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	input, ok := bindRecipeInput(c)
+	if !ok {
+		return
+	}
+
+	recipe, err := recipes.Create(ctx, userID, input)
+	if err != nil {
+		writeMealError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, recipe)
+}
+
+// HandleUpdateRecipe godoc
+// @Summary Update recipe
+// @Description Replace a recipe's name, servings, notes and ingredients. Meals already logged from it are not changed.
+// @Tags Recipes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the recipe"
+// @Param user_id query int true "ID of the user"
+// @Param recipe body models.RecipeInput true "Recipe details"
+// @Success 200 {object} models.Recipe
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format, invalid input, unknown pantry item or unit that does not convert"
+// @Failure 404 {object} models.ErrorResponse "Recipe not found"
+// @Failure 409 {object} models.ErrorResponse "Recipe with this name already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /recipes/{id} [put]
+func HandleUpdateRecipe(recipes *service.RecipeService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	input, ok := bindRecipeInput(c)
+	if !ok {
+		return
+	}
+
+	recipe, err := recipes.Update(ctx, userID, id, input)
+	if err != nil {
+		writeMealError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, recipe)
+}
+
+// HandleDeleteRecipe godoc
+// @Summary Delete recipe
+// @Description Delete a recipe. Meals already logged from it are kept; planned servings of it are removed from the meal plan.
+// @Tags Recipes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the recipe"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.SuccessResponse "Recipe deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Recipe not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /recipes/{id} [delete]
+func HandleDeleteRecipe(recipes *service.RecipeService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	err = recipes.Delete(ctx, userID, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Recipe deleted successfully"})
+}
+
+// HandleLogRecipe godoc
+// @Summary Log servings of a recipe
+// @Description Log a meal of the given number of servings of a recipe. Each ingredient is scaled from the recipe's servings and deducted from the pantry in the same transaction; nothing is logged if any item does not hold enough stock.
+// @Tags Recipes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the recipe"
+// @Param user_id query int true "ID of the user"
+// @Param log body models.LogRecipeInput true "Servings eaten"
+// @Success 201 {object} models.Meal
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format or invalid input"
+// @Failure 404 {object} models.ErrorResponse "Recipe not found"
+// @Failure 409 {object} models.InsufficientStockResponse "Insufficient pantry stock"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /recipes/{id}/log [post]
+func HandleLogRecipe(recipes *service.RecipeService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.LogRecipeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	eatenAt := time.Now()
+	if input.EatenAt != nil {
+		eatenAt = *input.EatenAt
+	}
+
+	meal, err := recipes.Log(ctx, userID, id, input.Servings, eatenAt)
+	if err != nil {
+		writeMealError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, meal)
+}
+
+// bindRecipeInput binds a recipe body, trimming the name. A 400 response is
+// written on failure.
+func bindRecipeInput(c *gin.Context) (models.RecipeInput, bool) {
+	var input models.RecipeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return input, false
+	}
+
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		writeError(c, http.StatusBadRequest, "Name is required")
+		return input, false
+	}
+	return input, true
+}
@@ -0,0 +1,112 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+func TestRecipeNames(t *testing.T) {
+	s := newServer(t)
+	rice := s.pantryItem("Rice", 1000, "g", 1.3)
+	ingredients := []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 300}}
+
+	bowl := decode[models.Recipe](t, s.do(http.MethodPost, "/api/recipes",
+		models.RecipeInput{Name: "Rice bowl", Servings: 2, Ingredients: ingredients}), http.StatusCreated)
+	curry := decode[models.Recipe](t, s.do(http.MethodPost, "/api/recipes",
+		models.RecipeInput{Name: "Curry", Servings: 4, Ingredients: ingredients}), http.StatusCreated)
+
+	w := s.do(http.MethodPost, "/api/recipes", models.RecipeInput{Name: "Rice bowl", Servings: 1, Ingredients: ingredients})
+	wantError(t, w, http.StatusConflict, "conflict")
+
+	w = s.do(http.MethodPut, fmt.Sprintf("/api/recipes/%d", curry.ID),
+		models.RecipeInput{Name: "Rice bowl", Servings: 4, Ingredients: ingredients})
+	wantError(t, w, http.StatusConflict, "conflict")
+
+	// A recipe keeps its own name.
+	w = s.do(http.MethodPut, fmt.Sprintf("/api/recipes/%d", bowl.ID),
+		models.RecipeInput{Name: "Rice bowl", Servings: 3, Ingredients: ingredients})
+	if got := decode[models.Recipe](t, w, http.StatusOK); got.Servings != 3 {
+		t.Errorf("servings = %v, want 3", got.Servings)
+	}
+
+	w = s.do(http.MethodPut, "/api/recipes/999", models.RecipeInput{Name: "Stew", Servings: 1, Ingredients: ingredients})
+	wantError(t, w, http.StatusNotFound, "not_found")
+}
+
+func TestCreateRecipeConvertsUnits(t *testing.T) {
+	s := newServer(t)
+	chicken := s.pantryItem("Chicken breast", 2000, "g", 1.65)
+	rice := s.pantryItem("Rice", 1000, "g", 1.3)
+
+	w := s.do(http.MethodPost, "/api/recipes", models.RecipeInput{
+		Name:     "Chicken rice bowl",
+		Servings: 4,
+		Ingredients: []models.RecipeIngredientInput{
+			{PantryItemID: chicken.ID, Quantity: 0.6, Unit: "kg"},
+			{PantryItemID: rice.ID, Quantity: 300},
+			{PantryItemID: rice.ID, Quantity: 100},
+		},
+	})
+	r := decode[models.Recipe](t, w, http.StatusCreated)
+
+	want := map[int]float64{chicken.ID: 600, rice.ID: 400}
+	if len(r.Ingredients) != len(want) {
+		t.Fatalf("ingredients = %+v, want one per pantry item", r.Ingredients)
+	}
+	for _, ing := range r.Ingredients {
+		if ing.Quantity != want[ing.PantryItemID] {
+			t.Errorf("%s quantity = %v, want %v", ing.Name, ing.Quantity, want[ing.PantryItemID])
+		}
+	}
+	if calories := 600*1.65 + 400*1.3; r.PerServing.Calories != calories/4 {
+		t.Errorf("calories per serving = %v, want %v", r.PerServing.Calories, calories/4)
+	}
+
+	w = s.do(http.MethodPost, "/api/recipes", models.RecipeInput{
+		Name:        "Mystery",
+		Servings:    1,
+		Ingredients: []models.RecipeIngredientInput{{PantryItemID: 999, Quantity: 1}},
+	})
+	wantError(t, w, http.StatusBadRequest, "invalid_request")
+}
+
+func TestLogRecipe(t *testing.T) {
+	s := newServer(t)
+	rice := s.pantryItem("Rice", 500, "g", 1.3)
+	r := decode[models.Recipe](t, s.do(http.MethodPost, "/api/recipes", models.RecipeInput{
+		Name:        "Rice bowl",
+		Servings:    4,
+		Ingredients: []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 400}},
+	}), http.StatusCreated)
+
+	w := s.do(http.MethodPost, fmt.Sprintf("/api/recipes/%d/log", r.ID), models.LogRecipeInput{Servings: 1.5})
+	meal := decode[models.Meal](t, w, http.StatusCreated)
+	if len(meal.Ingredients) != 1 || meal.Ingredients[0].QuantityUsed != 150 {
+		t.Errorf("meal ingredients = %+v, want 150 g of rice", meal.Ingredients)
+	}
+
+	w = s.do(http.MethodPost, fmt.Sprintf("/api/recipes/%d/log", r.ID), models.LogRecipeInput{Servings: 4})
+	wantError(t, w, http.StatusConflict, "insufficient_stock")
+
+	w = s.do(http.MethodPost, "/api/recipes/999/log", models.LogRecipeInput{Servings: 1})
+	wantError(t, w, http.StatusNotFound, "not_found")
+}
+
+func TestDeleteRecipe(t *testing.T) {
+	s := newServer(t)
+	rice := s.pantryItem("Rice", 500, "g", 1.3)
+	r := decode[models.Recipe](t, s.do(http.MethodPost, "/api/recipes", models.RecipeInput{
+		Name:        "Rice bowl",
+		Servings:    1,
+		Ingredients: []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 100}},
+	}), http.StatusCreated)
+
+	// The recipe uses the rice until it is deleted.
+	wantError(t, s.do(http.MethodDelete, fmt.Sprintf("/api/pantry/%d", rice.ID), nil), http.StatusConflict, "conflict")
+	decode[models.SuccessResponse](t, s.do(http.MethodDelete, fmt.Sprintf("/api/recipes/%d", r.ID), nil), http.StatusOK)
+	wantError(t, s.do(http.MethodGet, fmt.Sprintf("/api/recipes/%d", r.ID), nil), http.StatusNotFound, "not_found")
+	decode[models.SuccessResponse](t, s.do(http.MethodDelete, fmt.Sprintf("/api/pantry/%d", rice.ID), nil), http.StatusOK)
+}
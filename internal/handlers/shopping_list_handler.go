@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetShoppingList godoc
+// @Summary Get shopping list
+// @Description Retrieve a user's shopping list: pantry items below their threshold, pantry items the meal plan needs more of, optionally pantry items recent meals are projected to take below their threshold within run_out_days, and items added by hand. format=text or format=markdown returns a shareable checklist instead of JSON.
+// @Tags ShoppingList
+// @Accept json
+// @Produce json,plain,markdown
+// @Param user_id query int true "ID of the user"
+// @Param format query string false "json (default), text or markdown"
+// @Param plan_days query int false "Days of the meal plan, starting today, to shop for (default 7, 0 to leave the plan out)"
+// @Param run_out_days query int false "Also list items projected to run low within this many days (default 0, off)"
+// @Param history_days query int false "Days of meal history the projection averages over (default 28)"
+// @Success 200 {array} models.ShoppingListItem
+// @Failure 400 {object} models.ErrorResponse "User ID is required, unknown format, invalid plan_days, run_out_days or history_days"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /shopping-list [get]
+func HandleGetShoppingList(users store.UserStore, list *service.ShoppingListService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "text" && format != "markdown" {
+		writeError(c, http.StatusBadRequest, "Format must be json, text or markdown")
+		return
+	}
+
+	plan, ok := planWindowParam(users, c, userID)
+	if !ok {
+		return
+	}
+
+	outlook, ok := outlookParam(users, c, userID)
+	if !ok {
+		return
+	}
+
+	items, err := list.List(ctx, userID, plan, outlook)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	switch format {
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(pantry.FormatShoppingList(items, false)))
+	case "markdown":
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(pantry.FormatShoppingList(items, true)))
+	default:
+		c.IndentedJSON(http.StatusOK, items)
+	}
+}
+
+// HandleAddShoppingListItem godoc
+// @Summary Add shopping list item
+// @Description Add an item to the shopping list by hand. Items tied to a pantry item use its name and unit and restock it when purchased.
+// @Tags ShoppingList
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param item body models.ShoppingListItemInput true "Item details"
+// @Success 201 {object} models.ShoppingListItem
+// @Failure 400 {object} models.ErrorResponse "User ID is required, invalid input or unknown pantry item"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /shopping-list/items [post]
+func HandleAddShoppingListItem(list *service.ShoppingListService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.ShoppingListItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	item, err := list.Add(ctx, userID, input)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, item)
+}
+
+// HandleCheckShoppingListItem godoc
+// @Summary Check off shopping list item
+// @Description Set the checked state of an item added by hand
+// @Tags ShoppingList
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the shopping list item"
+// @Param user_id query int true "ID of the user"
+// @Param check body models.ShoppingListCheckInput true "Checked state"
+// @Success 200 {object} models.SuccessResponse "Shopping list item updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format or invalid input"
+// @Failure 404 {object} models.ErrorResponse "Shopping list item not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /shopping-list/items/{id}/check [put]
+func HandleCheckShoppingListItem(list *service.ShoppingListService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.ShoppingListCheckInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	if err := list.Check(ctx, userID, id, input.Checked); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Shopping list item updated successfully"})
+}
+
+// HandleCheckShoppingListPantryItem godoc
+// @Summary Check off pantry item
+// @Description Set the checked state of a shopping list row that comes from a pantry item below its threshold or needed by the meal plan
+// @Tags ShoppingList
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the pantry item"
+// @Param user_id query int true "ID of the user"
+// @Param check body models.ShoppingListCheckInput true "Checked state"
+// @Success 200 {object} models.SuccessResponse "Shopping list item updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format or invalid input"
+// @Failure 404 {object} models.ErrorResponse "Pantry item not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /shopping-list/pantry/{id}/check [put]
+func HandleCheckShoppingListPantryItem(list *service.ShoppingListService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.ShoppingListCheckInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	if err := list.CheckPantryItem(ctx, userID, id, input.Checked); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Shopping list item updated successfully"})
+}
+
+// HandleDeleteShoppingListItem godoc
+// @Summary Delete shopping list item
+// @Description Remove an item added by hand from the shopping list
+// @Tags ShoppingList
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the shopping list item"
+// @Param user_id query int true "ID of the user"
+// @Success 200 {object} models.SuccessResponse "Shopping list item deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} models.ErrorResponse "Shopping list item not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /shopping-list/items/{id} [delete]
+func HandleDeleteShoppingListItem(list *service.ShoppingListService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := list.Delete(ctx, userID, id); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Shopping list item deleted successfully"})
+}
+
+// HandlePurchaseShoppingList godoc
+// @Summary Purchase checked items
+// @Description Mark every checked row of the shopping list as purchased. Low-stock pantry items are restocked up to their threshold plus what the meal plan or the projection uses, hand-added items tied to a pantry item add their quantity, and checked rows are cleared.
+// @Tags ShoppingList
+// @Accept json
+// @Produce json
+// @Param user_id query int true "ID of the user"
+// @Param plan_days query int false "Days of the meal plan, starting today, that were shopped for (default 7, 0 to leave the plan out)"
+// @Param run_out_days query int false "Days of projected use that were shopped for (default 0, off)"
+// @Param history_days query int false "Days of meal history the projection averages over (default 28)"
+// @Success 200 {object} models.ShoppingListPurchase
+// @Failure 400 {object} models.ErrorResponse "User ID is required, invalid plan_days, run_out_days or history_days"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /shopping-list/purchase [post]
+func HandlePurchaseShoppingList(users store.UserStore, list *service.ShoppingListService, c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	plan, ok := planWindowParam(users, c, userID)
+	if !ok {
+		return
+	}
+	outlook, ok := outlookParam(users, c, userID)
+	if !ok {
+		return
+	}
+
+	purchase, err := list.Purchase(ctx, userID, plan, outlook)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, purchase)
+}
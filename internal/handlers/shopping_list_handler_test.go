@@ -0,0 +1,72 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+func TestAddShoppingListItem(t *testing.T) {
+	s := newServer(t)
+	oats := s.pantryItem("Oats", 750, "g", 3.89)
+	kg := "kg"
+
+	tests := []struct {
+		name   string
+		input  models.ShoppingListItemInput
+		status int
+		want   string
+	}{
+		{"by hand", models.ShoppingListItemInput{Name: " Bananas "}, http.StatusCreated, "Bananas"},
+		{"pantry item", models.ShoppingListItemInput{PantryItemID: &oats.ID, Quantity: ptr(500.0)}, http.StatusCreated, "Oats"},
+		{"unknown pantry item", models.ShoppingListItemInput{PantryItemID: ptr(999)}, http.StatusBadRequest, ""},
+		{"other unit", models.ShoppingListItemInput{PantryItemID: &oats.ID, Unit: &kg}, http.StatusBadRequest, ""},
+		{"nothing", models.ShoppingListItemInput{}, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPost, "/api/shopping-list/items", tt.input)
+			if tt.status != http.StatusCreated {
+				wantError(t, w, tt.status, "invalid_request")
+				return
+			}
+			item := decode[models.ShoppingListItem](t, w, tt.status)
+			if item.Name != tt.want || item.Source != "manual" {
+				t.Errorf("item = %q from %q, want %q from manual", item.Name, item.Source, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurchaseShoppingList(t *testing.T) {
+	s := newServer(t)
+	w := s.do(http.MethodPost, "/api/pantry", models.PantryItemInput{Name: "Oats", Quantity: 100, Unit: "g", Threshold: 500})
+	oats := decode[models.PantryItem](t, w, http.StatusCreated)
+	w = s.do(http.MethodPost, "/api/pantry", models.PantryItemInput{Name: "Milk", Quantity: 0.5, Unit: "l", Threshold: 1})
+	milk := decode[models.PantryItem](t, w, http.StatusCreated)
+	decode[models.ShoppingListItem](t, s.do(http.MethodPost, "/api/shopping-list/items",
+		models.ShoppingListItemInput{Name: "Bananas"}), http.StatusCreated)
+
+	list := decode[[]models.ShoppingListItem](t, s.do(http.MethodGet, "/api/shopping-list?plan_days=0", nil), http.StatusOK)
+	if len(list) != 3 {
+		t.Fatalf("list = %+v, want milk, oats and bananas", list)
+	}
+
+	w = s.do(http.MethodPut, fmt.Sprintf("/api/shopping-list/pantry/%d/check", oats.ID), models.ShoppingListCheckInput{Checked: true})
+	decode[models.SuccessResponse](t, w, http.StatusOK)
+	w = s.do(http.MethodPut, "/api/shopping-list/pantry/999/check", models.ShoppingListCheckInput{Checked: true})
+	wantError(t, w, http.StatusNotFound, "not_found")
+
+	purchase := decode[models.ShoppingListPurchase](t, s.do(http.MethodPost, "/api/shopping-list/purchase?plan_days=0", nil), http.StatusOK)
+	if len(purchase.Restocked) != 1 || purchase.Restocked[0].ID != oats.ID || purchase.Restocked[0].Quantity != 500 {
+		t.Errorf("restocked = %+v, want oats up to 500 g", purchase.Restocked)
+	}
+
+	// Only the unchecked milk and bananas are left.
+	list = decode[[]models.ShoppingListItem](t, s.do(http.MethodGet, "/api/shopping-list?plan_days=0", nil), http.StatusOK)
+	if len(list) != 2 || list[0].Name != "Bananas" || list[1].PantryItemID == nil || *list[1].PantryItemID != milk.ID {
+		t.Errorf("list after purchase = %+v", list)
+	}
+}
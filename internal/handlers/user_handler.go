@@ -1,12 +1,13 @@
 package handlers
 
 import (
-	"database/sql"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // HandleGetUsers godoc
@@ -18,23 +19,14 @@ import (
 // @Success 200 {array} models.User
 // @Failure 500 {object} models.ErrorResponse
 // @Router /users [get]
-func HandleGetUsers(db *sql.DB, c *gin.Context) {
-	rows, err := db.Query("SELECT id, email, created_at, updated_at FROM users")
+func HandleGetUsers(users *service.UserService, c *gin.Context) {
+	ctx := c.Request.Context()
+	list, err := users.List(ctx)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Error fetching data"})
+		writeServiceError(c, err)
 		return
 	}
-	defer rows.Close()
-	var users []models.User
-	for rows.Next() {
-		var u models.User
-		if err := rows.Scan(&u.ID, &u.Email, &u.CreatedAt, &u.UpdatedAt); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		users = append(users, u)
-	}
-	c.JSON(http.StatusOK, users)
+	c.JSON(http.StatusOK, list)
 }
 
 // HandleCreateUser godoc
@@ -46,24 +38,234 @@ func HandleGetUsers(db *sql.DB, c *gin.Context) {
 // @Param user body models.User true "User information"
 // @Success 201 {object} models.User
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Email is already in use"
 // @Failure 500 {object} models.ErrorResponse
 // @Router /users [post]
-func HandleCreateUser(db *sql.DB, c *gin.Context) {
+func HandleCreateUser(users *service.UserService, c *gin.Context) {
+	ctx := c.Request.Context()
 	var user models.User
-	if err := c.BindJSON(&user); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBindJSON(&user); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	created, err := users.Create(ctx, user.Email, user.PasswordHash)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// HandleGetUser godoc
+// @Summary Get user
+// @Description Returns a single user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Success 200 {object} models.User
+// @Failure 400 {object} models.ErrorResponse "Invalid user ID format"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id} [get]
+func HandleGetUser(users *service.UserService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	u, err := users.Get(ctx, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, u)
+}
+
+// HandleUpdateUserEmail godoc
+// @Summary Change email
+// @Description Changes the user's email address after re-checking their current password
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Param input body models.UpdateEmailInput true "New email and current password"
+// @Success 200 {object} models.User
+// @Failure 400 {object} models.ErrorResponse "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Current password is incorrect"
+// @Failure 403 {object} models.ErrorResponse "Account is disabled"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 409 {object} models.ErrorResponse "Email is already in use"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id}/email [put]
+func HandleUpdateUserEmail(users *service.UserService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var input models.UpdateEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
 		return
 	}
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.PasswordHash), bcrypt.DefaultCost)
+
+	u, err := users.UpdateEmail(ctx, id, input.Email, input.CurrentPassword)
 	if err != nil {
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Error hashing password"})
+		writeServiceError(c, err)
+		return
 	}
-	query := "INSERT INTO users (email, password_hash, created_at, updated_at) VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id;"
-	err = db.QueryRow(query, user.Email, string(hashedPassword)).Scan(&user.ID)
+
+	c.IndentedJSON(http.StatusOK, u)
+}
+
+// HandleUpdateUserPassword godoc
+// @Summary Change password
+// @Description Changes the user's password after re-checking their current password
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Param input body models.UpdatePasswordInput true "Current and new password"
+// @Success 200 {object} models.SuccessResponse "Password updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Current password is incorrect"
+// @Failure 403 {object} models.ErrorResponse "Account is disabled"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id}/password [put]
+func HandleUpdateUserPassword(users *service.UserService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(c, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var input models.UpdatePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
 		return
 	}
-	user.PasswordHash = ""
-	c.JSON(http.StatusCreated, user)
+
+	if err := users.UpdatePassword(ctx, id, input.CurrentPassword, input.NewPassword); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{Success: "Password updated successfully"})
+}
+
+// HandleDeleteUser godoc
+// @Summary Delete account
+// @Description Schedules the account for deletion after a grace period. The user's data can be exported from export_url until the account is purged, and the request can be cancelled in the meantime.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Param input body models.DeleteAccountInput true "Current password"
+// @Success 202 {object} models.AccountDeletionStatus
+// @Failure 400 {object} models.ErrorResponse "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Current password is incorrect"
+// @Failure 403 {object} models.ErrorResponse "Account is disabled"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id} [delete]
+func HandleDeleteUser(users *service.UserService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var input models.DeleteAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	status, err := users.ScheduleDeletion(ctx, id, input.Password)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	status.ExportURL = fmt.Sprintf("/api/export?user_id=%d", id)
+
+	c.IndentedJSON(http.StatusAccepted, status)
+}
+
+// HandleGetAccountDeletion godoc
+// @Summary Get account deletion status
+// @Description Returns whether the account is scheduled for deletion and when it will be purged
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Success 200 {object} models.AccountDeletionStatus
+// @Failure 400 {object} models.ErrorResponse "Invalid user ID format"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id}/deletion [get]
+func HandleGetAccountDeletion(users *service.UserService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	status, err := users.DeletionStatus(ctx, id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	if status.Pending {
+		status.ExportURL = fmt.Sprintf("/api/export?user_id=%d", id)
+	}
+
+	c.IndentedJSON(http.StatusOK, status)
+}
+
+// HandleCancelAccountDeletion godoc
+// @Summary Cancel account deletion
+// @Description Cancels a pending account deletion during the grace period
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Param input body models.DeleteAccountInput true "Current password"
+// @Success 200 {object} models.AccountDeletionStatus
+// @Failure 400 {object} models.ErrorResponse "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Current password is incorrect"
+// @Failure 403 {object} models.ErrorResponse "Account is disabled"
+// @Failure 404 {object} models.ErrorResponse "User not found or no deletion pending"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id}/deletion [delete]
+func HandleCancelAccountDeletion(users *service.UserService, c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var input models.DeleteAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	if err := users.CancelDeletion(ctx, id, input.Password); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.AccountDeletionStatus{UserID: id})
 }
@@ -0,0 +1,371 @@
+// Package importer loads workout history exported by other tracking apps
+// (Strong, Hevy, FitNotes) into workout_sessions and workout_exercises.
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+var (
+	ErrUnmapped     = errors.New("some exercises could not be mapped")
+	ErrNoSets       = errors.New("no sets found in import file")
+	ErrUserNotFound = errors.New("user not found")
+	ErrGymNotFound  = errors.New("gym not found for this user")
+)
+
+type Options struct {
+	Format   Format
+	GymID    int
+	Mapping  *Mapping
+	DryRun   bool
+	Location *time.Location
+	// Resolve is consulted for exercise names that neither the mapping nor
+	// the exercises table can resolve. It returns the name of the exercise to
+	// use, or "" to leave the name unmapped.
+	Resolve func(name string) (string, error)
+}
+
+type resolved struct {
+	exerciseID     int
+	gymEquipmentID int
+}
+
+type importer struct {
+	tx        *sql.Tx
+	userID    int
+	gymID     int
+	opts      Options
+	report    *models.ImportReport
+	equipment map[string]int
+}
+
+// Import parses r and inserts its sessions for userID inside a single
+// transaction. With DryRun set the transaction is rolled back after the
+// report has been built, so the report reflects exactly what would be
+// written. When exercises remain unmapped outside a dry run nothing is
+// written and ErrUnmapped is returned together with the report.
+func Import(ctx context.Context, db *sql.DB, userID int, r io.Reader, opts Options) (*models.ImportReport, error) {
+	sets, format, skipped, err := Parse(r, opts.Format, opts.Location)
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 {
+		return nil, ErrNoSets
+	}
+
+	report := &models.ImportReport{
+		Format:                string(format),
+		DryRun:                opts.DryRun,
+		SkippedRows:           skipped,
+		CreatedExercises:      []string{},
+		CreatedEquipmentTypes: []string{},
+		Unmapped:              []string{},
+		Warnings:              []string{},
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", userID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+
+	imp := &importer{
+		tx:        tx,
+		userID:    userID,
+		opts:      opts,
+		report:    report,
+		equipment: map[string]int{},
+	}
+	if err := imp.resolveGym(ctx, format); err != nil {
+		return nil, err
+	}
+
+	names := map[string]*resolved{}
+	for _, s := range sets {
+		if _, seen := names[s.Name]; seen {
+			continue
+		}
+		res, err := imp.resolveExercise(ctx, s.Name)
+		if err != nil {
+			return nil, err
+		}
+		names[s.Name] = res
+		if res == nil {
+			report.Unmapped = append(report.Unmapped, s.Name)
+		}
+	}
+	if len(report.Unmapped) > 0 && !opts.DryRun {
+		return report, ErrUnmapped
+	}
+
+	if err := imp.insertSessions(ctx, sets, names); err != nil {
+		return nil, err
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (imp *importer) resolveGym(ctx context.Context, format Format) error {
+	if imp.opts.GymID != 0 {
+		var exists bool
+		err := imp.tx.QueryRowContext(ctx,
+			"SELECT EXISTS(SELECT 1 FROM gyms WHERE id = $1 AND user_id = $2)",
+			imp.opts.GymID, imp.userID,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrGymNotFound
+		}
+		imp.gymID = imp.opts.GymID
+		imp.report.GymID = imp.gymID
+		return nil
+	}
+
+	name := "Imported from " + formatTitle(format)
+	err := imp.tx.QueryRowContext(ctx, "SELECT id FROM gyms WHERE user_id = $1 AND name = $2 LIMIT 1", imp.userID, name).Scan(&imp.gymID)
+	if err == sql.ErrNoRows {
+		err = imp.tx.QueryRowContext(ctx, "INSERT INTO gyms (user_id, name) VALUES ($1, $2) RETURNING id", imp.userID, name).Scan(&imp.gymID)
+		imp.report.CreatedGym = name
+	}
+	if err != nil {
+		return err
+	}
+	imp.report.GymID = imp.gymID
+	return nil
+}
+
+func (imp *importer) resolveExercise(ctx context.Context, raw string) (*resolved, error) {
+	base, equipment := splitEquipment(raw)
+	mapping := imp.opts.Mapping
+
+	candidates := []string{raw, base}
+	create := base
+	if target, ok := mapping.exercise(raw); ok {
+		candidates = []string{target}
+		create = target
+	}
+
+	exerciseID, err := imp.findExercise(ctx, candidates...)
+	if err != nil {
+		return nil, err
+	}
+
+	if exerciseID == 0 && imp.opts.Resolve != nil && (mapping == nil || !mapping.CreateMissing) {
+		target, err := imp.opts.Resolve(raw)
+		if err != nil {
+			return nil, err
+		}
+		if target == "" {
+			return nil, nil
+		}
+		if exerciseID, err = imp.findExercise(ctx, target); err != nil {
+			return nil, err
+		}
+		if exerciseID == 0 {
+			if exerciseID, err = imp.createExercise(ctx, target); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if exerciseID == 0 {
+		if mapping == nil || !mapping.CreateMissing {
+			return nil, nil
+		}
+		if exerciseID, err = imp.createExercise(ctx, create); err != nil {
+			return nil, err
+		}
+	}
+
+	gymEquipmentID, err := imp.resolveEquipment(ctx, mapping.equipment(equipment))
+	if err != nil {
+		return nil, err
+	}
+
+	return &resolved{exerciseID: exerciseID, gymEquipmentID: gymEquipmentID}, nil
+}
+
+func (imp *importer) findExercise(ctx context.Context, names ...string) (int, error) {
+	for _, name := range names {
+		var id int
+		err := imp.tx.QueryRowContext(ctx, "SELECT id FROM exercises WHERE LOWER(name) = LOWER($1)", name).Scan(&id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+	return 0, nil
+}
+
+func (imp *importer) createExercise(ctx context.Context, name string) (int, error) {
+	var id int
+	if err := imp.tx.QueryRowContext(ctx, "INSERT INTO exercises (name) VALUES ($1) RETURNING id", name).Scan(&id); err != nil {
+		return 0, fmt.Errorf("creating exercise %q: %w", name, err)
+	}
+	imp.report.CreatedExercises = append(imp.report.CreatedExercises, name)
+	return id, nil
+}
+
+// resolveEquipment returns a gym_equipment row of the given type in the
+// import gym, creating the equipment type and a weightless placeholder row
+// where needed.
+func (imp *importer) resolveEquipment(ctx context.Context, name string) (int, error) {
+	key := strings.ToLower(name)
+	if id, ok := imp.equipment[key]; ok {
+		return id, nil
+	}
+
+	var typeID int
+	err := imp.tx.QueryRowContext(ctx, "SELECT id FROM equipment_types WHERE LOWER(name) = LOWER($1)", name).Scan(&typeID)
+	if err == sql.ErrNoRows {
+		err = imp.tx.QueryRowContext(ctx, "INSERT INTO equipment_types (name) VALUES ($1) RETURNING id", name).Scan(&typeID)
+		imp.report.CreatedEquipmentTypes = append(imp.report.CreatedEquipmentTypes, name)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = imp.tx.QueryRowContext(ctx,
+		"SELECT id FROM gym_equipment WHERE gym_id = $1 AND equipment_type_id = $2 ORDER BY weight IS NOT NULL, id LIMIT 1",
+		imp.gymID, typeID,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		err = imp.tx.QueryRowContext(ctx,
+			"INSERT INTO gym_equipment (gym_id, equipment_type_id, notes) VALUES ($1, $2, $3) RETURNING id",
+			imp.gymID, typeID, "Created by import",
+		).Scan(&id)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	imp.equipment[key] = id
+	return id, nil
+}
+
+type entry struct {
+	res    *resolved
+	weight float64
+	reps   int
+	sets   int
+}
+
+type session struct {
+	start   time.Time
+	entries []*entry
+}
+
+// insertSessions groups sets by workout start time and collapses
+// consecutive identical sets into a single workout_exercises row.
+func (imp *importer) insertSessions(ctx context.Context, sets []Set, names map[string]*resolved) error {
+	var order []*session
+	byStart := map[int64]*session{}
+	for _, s := range sets {
+		sess, ok := byStart[s.Start.Unix()]
+		if !ok {
+			sess = &session{start: s.Start}
+			byStart[s.Start.Unix()] = sess
+			order = append(order, sess)
+		}
+
+		res := names[s.Name]
+		if res == nil {
+			continue
+		}
+		weight := math.Round(s.Weight*100) / 100
+		if n := len(sess.entries); n > 0 {
+			last := sess.entries[n-1]
+			if last.res == res && last.weight == weight && last.reps == s.Reps {
+				last.sets++
+				continue
+			}
+		}
+		sess.entries = append(sess.entries, &entry{res: res, weight: weight, reps: s.Reps, sets: 1})
+	}
+
+	for _, sess := range order {
+		if len(sess.entries) == 0 {
+			continue
+		}
+
+		var exists bool
+		err := imp.tx.QueryRowContext(ctx,
+			"SELECT EXISTS(SELECT 1 FROM workout_sessions WHERE user_id = $1 AND created_at = $2)",
+			imp.userID, sess.start,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			imp.report.SkippedSessions++
+			continue
+		}
+
+		var sessionID int
+		err = imp.tx.QueryRowContext(ctx,
+			"INSERT INTO workout_sessions (user_id, gym_id, created_at) VALUES ($1, $2, $3) RETURNING id",
+			imp.userID, imp.gymID, sess.start,
+		).Scan(&sessionID)
+		if err != nil {
+			return err
+		}
+		imp.report.Sessions++
+
+		for _, e := range sess.entries {
+			_, err := imp.tx.ExecContext(ctx,
+				`INSERT INTO workout_exercises
+                (workout_session_id, exercise_id, gym_equipment_id, weight, reps, sets, created_at)
+                VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+				sessionID, e.res.exerciseID, e.res.gymEquipmentID, e.weight, e.reps, e.sets, sess.start,
+			)
+			if err != nil {
+				return err
+			}
+			imp.report.ExerciseEntries++
+			imp.report.Sets += e.sets
+		}
+	}
+
+	return nil
+}
+
+func formatTitle(f Format) string {
+	switch f {
+	case FormatStrong:
+		return "Strong"
+	case FormatHevy:
+		return "Hevy"
+	case FormatFitNotes:
+		return "FitNotes"
+	}
+	return string(f)
+}
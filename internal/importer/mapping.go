@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Mapping holds the rules used to resolve exercise and equipment names found
+// in an export file. It is loaded from a JSON document such as:
+//
+//	{
+//	  "exercises": {"Bench Press (Barbell)": "Bench Press"},
+//	  "equipment": {"Machine": "Cable Machine"},
+//	  "default_equipment": "Unspecified",
+//	  "create_missing": true
+//	}
+type Mapping struct {
+	Exercises        map[string]string `json:"exercises"`
+	Equipment        map[string]string `json:"equipment"`
+	DefaultEquipment string            `json:"default_equipment"`
+	CreateMissing    bool              `json:"create_missing"`
+}
+
+const defaultEquipmentName = "Unspecified"
+
+func LoadMapping(r io.Reader) (*Mapping, error) {
+	var m Mapping
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (m *Mapping) exercise(name string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	for k, v := range m.Exercises {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func (m *Mapping) equipment(name string) string {
+	if name == "" {
+		if m != nil && m.DefaultEquipment != "" {
+			return m.DefaultEquipment
+		}
+		return defaultEquipmentName
+	}
+	if m != nil {
+		for k, v := range m.Equipment {
+			if strings.EqualFold(k, name) {
+				return v
+			}
+		}
+	}
+	return name
+}
+
+// splitEquipment separates the "(Barbell)" style suffix that Strong and Hevy
+// append to exercise names.
+func splitEquipment(name string) (base, equipment string) {
+	name = strings.TrimSpace(name)
+	if strings.HasSuffix(name, ")") {
+		if i := strings.LastIndex(name, " ("); i > 0 {
+			return strings.TrimSpace(name[:i]), strings.TrimSpace(name[i+2 : len(name)-1])
+		}
+	}
+	return name, ""
+}
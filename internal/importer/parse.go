@@ -0,0 +1,332 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatAuto     Format = "auto"
+	FormatStrong   Format = "strong"
+	FormatHevy     Format = "hevy"
+	FormatFitNotes Format = "fitnotes"
+)
+
+const lbsToKg = 0.45359237
+
+var (
+	ErrUnknownFormat = errors.New("unrecognised CSV export format")
+	ErrInvalidFile   = errors.New("invalid import file")
+)
+
+// Set is a single logged set as it appears in an export file, before
+// exercise names have been resolved against the database.
+type Set struct {
+	Start   time.Time
+	Workout string
+	Name    string
+	Weight  float64
+	Reps    int
+}
+
+// ParseFormat accepts the user-facing format names, treating an empty
+// string as auto-detection.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "", FormatAuto:
+		return FormatAuto, nil
+	case FormatStrong, FormatHevy, FormatFitNotes:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported import format %q", s)
+	}
+}
+
+// Parse reads an export file and returns its sets in file order. Rows that
+// carry no strength data (rest timers, cardio, timed holds) are counted in
+// skipped rather than returned. loc is used for exports whose timestamps
+// carry no zone information.
+func Parse(r io.Reader, format Format, loc *time.Location) (sets []Set, detected Format, skipped int, err error) {
+	br := bufio.NewReader(r)
+	first, err := br.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, "", 0, err
+	}
+	first = bytes.TrimPrefix(first, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(stripBOM(br))
+	reader.Comma = sniffDelimiter(first)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("%w: reading header: %v", ErrInvalidFile, err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	if format == FormatAuto {
+		format = detect(cols)
+		if format == "" {
+			return nil, "", 0, ErrUnknownFormat
+		}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var parse func(row []string) (Set, bool, error)
+	switch format {
+	case FormatStrong:
+		parse, err = strongParser(cols, loc)
+	case FormatHevy:
+		parse, err = hevyParser(cols, loc)
+	case FormatFitNotes:
+		parse, err = fitNotesParser(cols, loc)
+	default:
+		err = ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	line := 1
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, "", 0, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, line, err)
+		}
+		set, ok, err := parse(row)
+		if err != nil {
+			return nil, "", 0, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, line, err)
+		}
+		if !ok {
+			skipped++
+			continue
+		}
+		sets = append(sets, set)
+	}
+
+	return sets, format, skipped, nil
+}
+
+func detect(cols map[string]int) Format {
+	has := func(names ...string) bool {
+		for _, n := range names {
+			if _, ok := cols[n]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case has("exercise_title", "start_time", "reps"):
+		return FormatHevy
+	case has("date", "exercise name", "set order"):
+		return FormatStrong
+	case has("date", "exercise", "category", "reps"):
+		return FormatFitNotes
+	}
+	return ""
+}
+
+func strongParser(cols map[string]int, loc *time.Location) (func([]string) (Set, bool, error), error) {
+	if err := require(cols, "date", "workout name", "exercise name", "set order", "weight", "reps"); err != nil {
+		return nil, err
+	}
+	return func(row []string) (Set, bool, error) {
+		order := field(row, cols, "set order")
+		if strings.EqualFold(order, "rest timer") {
+			return Set{}, false, nil
+		}
+		reps, ok, err := parseReps(field(row, cols, "reps"))
+		if err != nil || !ok {
+			return Set{}, false, err
+		}
+		start, err := parseTime(field(row, cols, "date"), loc, "2006-01-02 15:04:05", "2006-01-02 15:04")
+		if err != nil {
+			return Set{}, false, err
+		}
+		weight, err := parseWeight(field(row, cols, "weight"))
+		if err != nil {
+			return Set{}, false, err
+		}
+		return Set{
+			Start:   start,
+			Workout: field(row, cols, "workout name"),
+			Name:    field(row, cols, "exercise name"),
+			Weight:  weight,
+			Reps:    reps,
+		}, true, nil
+	}, nil
+}
+
+func hevyParser(cols map[string]int, loc *time.Location) (func([]string) (Set, bool, error), error) {
+	if err := require(cols, "title", "start_time", "exercise_title", "reps"); err != nil {
+		return nil, err
+	}
+	weightCol, factor := "weight_kg", 1.0
+	if _, ok := cols[weightCol]; !ok {
+		weightCol, factor = "weight_lbs", lbsToKg
+		if _, ok := cols[weightCol]; !ok {
+			return nil, errors.New("missing column weight_kg or weight_lbs")
+		}
+	}
+	return func(row []string) (Set, bool, error) {
+		reps, ok, err := parseReps(field(row, cols, "reps"))
+		if err != nil || !ok {
+			return Set{}, false, err
+		}
+		start, err := parseTime(field(row, cols, "start_time"), loc,
+			"2 Jan 2006, 15:04", "2 Jan 2006 15:04", "2006-01-02 15:04:05", time.RFC3339)
+		if err != nil {
+			return Set{}, false, err
+		}
+		weight, err := parseWeight(field(row, cols, weightCol))
+		if err != nil {
+			return Set{}, false, err
+		}
+		return Set{
+			Start:   start,
+			Workout: field(row, cols, "title"),
+			Name:    field(row, cols, "exercise_title"),
+			Weight:  weight * factor,
+			Reps:    reps,
+		}, true, nil
+	}, nil
+}
+
+func fitNotesParser(cols map[string]int, loc *time.Location) (func([]string) (Set, bool, error), error) {
+	if err := require(cols, "date", "exercise", "reps"); err != nil {
+		return nil, err
+	}
+	weightCol, factor := "weight (kgs)", 1.0
+	if _, ok := cols[weightCol]; !ok {
+		weightCol, factor = "weight (lbs)", lbsToKg
+		if _, ok := cols[weightCol]; !ok {
+			return nil, errors.New("missing column Weight (kgs) or Weight (lbs)")
+		}
+	}
+	return func(row []string) (Set, bool, error) {
+		reps, ok, err := parseReps(field(row, cols, "reps"))
+		if err != nil || !ok {
+			return Set{}, false, err
+		}
+		// FitNotes only records the day, so every set logged on a date
+		// belongs to one session.
+		start, err := parseTime(field(row, cols, "date"), loc, "2006-01-02")
+		if err != nil {
+			return Set{}, false, err
+		}
+		weight, err := parseWeight(field(row, cols, weightCol))
+		if err != nil {
+			return Set{}, false, err
+		}
+		return Set{
+			Start:   start,
+			Workout: field(row, cols, "category"),
+			Name:    field(row, cols, "exercise"),
+			Weight:  weight * factor,
+			Reps:    reps,
+		}, true, nil
+	}, nil
+}
+
+func require(cols map[string]int, names ...string) error {
+	for _, n := range names {
+		if _, ok := cols[n]; !ok {
+			return fmt.Errorf("missing column %q", n)
+		}
+	}
+	return nil
+}
+
+func field(row []string, cols map[string]int, name string) string {
+	i, ok := cols[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func parseReps(s string) (int, bool, error) {
+	if s == "" {
+		return 0, false, nil
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid reps %q", s)
+	}
+	if f <= 0 {
+		return 0, false, nil
+	}
+	return int(f), true, nil
+}
+
+func parseWeight(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid weight %q", s)
+	}
+	return f, nil
+}
+
+func parseTime(s string, loc *time.Location, layouts ...string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// sniffDelimiter picks between comma and semicolon, the latter being used by
+// Strong in locales with a decimal comma.
+func sniffDelimiter(sample []byte) rune {
+	line := sample
+	if i := bytes.IndexByte(sample, '\n'); i >= 0 {
+		line = sample[:i]
+	}
+	if bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+type bomReader struct {
+	r       *bufio.Reader
+	checked bool
+}
+
+func stripBOM(r *bufio.Reader) io.Reader {
+	return &bomReader{r: r}
+}
+
+func (b *bomReader) Read(p []byte) (int, error) {
+	if !b.checked {
+		b.checked = true
+		if head, err := b.r.Peek(3); err == nil && bytes.Equal(head, []byte("\xef\xbb\xbf")) {
+			b.r.Discard(3)
+		}
+	}
+	return b.r.Read(p)
+}
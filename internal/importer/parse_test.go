@@ -0,0 +1,170 @@
+package importer_test
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/importer"
+)
+
+func TestParse(t *testing.T) {
+	melbourne, err := time.LoadLocation("Australia/Melbourne")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(s string) time.Time {
+		t, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	tests := []struct {
+		file        string
+		format      importer.Format
+		loc         *time.Location
+		wantFormat  importer.Format
+		wantSets    []importer.Set
+		wantSkipped int
+	}{
+		{
+			// Rest timers and timed holds are skipped; bodyweight sets
+			// are kept with no weight.
+			file:       "strong.csv",
+			format:     importer.FormatAuto,
+			loc:        melbourne,
+			wantFormat: importer.FormatStrong,
+			wantSets: []importer.Set{
+				{Start: utc("2025-03-09 20:30"), Workout: "Push Day", Name: "Bench Press (Barbell)", Weight: 60, Reps: 8},
+				{Start: utc("2025-03-09 20:30"), Workout: "Push Day", Name: "Bench Press (Barbell)", Weight: 62.5, Reps: 6},
+				{Start: utc("2025-03-09 20:30"), Workout: "Push Day", Name: "Push Up", Weight: 0, Reps: 15},
+				{Start: utc("2025-03-12 07:05"), Workout: "Pull Day", Name: "Deadlift (Barbell)", Weight: 140, Reps: 5},
+			},
+			wantSkipped: 2,
+		},
+		{
+			// Strong writes semicolons and decimal commas in some locales,
+			// behind a byte order mark.
+			file:       "strong_semicolon.csv",
+			format:     importer.FormatAuto,
+			wantFormat: importer.FormatStrong,
+			wantSets: []importer.Set{
+				{Start: utc("2025-03-10 07:30"), Workout: "Jambes", Name: "Squat (Barbell)", Weight: 102.5, Reps: 5},
+				{Start: utc("2025-03-10 07:30"), Workout: "Jambes", Name: "Squat (Barbell)", Weight: 102.5, Reps: 4},
+			},
+		},
+		{
+			file:       "hevy.csv",
+			format:     importer.FormatAuto,
+			wantFormat: importer.FormatHevy,
+			wantSets: []importer.Set{
+				{Start: utc("2025-03-15 09:00"), Workout: "Upper", Name: "Overhead Press (Barbell)", Weight: 30, Reps: 10},
+				{Start: utc("2025-03-15 09:00"), Workout: "Upper", Name: "Overhead Press (Barbell)", Weight: 42.5, Reps: 6},
+				{Start: utc("2025-03-15 09:00"), Workout: "Upper", Name: "Pull Up", Weight: 0, Reps: 8},
+			},
+			wantSkipped: 1,
+		},
+		{
+			file:       "hevy_lbs.csv",
+			format:     importer.FormatHevy,
+			wantFormat: importer.FormatHevy,
+			wantSets: []importer.Set{
+				{Start: utc("2025-03-16 17:45"), Workout: "Legs", Name: "Squat (Barbell)", Weight: 225 * 0.45359237, Reps: 5},
+			},
+		},
+		{
+			// FitNotes records days only, and its category stands in for
+			// the workout name.
+			file:       "fitnotes.csv",
+			format:     importer.FormatAuto,
+			loc:        melbourne,
+			wantFormat: importer.FormatFitNotes,
+			wantSets: []importer.Set{
+				{Start: utc("2025-03-10 13:00"), Workout: "Chest", Name: "Flat Barbell Bench Press", Weight: 80, Reps: 5},
+				{Start: utc("2025-03-10 13:00"), Workout: "Chest", Name: "Flat Barbell Bench Press", Weight: 80, Reps: 5},
+				{Start: utc("2025-03-12 13:00"), Workout: "Back", Name: "Barbell Row", Weight: 70, Reps: 8},
+			},
+			wantSkipped: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			sets, format, skipped, err := importer.Parse(f, tt.format, tt.loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", format, tt.wantFormat)
+			}
+			if skipped != tt.wantSkipped {
+				t.Errorf("skipped = %d, want %d", skipped, tt.wantSkipped)
+			}
+			if len(sets) != len(tt.wantSets) {
+				t.Fatalf("got %d sets, want %d: %+v", len(sets), len(tt.wantSets), sets)
+			}
+			for i, got := range sets {
+				want := tt.wantSets[i]
+				if !got.Start.Equal(want.Start) || got.Workout != want.Workout || got.Name != want.Name ||
+					got.Reps != want.Reps || math.Abs(got.Weight-want.Weight) > 1e-9 {
+					t.Errorf("set %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		csv    string
+		format importer.Format
+		want   error
+	}{
+		{"unknown export", "a,b,c\n1,2,3\n", importer.FormatAuto, importer.ErrUnknownFormat},
+		{"empty file", "", importer.FormatAuto, importer.ErrInvalidFile},
+		{"wrong format", "Date,Exercise,Category,Weight (kgs),Reps\n2025-03-11,Squat,Legs,100,5\n", importer.FormatStrong, importer.ErrInvalidFile},
+		{"no weight column", "Date,Exercise,Category,Reps\n2025-03-11,Squat,Legs,5\n", importer.FormatFitNotes, importer.ErrInvalidFile},
+		{"bad reps", "Date,Exercise,Category,Weight (kgs),Reps\n2025-03-11,Squat,Legs,100,five\n", importer.FormatAuto, importer.ErrInvalidFile},
+		{"bad date", "Date,Exercise,Category,Weight (kgs),Reps\n11/03/2025,Squat,Legs,100,5\n", importer.FormatAuto, importer.ErrInvalidFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := importer.Parse(strings.NewReader(tt.csv), tt.format, nil)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in   string
+		want importer.Format
+		ok   bool
+	}{
+		{"", importer.FormatAuto, true},
+		{" Strong ", importer.FormatStrong, true},
+		{"HEVY", importer.FormatHevy, true},
+		{"fitnotes", importer.FormatFitNotes, true},
+		{"jefit", "", false},
+	}
+	for _, tt := range tests {
+		got, err := importer.ParseFormat(tt.in)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
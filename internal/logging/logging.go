@@ -0,0 +1,99 @@
+// Package logging sets up structured JSON logging and writes one access log
+// entry per HTTP request.
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/requestid"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const userIDKey = "user_id"
+
+// New returns a logger writing JSON lines to stderr at level, one of debug,
+// info, warn or error.
+func New(level string) *slog.Logger {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		l = slog.LevelInfo
+	}
+	return slog.New(traceHandler{slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: l})})
+}
+
+// traceHandler adds the trace and span IDs of the context a record is
+// logged with, so that log lines can be matched to traces.
+type traceHandler struct {
+	slog.Handler
+}
+
+func (h traceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return traceHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h traceHandler) WithGroup(name string) slog.Handler {
+	return traceHandler{h.Handler.WithGroup(name)}
+}
+
+// SetUserID records the user a request acts for, to be included in its
+// access log entry.
+func SetUserID(c *gin.Context, id int) {
+	c.Set(userIDKey, id)
+}
+
+// Middleware logs every request once it has been handled, with its request
+// ID, route template, status and latency. Errors attached to the request
+// with c.Error are included, and server errors are logged at error level.
+func Middleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("request_id", requestid.Get(c)),
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if id, ok := c.Get(userIDKey); ok {
+			attrs = append(attrs, slog.Any("user_id", id))
+		}
+		if len(c.Errors) > 0 {
+			errs := make([]error, len(c.Errors))
+			for i, e := range c.Errors {
+				errs[i] = e.Err
+			}
+			attrs = append(attrs, slog.String("error", strings.ReplaceAll(errors.Join(errs...).Error(), "\n", "; ")))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
@@ -0,0 +1,96 @@
+// Package metrics defines the Prometheus metrics the server exports on
+// /metrics: HTTP request timings, database pool and query timings, and
+// counts of what users log.
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gym_tracker"
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to handle HTTP requests, by route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	storeQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "store_query_duration_seconds",
+		Help:      "Time taken by store methods, including every query they make.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"store", "method"})
+
+	// SessionsCreated counts workout sessions logged.
+	SessionsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workout_sessions_created_total",
+		Help:      "Workout sessions logged.",
+	})
+
+	// SetsLogged counts sets logged across all workout exercises.
+	SetsLogged = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workout_sets_logged_total",
+		Help:      "Sets logged in workout sessions.",
+	})
+
+	// PersonalRecords counts exercises logged with a heavier weight than
+	// the user had used for that exercise before.
+	PersonalRecords = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "personal_records_total",
+		Help:      "Exercises logged at a weight above the user's previous best.",
+	})
+)
+
+// RegisterDB exports the connection pool statistics of db. It panics if
+// called twice, so it belongs to the command that owns db rather than to
+// router setup.
+func RegisterDB(db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+// Middleware times every request. Requests that match no route share one
+// label so that scanners cannot create a series per path.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveQuery starts timing a store method and returns the function that
+// records it, to be deferred:
+//
+//	defer metrics.ObserveQuery("gyms", "List")()
+func ObserveQuery(store, method string) func() {
+	start := time.Now()
+	return func() {
+		storeQueryDuration.WithLabelValues(store, method).Observe(time.Since(start).Seconds())
+	}
+}
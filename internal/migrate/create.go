@@ -0,0 +1,51 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create writes empty up and down files for a migration numbered after the
+// newest one in dir and returns their paths.
+func Create(dir, name string) (up, down string, err error) {
+	slug := strings.Trim(nonWord.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return "", "", errors.New("migration name must contain letters or digits")
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	var version int64 = 1
+	if n := len(migrations); n > 0 {
+		version = migrations[n-1].Version + 1
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, slug))
+	up, down = base+".up.sql", base+".down.sql"
+	for _, f := range []struct{ path, body string }{
+		{up, "-- Remember to make the same change to db/schema.sql.\n"},
+		{down, ""},
+	} {
+		// O_EXCL keeps an existing migration from being overwritten.
+		file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", err
+		}
+		_, err = file.WriteString(f.body)
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return up, down, nil
+}
@@ -0,0 +1,285 @@
+// Package migrate applies the versioned SQL migrations in db/migrations and
+// records them in the schema_migrations table.
+//
+// Each migration runs in its own transaction together with the row that
+// records it, so a failed migration leaves nothing behind. A session-level
+// advisory lock is held while migrating, so that servers started side by
+// side apply each migration once: the second waits for the first and then
+// finds nothing pending.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// lockKey identifies the advisory lock taken while migrating.
+const lockKey int64 = 7_341_296_503_118
+
+const createTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration and when it was applied, if it has been. Applied
+// migrations whose files are missing have an empty Up and Down.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load reads the migrations in the root of fsys, ordered by version. Every
+// version must have both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	files := map[int64]int{}
+	for _, e := range entries {
+		m := fileName.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, mig.Name, m[2])
+		}
+		files[version]++
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if files[mig.Version] != 2 {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Up applies every pending migration in version order and returns those
+// it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if err := run(ctx, conn, mig.Up, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mig.Version, mig.Name); err != nil {
+				return fmt.Errorf("applying %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the steps most recently applied migrations, newest first,
+// and returns those it reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	byVersion := map[int64]Migration{}
+	for _, mig := range m.migrations {
+		byVersion[mig.Version] = mig
+	}
+
+	var reverted []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(done))
+		for v := range done {
+			versions = append(versions, v)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, v := range versions[:min(steps, len(versions))] {
+			mig, ok := byVersion[v]
+			if !ok {
+				return fmt.Errorf("migration %d is applied but its files are missing", v)
+			}
+			if err := run(ctx, conn, mig.Down, "DELETE FROM schema_migrations WHERE version = $1", mig.Version); err != nil {
+				return fmt.Errorf("reverting %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// ErrNotInitialised is returned by Inspect when schema_migrations does not
+// exist, as happens before the first migration is applied.
+var ErrNotInitialised = errors.New("schema_migrations does not exist")
+
+// Status lists every known or applied migration in version order.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		statuses = m.statuses(done)
+		return nil
+	})
+	return statuses, err
+}
+
+// Inspect is Status for diagnostics: it neither waits for the migration
+// lock nor creates schema_migrations, so it only reads. Each migration is
+// committed together with its row, so a run in progress shows as the
+// migrations it has finished.
+func (m *Migrator) Inspect(ctx context.Context) ([]Status, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotInitialised
+	}
+	done, err := appliedVersions(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	return m.statuses(done), nil
+}
+
+// statuses merges the known migrations with the applied ones in done.
+func (m *Migrator) statuses(done map[int64]appliedRow) []Status {
+	var statuses []Status
+	for _, mig := range m.migrations {
+		s := Status{Migration: mig}
+		if r, ok := done[mig.Version]; ok {
+			s.AppliedAt = &r.appliedAt
+			delete(done, mig.Version)
+		}
+		statuses = append(statuses, s)
+	}
+	for v, r := range done {
+		statuses = append(statuses, Status{Migration: Migration{Version: v, Name: r.name}, AppliedAt: &r.appliedAt})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses
+}
+
+// locked runs fn on a connection holding the migration lock, after making
+// sure schema_migrations exists.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("taking migration lock: %w", err)
+	}
+	// Unlock even when ctx is cancelled, as the connection goes back to
+	// the pool still holding the lock otherwise.
+	defer conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", lockKey)
+
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+type appliedRow struct {
+	name      string
+	appliedAt time.Time
+}
+
+// queryer is a *sql.DB or *sql.Conn.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func appliedVersions(ctx context.Context, q queryer) (map[int64]appliedRow, error) {
+	rows, err := q.QueryContext(ctx, "SELECT version, name, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := map[int64]appliedRow{}
+	for rows.Next() {
+		var v int64
+		var r appliedRow
+		if err := rows.Scan(&v, &r.name, &r.appliedAt); err != nil {
+			return nil, err
+		}
+		done[v] = r
+	}
+	return done, rows.Err()
+}
+
+// run executes a migration script and the statement that records it in
+// one transaction.
+func run(ctx context.Context, conn *sql.Conn, script, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
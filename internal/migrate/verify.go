@@ -0,0 +1,102 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// catalogQueries describe a schema as comparable lines. Columns are listed
+// by name rather than position, since columns added by ALTER TABLE come
+// last while schema.sql lists them where they belong.
+var catalogQueries = []string{
+	`SELECT format('column %s.%s %s%s %s default %s generated %s',
+			table_name, column_name, data_type,
+			COALESCE('(' || character_maximum_length || ')', ''),
+			CASE is_nullable WHEN 'YES' THEN 'null' ELSE 'not null' END,
+			COALESCE(column_default, '-'), COALESCE(generation_expression, '-'))
+		FROM information_schema.columns WHERE table_schema = $1`,
+	`SELECT format('constraint %s.%s %s', conrelid::regclass, conname, pg_get_constraintdef(oid))
+		FROM pg_constraint WHERE connamespace = $1::regnamespace`,
+	`SELECT format('index %s', indexdef) FROM pg_indexes WHERE schemaname = $1`,
+	`SELECT format('view %s %s', table_name, view_definition)
+		FROM information_schema.views WHERE table_schema = $1`,
+}
+
+// Verify checks that applying every migration to an empty schema yields the
+// same tables, constraints, indexes and views as schema, and returns the
+// differences. Both are built in scratch schemas inside a transaction that
+// is rolled back, so the database is left untouched.
+func Verify(ctx context.Context, db *sql.DB, migrations []Migration, schema string) ([]string, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ups := make([]string, len(migrations))
+	for i, mig := range migrations {
+		ups[i] = mig.Up
+	}
+	migrated, err := describe(ctx, tx, "verify_migrations", ups...)
+	if err != nil {
+		return nil, fmt.Errorf("applying migrations: %w", err)
+	}
+	expected, err := describe(ctx, tx, "verify_schema", schema)
+	if err != nil {
+		return nil, fmt.Errorf("applying schema.sql: %w", err)
+	}
+
+	var diffs []string
+	for _, line := range expected {
+		if _, found := slices.BinarySearch(migrated, line); !found {
+			diffs = append(diffs, "only in schema.sql: "+line)
+		}
+	}
+	for _, line := range migrated {
+		if _, found := slices.BinarySearch(expected, line); !found {
+			diffs = append(diffs, "only in migrations: "+line)
+		}
+	}
+	return diffs, nil
+}
+
+// describe runs the scripts in a new schema and returns its sorted
+// description, with references to the schema's name removed.
+func describe(ctx context.Context, tx *sql.Tx, schema string, scripts ...string) ([]string, error) {
+	if _, err := tx.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "SET LOCAL search_path TO "+schema); err != nil {
+		return nil, err
+	}
+	for _, script := range scripts {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			return nil, err
+		}
+	}
+
+	var lines []string
+	for _, query := range catalogQueries {
+		rows, err := tx.QueryContext(ctx, query, schema)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var line string
+			if err := rows.Scan(&line); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			lines = append(lines, strings.ReplaceAll(line, schema+".", ""))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	slices.Sort(lines)
+	return lines, nil
+}
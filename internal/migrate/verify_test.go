@@ -0,0 +1,168 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	dbfiles "github.com/Ross1116/gym-tracker-backend/db"
+	"github.com/Ross1116/gym-tracker-backend/internal/migrate"
+	_ "github.com/lib/pq"
+)
+
+// testDSNEnv names the environment variable holding a PostgreSQL database
+// the tests may create and drop schemas in. The tests are skipped without
+// it.
+const testDSNEnv = "GYM_TEST_DATABASE_DSN"
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func loadMigrations(t *testing.T) []migrate.Migration {
+	t.Helper()
+	migrations, err := migrate.Load(dbfiles.Migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrations
+}
+
+// scratchSchema creates an empty schema that is dropped after the test and
+// makes it the search path of db, which is limited to a single connection
+// so that the setting applies to every statement.
+func scratchSchema(t *testing.T, db *sql.DB, prefix string) string {
+	t.Helper()
+	ctx := context.Background()
+	db.SetMaxOpenConns(1)
+	schema := fmt.Sprintf("%s_%d", prefix, time.Now().UnixNano())
+	if _, err := db.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.ExecContext(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+	if _, err := db.ExecContext(ctx, "SET search_path TO "+schema); err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestVerify(t *testing.T) {
+	db := openTestDB(t)
+
+	diffs, err := migrate.Verify(context.Background(), db, loadMigrations(t), dbfiles.Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range diffs {
+		t.Error(d)
+	}
+}
+
+// TestRoundTrip applies every migration, reverts them all and applies them
+// again in a scratch schema, checking that each down migration removes what
+// its up migration created.
+func TestRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	migrations := loadMigrations(t)
+	schema := scratchSchema(t, db, "migrate_round_trip")
+
+	m := migrate.New(db, migrations)
+	for i, step := range []string{"up", "down", "up"} {
+		var err error
+		if step == "up" {
+			_, err = m.Up(ctx)
+		} else {
+			_, err = m.Down(ctx, len(migrations))
+		}
+		if err != nil {
+			t.Fatalf("step %d (%s): %v", i+1, step, err)
+		}
+
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range statuses {
+			if applied := s.AppliedAt != nil; applied != (step == "up") {
+				t.Errorf("step %d (%s): migration %04d_%s applied = %v", i+1, step, s.Version, s.Name, applied)
+			}
+		}
+
+		if step == "down" {
+			var left []string
+			rows, err := db.QueryContext(ctx, `
+					SELECT format('%s %s', relkind, relname)
+					FROM pg_class
+					WHERE relnamespace = $1::regnamespace AND relname NOT LIKE 'schema_migrations%'
+					ORDER BY relname`, schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for rows.Next() {
+				var rel string
+				if err := rows.Scan(&rel); err != nil {
+					t.Fatal(err)
+				}
+				left = append(left, rel)
+			}
+			rows.Close()
+			if len(left) > 0 {
+				t.Errorf("after reverting every migration, left behind: %v", left)
+			}
+		}
+	}
+}
+
+// TestInspect checks that Inspect reports a database that was never
+// migrated without creating schema_migrations in it.
+func TestInspect(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	migrations := loadMigrations(t)
+	scratchSchema(t, db, "migrate_inspect")
+
+	m := migrate.New(db, migrations)
+	if _, err := m.Inspect(ctx); !errors.Is(err, migrate.ErrNotInitialised) {
+		t.Fatalf("Inspect() before migrating: err = %v, want ErrNotInitialised", err)
+	}
+	var exists bool
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Error("Inspect created schema_migrations")
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := m.Inspect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != len(migrations) {
+		t.Fatalf("got %d statuses, want %d", len(statuses), len(migrations))
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Errorf("migration %04d_%s is not applied", s.Version, s.Name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+type BodyMeasurement struct {
+	ID           int       `json:"id"`
+	UserID       int       `json:"user_id"`
+	MeasuredAt   time.Time `json:"measured_at"`
+	BodyweightKg *float64  `json:"bodyweight_kg,omitempty" example:"82.4"`
+	BodyFatPct   *float64  `json:"body_fat_pct,omitempty" example:"18.5"`
+	WaistCm      *float64  `json:"waist_cm,omitempty" example:"84"`
+	ArmsCm       *float64  `json:"arms_cm,omitempty" example:"37.5"`
+	ChestCm      *float64  `json:"chest_cm,omitempty" example:"104"`
+	Notes        *string   `json:"notes,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type BodyMeasurementInput struct {
+	MeasuredAt   *time.Time `json:"measured_at"`
+	BodyweightKg *float64   `json:"bodyweight_kg" binding:"omitempty,gt=0,lt=700" example:"82.4"`
+	BodyFatPct   *float64   `json:"body_fat_pct" binding:"omitempty,gt=0,lt=100" example:"18.5"`
+	WaistCm      *float64   `json:"waist_cm" binding:"omitempty,gt=0" example:"84"`
+	ArmsCm       *float64   `json:"arms_cm" binding:"omitempty,gt=0" example:"37.5"`
+	ChestCm      *float64   `json:"chest_cm" binding:"omitempty,gt=0" example:"104"`
+	Notes        *string    `json:"notes" binding:"omitempty,max=255"`
+}
+
+type BodyweightTrendPoint struct {
+	MeasuredAt   time.Time `json:"measured_at"`
+	BodyweightKg float64   `json:"bodyweight_kg"`
+	TrendKg      float64   `json:"trend_kg"`
+	WeeklyRateKg *float64  `json:"weekly_rate_kg,omitempty"`
+}
+
+// BodyweightWeek lines up the bodyweight trend with training done in the
+// same week so weight changes can be read against strength progress.
+// BestEstimated1RM is the week's best estimated one-rep max on the
+// exercise the trend was asked for, if it was trained that week.
+type BodyweightWeek struct {
+	WeekStart        string   `json:"week_start" example:"2025-03-10"`
+	TrendKg          *float64 `json:"trend_kg,omitempty"`
+	TrendChangeKg    *float64 `json:"trend_change_kg,omitempty"`
+	Sessions         int      `json:"sessions"`
+	Sets             int      `json:"sets"`
+	Volume           float64  `json:"volume"`
+	BestEstimated1RM *float64 `json:"best_estimated_1rm,omitempty"`
+}
+
+type BodyMeasurementTrend struct {
+	Alpha        float64                `json:"alpha" example:"0.1"`
+	Timezone     string                 `json:"timezone" example:"Australia/Melbourne"`
+	ExerciseID   *int                   `json:"exercise_id,omitempty" example:"3"`
+	Entries      []BodyMeasurement      `json:"entries"`
+	Trend        []BodyweightTrendPoint `json:"trend"`
+	WeeklyRateKg *float64               `json:"weekly_rate_kg,omitempty"`
+	Weeks        []BodyweightWeek       `json:"weeks"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Food is an entry of the shared food catalogue. Nutrients are per 100 g,
+// or per 100 ml when basis_unit is ml, and are omitted when the source
+// dataset does not record them. ServingSize is in the basis unit.
+type Food struct {
+	ID             int       `json:"id"`
+	Source         string    `json:"source" example:"off"`
+	SourceID       string    `json:"source_id" example:"3017620422003"`
+	Barcode        *string   `json:"barcode,omitempty" example:"3017620422003"`
+	Name           string    `json:"name" example:"Nutella"`
+	Brand          *string   `json:"brand,omitempty" example:"Ferrero"`
+	BasisUnit      string    `json:"basis_unit" example:"g"`
+	ServingSize    *float64  `json:"serving_size,omitempty" example:"15"`
+	CaloriesPer100 *float64  `json:"calories_per_100,omitempty" example:"539"`
+	ProteinPer100  *float64  `json:"protein_per_100,omitempty" example:"6.3"`
+	CarbsPer100    *float64  `json:"carbs_per_100,omitempty" example:"57.5"`
+	FatPer100      *float64  `json:"fat_per_100,omitempty" example:"30.9"`
+	FiberPer100    *float64  `json:"fiber_per_100,omitempty" example:"0"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type FoodImportReport struct {
+	Source          string `json:"source" example:"off"`
+	Format          string `json:"format" example:"csv"`
+	Rows            int    `json:"rows"`
+	Imported        int    `json:"imported"`
+	Skipped         int    `json:"skipped"`
+	InvalidBarcodes int    `json:"invalid_barcodes"`
+}
+
+// PantryItemFromFoodInput creates a pantry item whose nutrition per unit is
+// copied from a catalogue food. Name defaults to the food's name.
+type PantryItemFromFoodInput struct {
+	FoodID    int     `json:"food_id" binding:"required" example:"42"`
+	Name      string  `json:"name" binding:"max=255" example:"Nutella"`
+	Quantity  float64 `json:"quantity" binding:"gte=0" example:"750"`
+	Unit      string  `json:"unit" binding:"required" example:"g"`
+	Threshold float64 `json:"threshold" binding:"gte=0" example:"200"`
+}
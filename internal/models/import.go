@@ -0,0 +1,23 @@
+package models
+
+type ImportReport struct {
+	Format                string   `json:"format" example:"strong"`
+	DryRun                bool     `json:"dry_run"`
+	GymID                 int      `json:"gym_id,omitempty"`
+	CreatedGym            string   `json:"created_gym,omitempty"`
+	Sessions              int      `json:"sessions"`
+	SkippedSessions       int      `json:"skipped_sessions"`
+	ExerciseEntries       int      `json:"exercise_entries"`
+	Sets                  int      `json:"sets"`
+	SkippedRows           int      `json:"skipped_rows"`
+	CreatedExercises      []string `json:"created_exercises"`
+	CreatedEquipmentTypes []string `json:"created_equipment_types"`
+	Unmapped              []string `json:"unmapped"`
+	Warnings              []string `json:"warnings"`
+}
+
+type ArchiveImportReport struct {
+	Format  string         `json:"format" example:"json"`
+	Version int            `json:"version" example:"1"`
+	Rows    map[string]int `json:"rows"`
+}
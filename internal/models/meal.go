@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+type Meal struct {
+	ID            int              `json:"id"`
+	UserID        int              `json:"user_id"`
+	Ingredients   []MealIngredient `json:"ingredients"`
+	TotalCalories float64          `json:"total_calories" example:"612.5"`
+	TotalProtein  float64          `json:"total_protein" example:"41.2"`
+	TotalCarbs    float64          `json:"total_carbs" example:"70.4"`
+	TotalFat      float64          `json:"total_fat" example:"18.9"`
+	TotalFiber    float64          `json:"total_fiber" example:"9.1"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+type MealIngredient struct {
+	PantryItemID int     `json:"pantry_item_id"`
+	Name         string  `json:"name" example:"Oats"`
+	Unit         string  `json:"unit" example:"g"`
+	QuantityUsed float64 `json:"quantity_used" example:"80"`
+	Calories     float64 `json:"calories" example:"311.2"`
+	Protein      float64 `json:"protein" example:"13.6"`
+	Carbs        float64 `json:"carbs" example:"52.8"`
+	Fat          float64 `json:"fat" example:"5.6"`
+	Fiber        float64 `json:"fiber" example:"8.8"`
+}
+
+type MealInput struct {
+	// EatenAt defaults to now.
+	EatenAt     *time.Time            `json:"eaten_at"`
+	Ingredients []MealIngredientInput `json:"ingredients" binding:"required,min=1,dive"`
+}
+
+type MealIngredientInput struct {
+	PantryItemID int     `json:"pantry_item_id" binding:"required"`
+	QuantityUsed float64 `json:"quantity_used" binding:"required,gt=0" example:"80"`
+	// Unit defaults to the pantry item's unit. Any unit of the same kind
+	// (mass, volume or count) is converted, as is count to or from mass for
+	// items with a weight per item.
+	Unit string `json:"unit,omitempty" example:"cup"`
+}
+
+// StockShortfall describes a pantry item that does not hold enough stock
+// for a meal.
+type StockShortfall struct {
+	PantryItemID int     `json:"pantry_item_id"`
+	Name         string  `json:"name" example:"Oats"`
+	Unit         string  `json:"unit" example:"g"`
+	Required     float64 `json:"required" example:"80"`
+	Available    float64 `json:"available" example:"35"`
+}
+
+type InsufficientStockResponse struct {
+	ErrorResponse
+	Shortfalls []StockShortfall `json:"shortfalls"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// MealPlanEntry is a meal planned for a day: either servings of a recipe or
+// a list of ingredients. Ingredients holds the pantry items the entry will
+// use, with recipe ingredients scaled to the planned servings.
+type MealPlanEntry struct {
+	ID          int                  `json:"id"`
+	UserID      int                  `json:"user_id"`
+	Date        string               `json:"date" example:"2025-03-17"`
+	RecipeID    *int                 `json:"recipe_id,omitempty"`
+	RecipeName  *string              `json:"recipe_name,omitempty" example:"Chicken rice bowl"`
+	Servings    *float64             `json:"servings,omitempty" example:"2"`
+	Notes       *string              `json:"notes,omitempty"`
+	Ingredients []MealPlanIngredient `json:"ingredients"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+type MealPlanIngredient struct {
+	PantryItemID int     `json:"pantry_item_id"`
+	Name         string  `json:"name" example:"Chicken breast"`
+	Unit         string  `json:"unit" example:"g"`
+	Quantity     float64 `json:"quantity" example:"300"`
+}
+
+// MealPlanEntryInput plans either a recipe or a list of ingredients, not
+// both. Servings of a recipe default to the whole recipe.
+type MealPlanEntryInput struct {
+	Date        string                  `json:"date" binding:"required" example:"2025-03-17"`
+	RecipeID    *int                    `json:"recipe_id"`
+	Servings    *float64                `json:"servings" binding:"omitempty,gt=0" example:"2"`
+	Notes       *string                 `json:"notes"`
+	Ingredients []RecipeIngredientInput `json:"ingredients" binding:"omitempty,dive"`
+}
+
+// MealPlanRequirement is the total amount of a pantry item the plan uses
+// over a range of days compared with what is in stock.
+type MealPlanRequirement struct {
+	PantryItemID int     `json:"pantry_item_id"`
+	Name         string  `json:"name" example:"Chicken breast"`
+	Unit         string  `json:"unit" example:"g"`
+	Required     float64 `json:"required" example:"900"`
+	Available    float64 `json:"available" example:"500"`
+	Shortfall    float64 `json:"shortfall" example:"400"`
+}
+
+type MealPlanRequirements struct {
+	From  string                `json:"from" example:"2025-03-17"`
+	To    string                `json:"to" example:"2025-03-23"`
+	Items []MealPlanRequirement `json:"items"`
+}
@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// Macros holds energy in kcal and macronutrients in grams.
+type Macros struct {
+	Calories float64 `json:"calories" example:"2350"`
+	Protein  float64 `json:"protein" example:"165"`
+	Carbs    float64 `json:"carbs" example:"260"`
+	Fat      float64 `json:"fat" example:"70"`
+	Fiber    float64 `json:"fiber" example:"32"`
+}
+
+// MacroTargets is a set of daily goals. Unset goals are omitted.
+type MacroTargets struct {
+	Calories *float64 `json:"calories,omitempty" example:"2400"`
+	Protein  *float64 `json:"protein,omitempty" example:"170"`
+	Carbs    *float64 `json:"carbs,omitempty" example:"270"`
+	Fat      *float64 `json:"fat,omitempty" example:"75"`
+	Fiber    *float64 `json:"fiber,omitempty" example:"35"`
+}
+
+type NutritionTargets struct {
+	UserID int `json:"user_id"`
+	MacroTargets
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type NutritionTargetsInput struct {
+	Calories *float64 `json:"calories" binding:"omitempty,gte=0" example:"2400"`
+	Protein  *float64 `json:"protein" binding:"omitempty,gte=0" example:"170"`
+	Carbs    *float64 `json:"carbs" binding:"omitempty,gte=0" example:"270"`
+	Fat      *float64 `json:"fat" binding:"omitempty,gte=0" example:"75"`
+	Fiber    *float64 `json:"fiber" binding:"omitempty,gte=0" example:"35"`
+}
+
+// NutritionDay totals the meals eaten on one day.
+type NutritionDay struct {
+	Macros
+	Meals int `json:"meals"`
+}
+
+// NutritionPeriod totals the meals logged in a day or a week. The daily
+// average is taken over the days with at least one meal logged.
+type NutritionPeriod struct {
+	Start        string `json:"start" example:"2025-03-10"`
+	End          string `json:"end" example:"2025-03-16"`
+	Meals        int    `json:"meals"`
+	DaysLogged   int    `json:"days_logged"`
+	Totals       Macros `json:"totals"`
+	DailyAverage Macros `json:"daily_average"`
+}
+
+type NutritionSummary struct {
+	Timezone string            `json:"timezone" example:"Australia/Melbourne"`
+	Period   string            `json:"period" example:"day"`
+	From     string            `json:"from" example:"2025-03-10"`
+	To       string            `json:"to" example:"2025-03-16"`
+	Periods  []NutritionPeriod `json:"periods"`
+}
+
+// NutritionToday compares what has been eaten so far today, in the user's
+// time zone, with their targets. Remaining goes negative once a target is
+// exceeded.
+type NutritionToday struct {
+	Date      string       `json:"date" example:"2025-03-14"`
+	Timezone  string       `json:"timezone" example:"Australia/Melbourne"`
+	Meals     int          `json:"meals"`
+	Consumed  Macros       `json:"consumed"`
+	Targets   MacroTargets `json:"targets"`
+	Remaining MacroTargets `json:"remaining"`
+}
+
+// CalculatorFactor is one input to the target calculator and where its
+// value came from: override, profile, body_log, workouts or default.
+type CalculatorFactor struct {
+	Value  float64 `json:"value" example:"82.4"`
+	Source string  `json:"source" example:"body_log"`
+}
+
+type TargetCalculatorInputs struct {
+	BodyweightKg        CalculatorFactor `json:"bodyweight_kg"`
+	HeightCm            CalculatorFactor `json:"height_cm"`
+	Age                 CalculatorFactor `json:"age"`
+	Sex                 string           `json:"sex" example:"male"`
+	SexSource           string           `json:"sex_source" example:"profile"`
+	ActivityFactor      CalculatorFactor `json:"activity_factor"`
+	LookbackDays        CalculatorFactor `json:"lookback_days"`
+	SetsPerDay          CalculatorFactor `json:"sets_per_day"`
+	CardioMinutesPerDay CalculatorFactor `json:"cardio_minutes_per_day"`
+	KcalPerSetPerKg     CalculatorFactor `json:"kcal_per_set_per_kg"`
+	CardioMET           CalculatorFactor `json:"cardio_met"`
+	ProteinPerKg        CalculatorFactor `json:"protein_per_kg"`
+	AdjustmentKcal      CalculatorFactor `json:"adjustment_kcal"`
+}
+
+// TargetCalculation is a recommended calorie and protein target together
+// with every input and the arithmetic used to reach it.
+type TargetCalculation struct {
+	Inputs              TargetCalculatorInputs `json:"inputs"`
+	BMR                 float64                `json:"bmr" example:"1805"`
+	BaselineCalories    float64                `json:"baseline_calories" example:"2347"`
+	TrainingCalories    float64                `json:"training_calories" example:"99"`
+	CardioCalories      float64                `json:"cardio_calories" example:"62"`
+	MaintenanceCalories float64                `json:"maintenance_calories" example:"2508"`
+	TargetCalories      float64                `json:"target_calories" example:"2508"`
+	ProteinGrams        float64                `json:"protein_grams" example:"148"`
+	Reasoning           []string               `json:"reasoning"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+type PantryItem struct {
+	ID              int       `json:"id"`
+	UserID          int       `json:"user_id"`
+	Name            string    `json:"name" example:"Oats"`
+	Quantity        float64   `json:"quantity" example:"750"`
+	Unit            string    `json:"unit" example:"g"`
+	Threshold       float64   `json:"threshold" example:"250"`
+	CaloriesPerUnit float64   `json:"calories_per_unit" example:"3.89"`
+	ProteinPerUnit  float64   `json:"protein_per_unit" example:"0.17"`
+	CarbsPerUnit    float64   `json:"carbs_per_unit" example:"0.66"`
+	FatPerUnit      float64   `json:"fat_per_unit" example:"0.07"`
+	FiberPerUnit    float64   `json:"fiber_per_unit" example:"0.11"`
+	GramsPerItem    *float64  `json:"grams_per_item,omitempty" example:"50"`
+	LowStock        bool      `json:"low_stock"`
+	NextExpiry      *string   `json:"next_expiry,omitempty" example:"2025-03-20"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type PantryItemInput struct {
+	Name            string  `json:"name" binding:"required,max=255" example:"Oats"`
+	Quantity        float64 `json:"quantity" binding:"gte=0" example:"750"`
+	Unit            string  `json:"unit" binding:"required" example:"g"`
+	Threshold       float64 `json:"threshold" binding:"gte=0" example:"250"`
+	CaloriesPerUnit float64 `json:"calories_per_unit" binding:"gte=0" example:"3.89"`
+	ProteinPerUnit  float64 `json:"protein_per_unit" binding:"gte=0" example:"0.17"`
+	CarbsPerUnit    float64 `json:"carbs_per_unit" binding:"gte=0" example:"0.66"`
+	FatPerUnit      float64 `json:"fat_per_unit" binding:"gte=0" example:"0.07"`
+	FiberPerUnit    float64 `json:"fiber_per_unit" binding:"gte=0" example:"0.11"`
+	// GramsPerItem lets an item be measured both by weight and by count.
+	GramsPerItem *float64 `json:"grams_per_item" binding:"omitempty,gt=0" example:"50"`
+	// ExpiresOn (YYYY-MM-DD) dates the stock added when the item is created
+	// or its quantity is raised.
+	ExpiresOn *string `json:"expires_on" example:"2025-03-20"`
+}
+
+type PantryRestockInput struct {
+	Quantity float64 `json:"quantity" binding:"required,gt=0" example:"500"`
+	// ExpiresOn (YYYY-MM-DD) records the restocked quantity as a batch
+	// expiring on that day.
+	ExpiresOn *string `json:"expires_on" example:"2025-03-20"`
+}
+
+// PantryBatch is stock of a pantry item expiring on a known day.
+type PantryBatch struct {
+	ID           int       `json:"id"`
+	PantryItemID int       `json:"pantry_item_id"`
+	Quantity     float64   `json:"quantity" example:"500"`
+	ExpiresOn    string    `json:"expires_on" example:"2025-03-20"`
+	ReceivedAt   time.Time `json:"received_at"`
+}
+
+// PantryItemBatches splits an item's stock into dated batches and the
+// undated remainder.
+type PantryItemBatches struct {
+	PantryItemID int           `json:"pantry_item_id"`
+	Name         string        `json:"name" example:"Greek yoghurt"`
+	Unit         string        `json:"unit" example:"g"`
+	Quantity     float64       `json:"quantity" example:"1500"`
+	Undated      float64       `json:"undated" example:"500"`
+	Batches      []PantryBatch `json:"batches"`
+}
+
+// ExpiringBatch is a batch expiring within the requested window. DaysLeft
+// is negative once the batch has expired.
+type ExpiringBatch struct {
+	BatchID      int     `json:"batch_id"`
+	PantryItemID int     `json:"pantry_item_id"`
+	Name         string  `json:"name" example:"Greek yoghurt"`
+	Unit         string  `json:"unit" example:"g"`
+	Quantity     float64 `json:"quantity" example:"500"`
+	ExpiresOn    string  `json:"expires_on" example:"2025-03-20"`
+	DaysLeft     int     `json:"days_left" example:"2"`
+}
+
+// PantryForecast projects a pantry item's stock from its average daily use
+// in recent meals. Items with no recent use have no run-out estimate or
+// suggested threshold.
+type PantryForecast struct {
+	PantryItemID       int      `json:"pantry_item_id"`
+	Name               string   `json:"name" example:"Oats"`
+	Unit               string   `json:"unit" example:"g"`
+	Quantity           float64  `json:"quantity" example:"600"`
+	Threshold          float64  `json:"threshold" example:"200"`
+	DailyUsage         float64  `json:"daily_usage" example:"80"`
+	DaysLeft           *float64 `json:"days_left,omitempty" example:"7.5"`
+	RunsOutOn          *string  `json:"runs_out_on,omitempty" example:"2025-03-24"`
+	SuggestedThreshold *float64 `json:"suggested_threshold,omitempty" example:"240"`
+}
+
+type PantryForecastReport struct {
+	HistoryDays int              `json:"history_days" example:"28"`
+	LeadDays    float64          `json:"lead_days" example:"3"`
+	Items       []PantryForecast `json:"items"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type UserProfile struct {
+	UserID         int       `json:"user_id"`
+	BodyweightKg   *float64  `json:"bodyweight_kg,omitempty" example:"82.5"`
+	HeightCm       *float64  `json:"height_cm,omitempty" example:"180"`
+	BirthYear      *int      `json:"birth_year,omitempty" example:"1990"`
+	Sex            *string   `json:"sex,omitempty" example:"male"`
+	PreferredUnits string    `json:"preferred_units" example:"metric"`
+	Timezone       string    `json:"timezone" example:"Australia/Melbourne"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type UserProfileInput struct {
+	BodyweightKg   *float64 `json:"bodyweight_kg" binding:"omitempty,gt=0,lt=700" example:"82.5"`
+	HeightCm       *float64 `json:"height_cm" binding:"omitempty,gt=0,lt=300" example:"180"`
+	BirthYear      *int     `json:"birth_year" binding:"omitempty,gte=1900" example:"1990"`
+	Sex            *string  `json:"sex" binding:"omitempty,oneof=male female other" example:"male"`
+	PreferredUnits string   `json:"preferred_units" binding:"omitempty,oneof=metric imperial" example:"metric"`
+	Timezone       string   `json:"timezone" example:"Australia/Melbourne"`
+}
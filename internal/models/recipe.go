@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+type Recipe struct {
+	ID          int                `json:"id"`
+	UserID      int                `json:"user_id"`
+	Name        string             `json:"name" example:"Chicken rice bowl"`
+	Servings    float64            `json:"servings" example:"4"`
+	Notes       *string            `json:"notes,omitempty"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+	Total       Macros             `json:"total"`
+	PerServing  Macros             `json:"per_serving"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// RecipeIngredient is the amount of a pantry item used for the whole
+// recipe, with the nutrition that amount provides.
+type RecipeIngredient struct {
+	PantryItemID int     `json:"pantry_item_id"`
+	Name         string  `json:"name" example:"Chicken breast"`
+	Unit         string  `json:"unit" example:"g"`
+	Quantity     float64 `json:"quantity" example:"600"`
+	Macros
+}
+
+type RecipeInput struct {
+	Name        string                  `json:"name" binding:"required,max=255" example:"Chicken rice bowl"`
+	Servings    float64                 `json:"servings" binding:"required,gt=0" example:"4"`
+	Notes       *string                 `json:"notes"`
+	Ingredients []RecipeIngredientInput `json:"ingredients" binding:"required,min=1,dive"`
+}
+
+type RecipeIngredientInput struct {
+	PantryItemID int     `json:"pantry_item_id" binding:"required"`
+	Quantity     float64 `json:"quantity" binding:"required,gt=0" example:"600"`
+	// Unit defaults to the pantry item's unit; compatible units are
+	// converted as for meal ingredients.
+	Unit string `json:"unit,omitempty" example:"kg"`
+}
+
+type LogRecipeInput struct {
+	Servings float64 `json:"servings" binding:"required,gt=0" example:"1.5"`
+	// EatenAt defaults to now.
+	EatenAt *time.Time `json:"eaten_at"`
+}
@@ -1,7 +1,21 @@
 package models
 
+// ErrorResponse is the body of every error response. Error is meant for
+// people and may change; Code is stable and meant for programs.
 type ErrorResponse struct {
-	Error string `json:"error" example:"Error message"`
+	Error     string       `json:"error" example:"Error message"`
+	Code      string       `json:"code" example:"not_found"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty" example:"4f1c2b7e9a0d4e6f8b3a5c7d9e1f2a3b"`
+	// TraceID identifies the request's trace when tracing is enabled.
+	TraceID string `json:"trace_id,omitempty" example:"0af7651916cd43dd8448eb211c80319c"`
+}
+
+// FieldError describes why one field of the request body was rejected.
+// Field is the JSON path of the field, such as ingredients[0].quantity_used.
+type FieldError struct {
+	Field   string `json:"field" example:"name"`
+	Message string `json:"message" example:"is required"`
 }
 
 type SuccessResponse struct {
@@ -0,0 +1,37 @@
+package models
+
+// ShoppingListItem is a row of a user's shopping list. Rows with source
+// "threshold" come from pantry items below their restock threshold, rows
+// with source "plan" from pantry items the meal plan needs more of and rows
+// with source "forecast" from pantry items recent meals are projected to
+// use up; none of them has an item_id. Plan and forecast rows give the
+// amounts planned and projected. Rows with source "manual" were added by
+// hand.
+type ShoppingListItem struct {
+	ItemID       *int     `json:"item_id,omitempty"`
+	PantryItemID *int     `json:"pantry_item_id,omitempty"`
+	Name         string   `json:"name" example:"Oats"`
+	Quantity     *float64 `json:"quantity,omitempty" example:"250"`
+	Unit         *string  `json:"unit,omitempty" example:"g"`
+	Source       string   `json:"source" example:"threshold"`
+	Planned      *float64 `json:"planned,omitempty" example:"600"`
+	Projected    *float64 `json:"projected,omitempty" example:"560"`
+	Checked      bool     `json:"checked"`
+}
+
+type ShoppingListItemInput struct {
+	// Name defaults to the pantry item's name when pantry_item_id is set.
+	Name         string   `json:"name" binding:"max=255" example:"Bananas"`
+	PantryItemID *int     `json:"pantry_item_id"`
+	Quantity     *float64 `json:"quantity" binding:"omitempty,gt=0" example:"6"`
+	Unit         *string  `json:"unit" binding:"omitempty,max=50" example:"item"`
+}
+
+type ShoppingListCheckInput struct {
+	Checked bool `json:"checked" example:"true"`
+}
+
+type ShoppingListPurchase struct {
+	Restocked    []PantryItem `json:"restocked"`
+	ItemsRemoved int64        `json:"items_removed"`
+}
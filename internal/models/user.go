@@ -8,4 +8,28 @@ type User struct {
 	PasswordHash string    `json:"password" example:"MySecurePassword123" swaggerignore:"true"`
 	CreatedAt    time.Time `json:"created_at" swaggerignore:"true"`
 	UpdatedAt    time.Time `json:"updated_at" swaggerignore:"true"`
+	// DisabledAt is set when an administrator has disabled the account.
+	DisabledAt *time.Time `json:"disabled_at,omitempty" swaggerignore:"true"`
+}
+
+type UpdateEmailInput struct {
+	Email           string `json:"email" binding:"required,email" example:"new@example.com"`
+	CurrentPassword string `json:"current_password" binding:"required" example:"MySecurePassword123"`
+}
+
+type UpdatePasswordInput struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"MySecurePassword123"`
+	NewPassword     string `json:"new_password" binding:"required,min=8" example:"MyNewSecurePassword456"`
+}
+
+type DeleteAccountInput struct {
+	Password string `json:"password" binding:"required" example:"MySecurePassword123"`
+}
+
+type AccountDeletionStatus struct {
+	UserID       int        `json:"user_id"`
+	Pending      bool       `json:"pending"`
+	RequestedAt  *time.Time `json:"requested_at,omitempty"`
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+	ExportURL    string     `json:"export_url,omitempty" example:"/api/export?user_id=1"`
 }
@@ -5,14 +5,16 @@ import (
 )
 
 type WorkoutSession struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	GymID     int       `json:"gym_id"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            int       `json:"id"`
+	UserID        int       `json:"user_id"`
+	GymID         int       `json:"gym_id"`
+	CardioMinutes *int      `json:"cardio_minutes,omitempty" example:"20"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 type WorkoutSessionInput struct {
-	GymID int `json:"gym_id" binding:"required"`
+	GymID         int  `json:"gym_id" binding:"required"`
+	CardioMinutes *int `json:"cardio_minutes" binding:"omitempty,gte=0" example:"20"`
 }
 
 type WorkoutSessionWithExercises struct {
@@ -21,14 +23,17 @@ type WorkoutSessionWithExercises struct {
 }
 
 type WorkoutExercise struct {
-	ID               int       `json:"id"`
-	WorkoutSessionID int       `json:"workout_session_id"`
-	ExerciseID       int       `json:"exercise_id"`
-	GymEquipmentID   int       `json:"gym_equipment_id"`
-	Weight           float64   `json:"weight"`
-	Reps             int       `json:"reps"`
-	Sets             int       `json:"sets"`
-	CreatedAt        time.Time `json:"created_at"`
+	ID               int     `json:"id"`
+	WorkoutSessionID int     `json:"workout_session_id"`
+	ExerciseID       int     `json:"exercise_id"`
+	GymEquipmentID   int     `json:"gym_equipment_id"`
+	Weight           float64 `json:"weight"`
+	Reps             int     `json:"reps"`
+	Sets             int     `json:"sets"`
+	// PersonalRecord is set on a newly logged exercise whose weight beats
+	// the user's previous best for the exercise.
+	PersonalRecord bool      `json:"personal_record,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 type WorkoutExerciseInput struct {
@@ -40,19 +45,38 @@ type WorkoutExerciseInput struct {
 }
 
 type WorkoutExerciseWithDetails struct {
-	ID               int       `json:"id"`
-	WorkoutSessionID int       `json:"workout_session_id"`
-	ExerciseID       int       `json:"exercise_id"`
-	ExerciseName     string    `json:"exercise_name"`
-	GymEquipmentID   int       `json:"gym_equipment_id"`
-	EquipmentName    string    `json:"equipment_name"`
-	Weight           float64   `json:"weight"`
-	Reps             int       `json:"reps"`
-	Sets             int       `json:"sets"`
-	CreatedAt        time.Time `json:"created_at"`
+	ID               int     `json:"id"`
+	WorkoutSessionID int     `json:"workout_session_id"`
+	ExerciseID       int     `json:"exercise_id"`
+	ExerciseName     string  `json:"exercise_name"`
+	GymEquipmentID   int     `json:"gym_equipment_id"`
+	EquipmentName    string  `json:"equipment_name"`
+	Weight           float64 `json:"weight"`
+	Reps             int     `json:"reps"`
+	Sets             int     `json:"sets"`
+	// PersonalRecord is set on a newly logged exercise whose weight beats
+	// the user's previous best for the exercise.
+	PersonalRecord bool      `json:"personal_record,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 type WorkoutSessionWithExercisesInput struct {
-	GymID     int                    `json:"gym_id" binding:"required"`
-	Exercises []WorkoutExerciseInput `json:"exercises" binding:"required"`
+	GymID         int                    `json:"gym_id" binding:"required"`
+	CardioMinutes *int                   `json:"cardio_minutes" binding:"omitempty,gte=0" example:"20"`
+	Exercises     []WorkoutExerciseInput `json:"exercises" binding:"required"`
+}
+
+type WorkoutDay struct {
+	Date            string  `json:"date" example:"2025-03-14"`
+	Sessions        int     `json:"sessions"`
+	ExerciseEntries int     `json:"exercise_entries"`
+	Sets            int     `json:"sets"`
+	Volume          float64 `json:"volume"`
+}
+
+type WorkoutCalendar struct {
+	Timezone string       `json:"timezone" example:"Australia/Melbourne"`
+	From     string       `json:"from" example:"2025-02-13"`
+	To       string       `json:"to" example:"2025-03-14"`
+	Days     []WorkoutDay `json:"days"`
 }
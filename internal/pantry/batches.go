@@ -0,0 +1,201 @@
+package pantry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Stock with a known expiry date is kept as batches in pantry_batches. The
+// batches of an item never hold more than its quantity; whatever they do
+// not cover is undated stock. Stock is drawn first-expiring-first-out:
+// batches by expiry date, then in the order they were received, and undated
+// stock last.
+
+// batchEpsilon absorbs rounding when a draw empties a batch.
+const batchEpsilon = 1e-9
+
+type drawnBatch struct {
+	expiresOn time.Time
+	quantity  float64
+}
+
+// AddBatch records quantity of the item, already added to its stock, as
+// expiring on expiresOn (YYYY-MM-DD).
+func AddBatch(ctx context.Context, tx *sql.Tx, pantryItemID int, quantity float64, expiresOn string) error {
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO pantry_batches (pantry_item_id, quantity, expires_on) VALUES ($1, $2, $3)",
+		pantryItemID, quantity, expiresOn,
+	)
+	return err
+}
+
+// TrimBatches draws down the item's batches until they fit within its
+// quantity. It is called after the quantity has been lowered by hand.
+func TrimBatches(ctx context.Context, tx *sql.Tx, pantryItemID int) error {
+	var excess float64
+	err := tx.QueryRowContext(ctx, `
+			SELECT COALESCE(SUM(b.quantity), 0) - p.quantity
+			FROM pantry_items p
+			LEFT JOIN pantry_batches b ON b.pantry_item_id = p.id
+			WHERE p.id = $1
+			GROUP BY p.quantity`, pantryItemID).Scan(&excess)
+	if err != nil || excess <= batchEpsilon {
+		return err
+	}
+	_, err = drawBatches(ctx, tx, pantryItemID, excess)
+	return err
+}
+
+// drawBatches takes up to amount from the item's batches, earliest expiry
+// first, deleting the batches it empties, and returns what was taken from
+// each expiry date.
+func drawBatches(ctx context.Context, tx *sql.Tx, pantryItemID int, amount float64) ([]drawnBatch, error) {
+	rows, err := tx.QueryContext(ctx, `
+			SELECT id, expires_on, quantity
+			FROM pantry_batches
+			WHERE pantry_item_id = $1
+			ORDER BY expires_on, received_at, id
+			FOR UPDATE`, pantryItemID)
+	if err != nil {
+		return nil, err
+	}
+	type batch struct {
+		id int
+		drawnBatch
+	}
+	var batches []batch
+	for rows.Next() {
+		var b batch
+		if err := rows.Scan(&b.id, &b.expiresOn, &b.quantity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		batches = append(batches, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	quantities := make([]float64, len(batches))
+	for i, b := range batches {
+		quantities[i] = b.quantity
+	}
+	var drawn []drawnBatch
+	for i, take := range Draw(quantities, amount) {
+		b := batches[i]
+		if Emptied(b.quantity, take) {
+			_, err = tx.ExecContext(ctx, "DELETE FROM pantry_batches WHERE id = $1", b.id)
+		} else {
+			_, err = tx.ExecContext(ctx, "UPDATE pantry_batches SET quantity = quantity - $2 WHERE id = $1", b.id, take)
+		}
+		if err != nil {
+			return nil, err
+		}
+		drawn = append(drawn, drawnBatch{expiresOn: b.expiresOn, quantity: take})
+	}
+	return drawn, nil
+}
+
+// Draw splits amount across batches holding quantities, given in the order
+// stock is drawn, and returns how much to take from each batch it reaches.
+// Whatever the batches do not cover comes from undated stock.
+func Draw(quantities []float64, amount float64) []float64 {
+	var takes []float64
+	for _, q := range quantities {
+		if amount <= batchEpsilon {
+			break
+		}
+		take := min(amount, q)
+		takes = append(takes, take)
+		amount -= take
+	}
+	return takes
+}
+
+// Emptied reports whether taking take from a batch of quantity leaves
+// nothing but rounding, in which case the batch is deleted.
+func Emptied(quantity, take float64) bool {
+	return quantity-take <= batchEpsilon
+}
+
+// ErrUnitInUse is returned by ChangeUnit when an item's unit cannot be
+// converted and meals, recipes, plans or the shopping list hold amounts of
+// it in the old unit.
+var ErrUnitInUse = errors.New("pantry item is used in meals, recipes, meal plans or the shopping list")
+
+// unitReferences are the amounts of an item recorded in its unit outside
+// pantry_items.
+var unitReferences = []struct{ table, column string }{
+	{"pantry_batches", "quantity"},
+	{"meal_ingredients", "quantity_used"},
+	{"meal_ingredient_batches", "quantity"},
+	{"recipe_ingredients", "quantity"},
+	{"meal_plan_ingredients", "quantity"},
+	{"shopping_list_items", "quantity"},
+}
+
+// ChangeUnit restates every recorded amount of the item after its unit
+// changes from one canonical unit to another: its batches, the meals,
+// recipes and plans using it and its shopping list entries. When the units
+// do not convert, batches are dropped, leaving that stock undated, and
+// ErrUnitInUse is returned if anything else refers to the item.
+func ChangeUnit(ctx context.Context, tx *sql.Tx, pantryItemID int, from, to string, gramsPerItem *float64) error {
+	if from == to {
+		return nil
+	}
+	factor, ok := Convert(1, from, to, gramsPerItem)
+	if !ok {
+		var referenced bool
+		err := tx.QueryRowContext(ctx, `
+				SELECT EXISTS(SELECT 1 FROM meal_ingredients WHERE pantry_item_id = $1)
+					OR EXISTS(SELECT 1 FROM recipe_ingredients WHERE pantry_item_id = $1)
+					OR EXISTS(SELECT 1 FROM meal_plan_ingredients WHERE pantry_item_id = $1)
+					OR EXISTS(SELECT 1 FROM shopping_list_items WHERE pantry_item_id = $1 AND quantity IS NOT NULL)`,
+			pantryItemID).Scan(&referenced)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			return ErrUnitInUse
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM pantry_batches WHERE pantry_item_id = $1", pantryItemID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE shopping_list_items SET unit = $2 WHERE pantry_item_id = $1", pantryItemID, to)
+		return err
+	}
+
+	for _, ref := range unitReferences {
+		query := fmt.Sprintf("UPDATE %s SET %s = %s * $2 WHERE pantry_item_id = $1", ref.table, ref.column, ref.column)
+		if _, err := tx.ExecContext(ctx, query, pantryItemID, factor); err != nil {
+			return err
+		}
+	}
+	_, err := tx.ExecContext(ctx, "UPDATE shopping_list_items SET unit = $2 WHERE pantry_item_id = $1", pantryItemID, to)
+	return err
+}
+
+// DiscardBatch removes one of the user's batches together with the stock it
+// holds, returning sql.ErrNoRows when there is no such batch. It is used for
+// food thrown away once expired.
+func DiscardBatch(ctx context.Context, tx *sql.Tx, userID, pantryItemID, batchID int) error {
+	var quantity float64
+	err := tx.QueryRowContext(ctx, `
+			DELETE FROM pantry_batches b
+			USING pantry_items p
+			WHERE b.id = $1 AND b.pantry_item_id = $2 AND p.id = b.pantry_item_id AND p.user_id = $3
+			RETURNING b.quantity`, batchID, pantryItemID, userID).Scan(&quantity)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+			UPDATE pantry_items
+			SET quantity = GREATEST(quantity - $2, 0), updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1`, pantryItemID, quantity)
+	return err
+}
@@ -0,0 +1,265 @@
+package pantry_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	dbfiles "github.com/Ross1116/gym-tracker-backend/db"
+	"github.com/Ross1116/gym-tracker-backend/internal/migrate"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	_ "github.com/lib/pq"
+)
+
+// testDSNEnv names the environment variable holding a PostgreSQL database
+// the tests may create and drop schemas in. The tests that need it are
+// skipped without it.
+const testDSNEnv = "GYM_TEST_DATABASE_DSN"
+
+func TestDraw(t *testing.T) {
+	tests := []struct {
+		name       string
+		quantities []float64
+		amount     float64
+		want       []float64
+	}{
+		{"no batches", nil, 5, nil},
+		{"first batch", []float64{3, 4}, 2, []float64{2}},
+		{"across batches", []float64{3, 4}, 5, []float64{3, 2}},
+		{"exactly", []float64{3, 4}, 7, []float64{3, 4}},
+		{"into undated stock", []float64{3, 4}, 10, []float64{3, 4}},
+		{"nothing", []float64{3, 4}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pantry.Draw(tt.quantities, tt.amount); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Draw(%v, %v) = %v, want %v", tt.quantities, tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestConsumeAndRestore checks that a meal draws the earliest-expiring
+// batches first, records them and puts them back when it is undone.
+func TestConsumeAndRestore(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	userID, oats := seedOats(t, db)
+
+	var mealID int
+	inTx(t, db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, "INSERT INTO meals (user_id) VALUES ($1) RETURNING id", userID).Scan(&mealID); err != nil {
+			return err
+		}
+		if err := pantry.Consume(ctx, tx, userID, mealID, []models.MealIngredientInput{{PantryItemID: oats, QuantityUsed: 350}}); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO meal_ingredients (meal_id, pantry_item_id, quantity_used) VALUES ($1, $2, $3)",
+			mealID, oats, 350,
+		)
+		return err
+	})
+	wantFloats(t, db, "item quantity", []float64{450}, "SELECT quantity FROM pantry_items WHERE id = $1", oats)
+	wantFloats(t, db, "batches", []float64{50},
+		"SELECT quantity FROM pantry_batches WHERE pantry_item_id = $1 ORDER BY expires_on, id", oats)
+	wantFloats(t, db, "meal batches", []float64{100, 250},
+		"SELECT quantity FROM meal_ingredient_batches WHERE meal_id = $1 ORDER BY expires_on", mealID)
+
+	inTx(t, db, func(tx *sql.Tx) error { return pantry.Restore(ctx, tx, mealID) })
+	wantFloats(t, db, "item quantity", []float64{800}, "SELECT quantity FROM pantry_items WHERE id = $1", oats)
+	wantFloats(t, db, "batches", []float64{100, 50, 250},
+		"SELECT quantity FROM pantry_batches WHERE pantry_item_id = $1 ORDER BY expires_on, id", oats)
+	wantFloats(t, db, "meal batches", nil, "SELECT quantity FROM meal_ingredient_batches WHERE meal_id = $1", mealID)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pantry.Consume(ctx, tx, userID, mealID, []models.MealIngredientInput{{PantryItemID: oats, QuantityUsed: 900}})
+	tx.Rollback()
+	var short *pantry.InsufficientStockError
+	if !errors.As(err, &short) {
+		t.Fatalf("consuming more than is stocked: err = %v, want InsufficientStockError", err)
+	}
+	if s := short.Shortfalls[0]; s.Required != 900 || s.Available != 800 {
+		t.Errorf("shortfall = %+v, want 900 required and 800 available", s)
+	}
+}
+
+func TestChangeUnit(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	userID, oats := seedOats(t, db)
+
+	var mealID int
+	inTx(t, db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, "INSERT INTO meals (user_id) VALUES ($1) RETURNING id", userID).Scan(&mealID); err != nil {
+			return err
+		}
+		if err := pantry.Consume(ctx, tx, userID, mealID, []models.MealIngredientInput{{PantryItemID: oats, QuantityUsed: 200}}); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO meal_ingredients (meal_id, pantry_item_id, quantity_used) VALUES ($1, $2, $3)",
+			mealID, oats, 200,
+		)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO shopping_list_items (user_id, pantry_item_id, name, quantity, unit) VALUES ($1, $2, 'Oats', 500, 'g')",
+			userID, oats,
+		)
+		return err
+	})
+
+	inTx(t, db, func(tx *sql.Tx) error { return pantry.ChangeUnit(ctx, tx, oats, "g", "kg", nil) })
+	wantFloats(t, db, "batches", []float64{0.2},
+		"SELECT quantity FROM pantry_batches WHERE pantry_item_id = $1 ORDER BY expires_on, id", oats)
+	wantFloats(t, db, "meal ingredients", []float64{0.2},
+		"SELECT quantity_used FROM meal_ingredients WHERE meal_id = $1", mealID)
+	wantFloats(t, db, "meal batches", []float64{0.1, 0.1},
+		"SELECT quantity FROM meal_ingredient_batches WHERE meal_id = $1 ORDER BY expires_on", mealID)
+	wantFloats(t, db, "shopping list", []float64{0.5},
+		"SELECT quantity FROM shopping_list_items WHERE pantry_item_id = $1 AND unit = 'kg'", oats)
+
+	// Kilograms do not convert to items without a weight per item.
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pantry.ChangeUnit(ctx, tx, oats, "kg", "item", nil)
+	tx.Rollback()
+	if !errors.Is(err, pantry.ErrUnitInUse) {
+		t.Errorf("changing a used item to items: err = %v, want ErrUnitInUse", err)
+	}
+
+	inTx(t, db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM meals WHERE id = $1", mealID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE shopping_list_items SET quantity = NULL WHERE pantry_item_id = $1", oats)
+		return err
+	})
+	inTx(t, db, func(tx *sql.Tx) error { return pantry.ChangeUnit(ctx, tx, oats, "kg", "item", nil) })
+	wantFloats(t, db, "batches", nil, "SELECT quantity FROM pantry_batches WHERE pantry_item_id = $1", oats)
+	var unit string
+	if err := db.QueryRowContext(ctx, "SELECT unit FROM shopping_list_items WHERE pantry_item_id = $1", oats).Scan(&unit); err != nil {
+		t.Fatal(err)
+	}
+	if unit != "item" {
+		t.Errorf("shopping list unit = %q, want item", unit)
+	}
+}
+
+// openTestDB migrates a scratch schema, dropped after the test, in the
+// database named by GYM_TEST_DATABASE_DSN. The connection pool is limited
+// to one connection so that the search path applies to every statement.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+	ctx := context.Background()
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("pantry_test_%d", time.Now().UnixNano())
+	if _, err := db.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.ExecContext(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+	if _, err := db.ExecContext(ctx, "SET search_path TO "+schema); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := migrate.Load(dbfiles.Migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrate.New(db, migrations).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seedOats creates a user holding 800 g of oats: 100 g expiring on
+// 2025-03-20, 300 g expiring on 2025-04-01 and the rest undated.
+func seedOats(t *testing.T, db *sql.DB) (userID, pantryItemID int) {
+	t.Helper()
+	ctx := context.Background()
+	inTx(t, db, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx,
+			"INSERT INTO users (email, password_hash) VALUES ('lifter@example.com', 'hash') RETURNING id",
+		).Scan(&userID)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRowContext(ctx, `
+				INSERT INTO pantry_items (user_id, name, quantity, unit, threshold, calories_per_unit, protein_per_unit)
+				VALUES ($1, 'Oats', 800, 'g', 0, 3.89, 0.13)
+				RETURNING id`, userID).Scan(&pantryItemID)
+		if err != nil {
+			return err
+		}
+		if err := pantry.AddBatch(ctx, tx, pantryItemID, 300, "2025-04-01"); err != nil {
+			return err
+		}
+		return pantry.AddBatch(ctx, tx, pantryItemID, 100, "2025-03-20")
+	})
+	return userID, pantryItemID
+}
+
+// inTx runs fn in a transaction and commits it, failing the test on error.
+func inTx(t *testing.T, db *sql.DB, fn func(tx *sql.Tx) error) {
+	t.Helper()
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// wantFloats checks the single column of numbers returned by query.
+func wantFloats(t *testing.T, db *sql.DB, what string, want []float64, query string, args ...any) {
+	t.Helper()
+	rows, err := db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []float64
+	for rows.Next() {
+		var f float64
+		if err := rows.Scan(&f); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, f)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %v, want %v", what, got, want)
+	}
+}
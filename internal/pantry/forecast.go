@@ -0,0 +1,130 @@
+package pantry
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+// Outlook projects each pantry item's average daily use, taken over the
+// History days of meals before Now, forward for Days days. Now should be in
+// the user's time zone so that run-out dates fall on the user's calendar.
+type Outlook struct {
+	Now     time.Time
+	History int
+	Days    float64
+}
+
+func (o *Outlook) args() (since, until, days any) {
+	if o == nil {
+		return nil, nil, nil
+	}
+	now := o.Now.UTC()
+	return now.AddDate(0, 0, -o.History), now, o.Days
+}
+
+// dailyUsage returns a CTE named usage with the average daily quantity of
+// each pantry item used by the meals of user $1 logged between the
+// timestamps in parameters $since and $until. Items added during that time
+// are averaged over the days they have existed, and never over less than a
+// day. A NULL window selects nothing.
+func dailyUsage(since, until int) string {
+	return fmt.Sprintf(`
+		usage AS (
+				SELECT mi.pantry_item_id,
+						SUM(mi.quantity_used) / GREATEST(
+								EXTRACT(EPOCH FROM $%[2]d::timestamp - GREATEST($%[1]d::timestamp, MIN(p.created_at))) / 86400,
+								1
+						) AS daily
+				FROM meal_ingredients mi
+				JOIN meals m ON m.id = mi.meal_id
+				JOIN pantry_items p ON p.id = mi.pantry_item_id
+				WHERE m.user_id = $1 AND m.created_at >= $%[1]d::timestamp AND m.created_at < $%[2]d::timestamp
+				GROUP BY mi.pantry_item_id
+		)`, since, until)
+}
+
+// Forecast projects when each of the user's pantry items will run out at
+// its recent rate of use and suggests a threshold that leaves leadDays of
+// use in stock when the item is put on the shopping list. Items are ordered
+// by how soon they run out; items without recent use come last, by name.
+func Forecast(ctx context.Context, q queryer, userID int, o Outlook, leadDays float64) ([]models.PantryForecast, error) {
+	since, until, _ := o.args()
+	rows, err := q.QueryContext(ctx, `
+			WITH`+dailyUsage(2, 3)+`
+			SELECT p.id, p.name, p.unit, p.quantity, p.threshold, COALESCE(u.daily, 0)
+			FROM pantry_items p
+			LEFT JOIN usage u ON u.pantry_item_id = p.id
+			WHERE p.user_id = $1
+			ORDER BY p.name`, userID, since, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.PantryForecast{}
+	for rows.Next() {
+		var f models.PantryForecast
+		if err := rows.Scan(&f.PantryItemID, &f.Name, &f.Unit, &f.Quantity, &f.Threshold, &f.DailyUsage); err != nil {
+			return nil, err
+		}
+		if f.DailyUsage > 0 {
+			daysLeft := f.Quantity / f.DailyUsage
+			runsOutOn := o.Now.Add(time.Duration(daysLeft * float64(24*time.Hour))).Format("2006-01-02")
+			suggested := suggestThreshold(f.DailyUsage*leadDays, f.Unit)
+			f.DaysLeft, f.RunsOutOn, f.SuggestedThreshold = &daysLeft, &runsOutOn, &suggested
+		}
+		items = append(items, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].DaysLeft, items[j].DaysLeft
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a < *b
+	})
+	return items, nil
+}
+
+// TuneThresholds sets the threshold of every pantry item with recent use to
+// the one Forecast suggests and returns the IDs of the items it changed.
+func TuneThresholds(ctx context.Context, tx *sql.Tx, userID int, o Outlook, leadDays float64) ([]int64, error) {
+	forecast, err := Forecast(ctx, tx, userID, o, leadDays)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int64{}
+	for _, f := range forecast {
+		if f.SuggestedThreshold == nil || *f.SuggestedThreshold == f.Threshold {
+			continue
+		}
+		_, err := tx.ExecContext(ctx,
+			"UPDATE pantry_items SET threshold = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1",
+			f.PantryItemID, *f.SuggestedThreshold,
+		)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, int64(f.PantryItemID))
+	}
+	return ids, nil
+}
+
+// suggestThreshold rounds a threshold up to whole items, or to two decimal
+// places for weights and volumes, ignoring floating point noise.
+func suggestThreshold(quantity float64, unit string) float64 {
+	if unit == "item" {
+		return math.Ceil(quantity - 1e-9)
+	}
+	return math.Ceil(quantity*100-1e-6) / 100
+}
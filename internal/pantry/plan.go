@@ -0,0 +1,83 @@
+package pantry
+
+import (
+	"context"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+// PlanWindow is the inclusive range of days, as YYYY-MM-DD dates, whose
+// meal plan is taken into account.
+type PlanWindow struct {
+	From string
+	To   string
+}
+
+func (w *PlanWindow) args() (from, to any) {
+	if w == nil {
+		return nil, nil
+	}
+	return w.From, w.To
+}
+
+// plannedRequirements is a CTE named required with the total quantity of
+// each pantry item used by the plan entries of user $1 between dates $2 and
+// $3. Recipe ingredients are scaled to the planned servings. A NULL range
+// selects nothing.
+const plannedRequirements = `
+		required AS (
+				SELECT pantry_item_id, SUM(quantity) AS quantity
+				FROM (
+						SELECT ri.pantry_item_id, ri.quantity * e.servings / r.servings AS quantity
+						FROM meal_plan_entries e
+						JOIN recipes r ON r.id = e.recipe_id
+						JOIN recipe_ingredients ri ON ri.recipe_id = r.id
+						WHERE e.user_id = $1 AND e.planned_date BETWEEN $2::date AND $3::date
+						UNION ALL
+						SELECT mi.pantry_item_id, mi.quantity
+						FROM meal_plan_entries e
+						JOIN meal_plan_ingredients mi ON mi.entry_id = e.id
+						WHERE e.user_id = $1 AND e.planned_date BETWEEN $2::date AND $3::date
+				) uses
+				GROUP BY pantry_item_id
+		)`
+
+// PlanRequirements compares what the user's meal plan uses over the window
+// with current stock, one row per pantry item, largest shortfall first.
+func PlanRequirements(ctx context.Context, q queryer, userID int, plan PlanWindow) ([]models.MealPlanRequirement, error) {
+	from, to := plan.args()
+	rows, err := q.QueryContext(ctx, `
+			WITH`+plannedRequirements+`
+			SELECT
+					p.id,
+					p.name,
+					p.unit,
+					r.quantity,
+					p.quantity,
+					GREATEST(r.quantity - p.quantity, 0)
+			FROM required r
+			JOIN pantry_items p ON p.id = r.pantry_item_id
+			ORDER BY 6 DESC, 2`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.MealPlanRequirement{}
+	for rows.Next() {
+		var item models.MealPlanRequirement
+		err := rows.Scan(
+			&item.PantryItemID,
+			&item.Name,
+			&item.Unit,
+			&item.Required,
+			&item.Available,
+			&item.Shortfall,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,226 @@
+package pantry
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// neededStock is a CTE named needed with, for each pantry item, the amount
+// the plan (the required CTE) and the outlook (the usage CTE, carried
+// forward for $6 days) say will be used: the larger of the two, since
+// planned meals are part of the usage the outlook projects.
+const neededStock = `
+		needed AS (
+				SELECT
+						COALESCE(r.pantry_item_id, u.pantry_item_id) AS pantry_item_id,
+						r.quantity AS planned,
+						u.daily * $6 AS projected,
+						GREATEST(COALESCE(r.quantity, 0), COALESCE(u.daily * $6, 0)) AS quantity
+				FROM required r
+				FULL JOIN usage u ON u.pantry_item_id = r.pantry_item_id
+		)`
+
+// ShoppingList returns the user's shopping list: every pantry item below its
+// threshold from the shopping_list view followed by the items added by hand.
+// When plan is set, pantry items the meal plan over that window uses are
+// listed with source "plan" instead, with enough to cook the planned meals
+// and still be left at the threshold. When outlook is set, items recent
+// meals are projected to take below their threshold within its days are
+// listed the same way with source "forecast". Unchecked rows come first.
+func ShoppingList(ctx context.Context, q queryer, userID int, plan *PlanWindow, outlook *Outlook) ([]models.ShoppingListItem, error) {
+	from, to := plan.args()
+	since, until, days := outlook.args()
+	rows, err := q.QueryContext(ctx, `
+			WITH`+plannedRequirements+`,`+dailyUsage(4, 5)+`,`+neededStock+`
+			SELECT
+					NULL::integer,
+					s.pantry_item_id,
+					s.name,
+					s.quantity_needed,
+					s.unit,
+					'threshold',
+					NULL::decimal,
+					NULL::decimal,
+					c.pantry_item_id IS NOT NULL
+			FROM shopping_list s
+			LEFT JOIN needed n ON n.pantry_item_id = s.pantry_item_id
+			LEFT JOIN shopping_list_checks c ON c.pantry_item_id = s.pantry_item_id
+			WHERE s.user_id = $1 AND n.pantry_item_id IS NULL
+			UNION ALL
+			SELECT
+					NULL::integer,
+					p.id,
+					p.name,
+					p.threshold + n.quantity - p.quantity,
+					p.unit,
+					CASE WHEN n.planned >= n.quantity THEN 'plan' ELSE 'forecast' END,
+					n.planned,
+					n.projected,
+					c.pantry_item_id IS NOT NULL
+			FROM needed n
+			JOIN pantry_items p ON p.id = n.pantry_item_id
+			LEFT JOIN shopping_list_checks c ON c.pantry_item_id = p.id
+			WHERE p.quantity < p.threshold + n.quantity
+			UNION ALL
+			SELECT id, pantry_item_id, name, quantity, unit, 'manual', NULL, NULL, checked
+			FROM shopping_list_items
+			WHERE user_id = $1
+			ORDER BY 9, 3`, userID, from, to, since, until, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.ShoppingListItem{}
+	for rows.Next() {
+		var item models.ShoppingListItem
+		err := rows.Scan(
+			&item.ItemID,
+			&item.PantryItemID,
+			&item.Name,
+			&item.Quantity,
+			&item.Unit,
+			&item.Source,
+			&item.Planned,
+			&item.Projected,
+			&item.Checked,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// Purchase restocks the pantry from every checked row of the user's
+// shopping list, as listed by ShoppingList for the same plan window and
+// outlook. Checked manual items tied to a pantry item add their quantity;
+// checked threshold, plan and forecast rows bring the item up to its
+// threshold plus whatever the plan or outlook says will be used.
+// Checked manual items and check marks are then cleared. The IDs of the
+// restocked pantry items and the number of manual items removed are
+// returned.
+func Purchase(ctx context.Context, tx *sql.Tx, userID int, plan *PlanWindow, outlook *Outlook) ([]int64, int64, error) {
+	restocked := map[int64]bool{}
+	collect := func(rows *sql.Rows, err error) error {
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			restocked[id] = true
+		}
+		return rows.Err()
+	}
+
+	err := collect(tx.QueryContext(ctx, `
+			UPDATE pantry_items p
+			SET quantity = p.quantity + i.quantity, updated_at = CURRENT_TIMESTAMP
+			FROM (
+					SELECT pantry_item_id, SUM(quantity) AS quantity
+					FROM shopping_list_items
+					WHERE user_id = $1 AND checked AND pantry_item_id IS NOT NULL AND quantity IS NOT NULL
+					GROUP BY pantry_item_id
+			) i
+			WHERE p.id = i.pantry_item_id AND p.user_id = $1
+			RETURNING p.id`, userID))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	from, to := plan.args()
+	since, until, days := outlook.args()
+	err = collect(tx.QueryContext(ctx, `
+			WITH`+plannedRequirements+`,`+dailyUsage(4, 5)+`,`+neededStock+`
+			UPDATE pantry_items p
+			SET quantity = p.threshold + COALESCE(n.quantity, 0), updated_at = CURRENT_TIMESTAMP
+			FROM shopping_list_checks c
+			LEFT JOIN needed n ON n.pantry_item_id = c.pantry_item_id
+			WHERE c.pantry_item_id = p.id AND p.user_id = $1
+					AND p.quantity < p.threshold + COALESCE(n.quantity, 0)
+			RETURNING p.id`, userID, from, to, since, until, days))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+			DELETE FROM shopping_list_checks c
+			USING pantry_items p
+			WHERE c.pantry_item_id = p.id AND p.user_id = $1`, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM shopping_list_items WHERE user_id = $1 AND checked", userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]int64, 0, len(restocked))
+	for id := range restocked {
+		ids = append(ids, id)
+	}
+	return ids, removed, nil
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`", "#", `\#`,
+)
+
+// FormatShoppingList renders the list for sharing, as plain text or as a
+// Markdown task list.
+func FormatShoppingList(items []models.ShoppingListItem, markdown bool) string {
+	var b strings.Builder
+	if markdown {
+		b.WriteString("# Shopping list\n\n")
+	} else {
+		b.WriteString("Shopping list\n\n")
+	}
+
+	for _, item := range items {
+		box := "[ ]"
+		if item.Checked {
+			box = "[x]"
+		}
+		name := item.Name
+		if markdown {
+			b.WriteString("- ")
+			name = markdownEscaper.Replace(name)
+		}
+		b.WriteString(box + " " + name)
+		if amount := formatAmount(item.Quantity, item.Unit); amount != "" {
+			b.WriteString(" - " + amount)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func formatAmount(quantity *float64, unit *string) string {
+	if quantity == nil {
+		return ""
+	}
+	s := strconv.FormatFloat(math.Round(*quantity*100)/100, 'f', -1, 64)
+	if unit != nil && *unit != "" && *unit != "item" {
+		s += " " + *unit
+	}
+	return s
+}
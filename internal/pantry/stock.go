@@ -0,0 +1,210 @@
+// Package pantry holds the stock-keeping rules shared by meal logging and
+// the other features that draw on a user's pantry.
+package pantry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// ErrItemNotFound is returned when a use refers to a pantry item that does
+// not exist or belongs to another user.
+var ErrItemNotFound = errors.New("pantry item not found")
+
+// InsufficientStockError is returned by Consume when one or more items do
+// not hold enough stock. Nothing is deducted in that case.
+type InsufficientStockError struct {
+	Shortfalls []models.StockShortfall
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock for %d pantry item(s)", len(e.Shortfalls))
+}
+
+// Amount is a quantity of a pantry item in Unit, or in the unit the item is
+// stocked in when Unit is empty.
+type Amount struct {
+	PantryItemID int
+	Quantity     float64
+	Unit         string
+}
+
+// InStockUnits converts amounts into the units their pantry items are
+// stocked in, folding repeated items into one amount in first-seen order.
+// ErrItemNotFound is returned for items that are not the user's and a
+// *UnitError for units that do not convert.
+func InStockUnits(ctx context.Context, q queryer, userID int, amounts []Amount) ([]Amount, error) {
+	ids := make([]int64, len(amounts))
+	for i, a := range amounts {
+		ids[i] = int64(a.PantryItemID)
+	}
+
+	rows, err := q.QueryContext(ctx, `
+			SELECT id, name, unit, grams_per_item
+			FROM pantry_items
+			WHERE user_id = $1 AND id = ANY($2)`, userID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	items := map[int]StockItem{}
+	for rows.Next() {
+		var id int
+		var it StockItem
+		if err := rows.Scan(&id, &it.Name, &it.Unit, &it.GramsPerItem); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		items[id] = it
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ToStockUnits(items, amounts)
+}
+
+// StockItem is what converting amounts needs to know of a pantry item.
+type StockItem struct {
+	Name         string
+	Unit         string
+	GramsPerItem *float64
+}
+
+// ToStockUnits is InStockUnits over the user's items, keyed by ID, once
+// they have been loaded.
+func ToStockUnits(items map[int]StockItem, amounts []Amount) ([]Amount, error) {
+	index := map[int]int{}
+	out := make([]Amount, 0, len(amounts))
+	for _, a := range amounts {
+		it, ok := items[a.PantryItemID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrItemNotFound, a.PantryItemID)
+		}
+		quantity := a.Quantity
+		if a.Unit != "" {
+			from, ok := ParseUnit(a.Unit)
+			if !ok {
+				return nil, &UnitError{PantryItemID: a.PantryItemID, Name: it.Name}
+			}
+			if quantity, ok = Convert(a.Quantity, from, it.Unit, it.GramsPerItem); !ok {
+				return nil, &UnitError{PantryItemID: a.PantryItemID, Name: it.Name, From: from, To: it.Unit}
+			}
+		}
+		if i, ok := index[a.PantryItemID]; ok {
+			out[i].Quantity += quantity
+			continue
+		}
+		index[a.PantryItemID] = len(out)
+		out = append(out, Amount{PantryItemID: a.PantryItemID, Quantity: quantity, Unit: it.Unit})
+	}
+	return out, nil
+}
+
+// Consume deducts each use, in the item's own unit, from the user's pantry
+// inside tx and records against the meal which dated batches the stock came
+// from. Uses must name distinct pantry items. The affected rows are locked
+// in id order so that concurrent meals cannot both spend the same stock.
+func Consume(ctx context.Context, tx *sql.Tx, userID, mealID int, uses []models.MealIngredientInput) error {
+	if len(uses) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(uses))
+	for i, u := range uses {
+		ids[i] = int64(u.PantryItemID)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+			SELECT id, name, unit, quantity
+			FROM pantry_items
+			WHERE user_id = $1 AND id = ANY($2)
+			ORDER BY id
+			FOR UPDATE`, userID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	stock := map[int]models.StockShortfall{}
+	for rows.Next() {
+		var s models.StockShortfall
+		if err := rows.Scan(&s.PantryItemID, &s.Name, &s.Unit, &s.Available); err != nil {
+			rows.Close()
+			return err
+		}
+		stock[s.PantryItemID] = s
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var shortfalls []models.StockShortfall
+	for _, u := range uses {
+		s, ok := stock[u.PantryItemID]
+		if !ok {
+			return fmt.Errorf("%w: %d", ErrItemNotFound, u.PantryItemID)
+		}
+		if u.QuantityUsed > s.Available {
+			s.Required = u.QuantityUsed
+			shortfalls = append(shortfalls, s)
+		}
+	}
+	if len(shortfalls) > 0 {
+		return &InsufficientStockError{Shortfalls: shortfalls}
+	}
+
+	for _, u := range uses {
+		_, err := tx.ExecContext(ctx,
+			"UPDATE pantry_items SET quantity = quantity - $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1",
+			u.PantryItemID, u.QuantityUsed,
+		)
+		if err != nil {
+			return err
+		}
+
+		drawn, err := drawBatches(ctx, tx, u.PantryItemID, u.QuantityUsed)
+		if err != nil {
+			return err
+		}
+		for _, d := range drawn {
+			_, err := tx.ExecContext(ctx, `
+					INSERT INTO meal_ingredient_batches (meal_id, pantry_item_id, expires_on, quantity)
+					VALUES ($1, $2, $3, $4)`,
+				mealID, u.PantryItemID, d.expiresOn, d.quantity,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Restore puts the stock used by a meal back into the pantry, including the
+// dated batches it was drawn from. It is called before a meal's ingredients
+// are replaced or the meal is deleted.
+func Restore(ctx context.Context, tx *sql.Tx, mealID int) error {
+	_, err := tx.ExecContext(ctx, `
+			UPDATE pantry_items p
+			SET quantity = p.quantity + mi.quantity_used, updated_at = CURRENT_TIMESTAMP
+			FROM meal_ingredients mi
+			WHERE mi.meal_id = $1 AND mi.pantry_item_id = p.id`, mealID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+			INSERT INTO pantry_batches (pantry_item_id, quantity, expires_on)
+			SELECT pantry_item_id, quantity, expires_on
+			FROM meal_ingredient_batches
+			WHERE meal_id = $1`, mealID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM meal_ingredient_batches WHERE meal_id = $1", mealID)
+	return err
+}
@@ -0,0 +1,110 @@
+package pantry
+
+import (
+	"fmt"
+	"strings"
+)
+
+type dimension int
+
+const (
+	mass dimension = iota
+	volume
+	count
+)
+
+type unitDef struct {
+	dim dimension
+	// base is the size of the unit in grams, millilitres or items.
+	base float64
+}
+
+var unitDefs = map[string]unitDef{
+	"mg":    {mass, 0.001},
+	"g":     {mass, 1},
+	"kg":    {mass, 1000},
+	"oz":    {mass, 28.349523125},
+	"lb":    {mass, 453.59237},
+	"ml":    {volume, 1},
+	"l":     {volume, 1000},
+	"tsp":   {volume, 4.92892159375},
+	"tbsp":  {volume, 14.78676478125},
+	"fl_oz": {volume, 29.5735295625},
+	"cup":   {volume, 240},
+	"item":  {count, 1},
+}
+
+// unitAliases maps the accepted spellings of a unit to its canonical name.
+var unitAliases = map[string]string{
+	"milligram": "mg", "milligrams": "mg",
+	"gram": "g", "grams": "g", "gramme": "g", "grammes": "g",
+	"kilogram": "kg", "kilograms": "kg", "kgs": "kg",
+	"ounce": "oz", "ounces": "oz",
+	"pound": "lb", "pounds": "lb", "lbs": "lb",
+	"milliliter": "ml", "milliliters": "ml", "millilitre": "ml", "millilitres": "ml",
+	"liter": "l", "liters": "l", "litre": "l", "litres": "l",
+	"teaspoon": "tsp", "teaspoons": "tsp",
+	"tablespoon": "tbsp", "tablespoons": "tbsp",
+	"fl oz": "fl_oz", "floz": "fl_oz", "fluid ounce": "fl_oz", "fluid ounces": "fl_oz",
+	"cups":  "cup",
+	"items": "item", "piece": "item", "pieces": "item", "pc": "item", "pcs": "item", "each": "item",
+}
+
+// stockUnits are the units a pantry item can be stocked in.
+var stockUnits = map[string]bool{"g": true, "kg": true, "ml": true, "l": true, "item": true}
+
+// ParseUnit returns the canonical name of a unit spelling, ignoring case
+// and surrounding space.
+func ParseUnit(s string) (string, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if alias, ok := unitAliases[s]; ok {
+		s = alias
+	}
+	_, ok := unitDefs[s]
+	return s, ok
+}
+
+// IsStockUnit reports whether a canonical unit can be used for pantry
+// stock: g, kg, ml, l or item.
+func IsStockUnit(unit string) bool {
+	return stockUnits[unit]
+}
+
+// UnitError is returned when an amount cannot be converted into the unit
+// its pantry item is stocked in.
+type UnitError struct {
+	PantryItemID int
+	Name         string
+	From, To     string
+}
+
+func (e *UnitError) Error() string {
+	if e.From == "" {
+		return fmt.Sprintf("unknown unit for %s", e.Name)
+	}
+	return fmt.Sprintf("cannot convert %s to %s for %s", e.From, e.To, e.Name)
+}
+
+// Convert converts quantity from one canonical unit to another. Mass and
+// volume convert within their own dimension; items convert to and from
+// mass when gramsPerItem is known.
+func Convert(quantity float64, from, to string, gramsPerItem *float64) (float64, bool) {
+	f, okFrom := unitDefs[from]
+	t, okTo := unitDefs[to]
+	if !okFrom || !okTo {
+		return 0, false
+	}
+	if f.dim == t.dim {
+		return quantity * f.base / t.base, true
+	}
+	if gramsPerItem == nil {
+		return 0, false
+	}
+	switch {
+	case f.dim == count && t.dim == mass:
+		return quantity * *gramsPerItem / t.base, true
+	case f.dim == mass && t.dim == count:
+		return quantity * f.base / *gramsPerItem, true
+	}
+	return 0, false
+}
@@ -0,0 +1,57 @@
+// Package requestid tags every request with an ID that is echoed in the
+// X-Request-ID response header and in error responses, so that a failure
+// reported by a client can be found in the server logs.
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	Header = "X-Request-ID"
+
+	contextKey = "request_id"
+	// maxLength bounds IDs supplied by clients or proxies.
+	maxLength = 128
+)
+
+// Middleware keeps a well-formed X-Request-ID sent with the request, such
+// as one set by a load balancer, and otherwise generates a new ID.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !valid(id) {
+			id = generate()
+		}
+		c.Set(contextKey, id)
+		c.Header(Header, id)
+		c.Next()
+	}
+}
+
+// Get returns the ID of the request, or "" outside Middleware.
+func Get(c *gin.Context) string {
+	return c.GetString(contextKey)
+}
+
+func generate() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// valid accepts IDs of printable ASCII without spaces.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
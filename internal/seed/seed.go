@@ -0,0 +1,64 @@
+// Package seed loads the shared reference data a fresh database needs: the
+// global exercise and equipment type lists users pick from.
+package seed
+
+import (
+	"context"
+	"database/sql"
+)
+
+var Exercises = []string{
+	"Bench Press", "Incline Bench Press", "Overhead Press", "Push Press", "Dip",
+	"Squat", "Front Squat", "Leg Press", "Lunge", "Romanian Deadlift",
+	"Deadlift", "Hip Thrust", "Leg Curl", "Leg Extension", "Calf Raise",
+	"Pull-Up", "Chin-Up", "Lat Pulldown", "Barbell Row", "Seated Cable Row",
+	"Face Pull", "Lateral Raise", "Biceps Curl", "Triceps Pushdown", "Plank",
+}
+
+var EquipmentTypes = []string{
+	"Barbell", "Dumbbell", "Kettlebell", "EZ Bar", "Trap Bar", "Cable Machine",
+	"Smith Machine", "Leg Press Machine", "Pull-Up Bar", "Bench", "Bodyweight",
+	"Resistance Band",
+}
+
+// Report counts the rows Run inserted; names already present are skipped.
+type Report struct {
+	Exercises      int `json:"exercises"`
+	EquipmentTypes int `json:"equipment_types"`
+}
+
+// Run inserts the reference data in one transaction. It can be run again
+// safely, including after users have added their own entries.
+func Run(ctx context.Context, db *sql.DB) (Report, error) {
+	var report Report
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return report, err
+	}
+	defer tx.Rollback()
+
+	if report.Exercises, err = insertNames(ctx, tx, "exercises", Exercises); err != nil {
+		return report, err
+	}
+	if report.EquipmentTypes, err = insertNames(ctx, tx, "equipment_types", EquipmentTypes); err != nil {
+		return report, err
+	}
+	return report, tx.Commit()
+}
+
+func insertNames(ctx context.Context, tx *sql.Tx, table string, names []string) (int, error) {
+	inserted := 0
+	for _, name := range names {
+		result, err := tx.ExecContext(ctx,
+			"INSERT INTO "+table+" (name) VALUES ($1) ON CONFLICT (name) DO NOTHING", name)
+		if err != nil {
+			return inserted, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return inserted, err
+		}
+		inserted += int(n)
+	}
+	return inserted, nil
+}
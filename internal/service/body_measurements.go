@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/analytics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var errMeasurementNotFound = errorf(NotFound, "Measurement not found")
+
+type BodyMeasurementService struct {
+	measurements store.BodyMeasurementStore
+}
+
+func NewBodyMeasurementService(measurements store.BodyMeasurementStore) *BodyMeasurementService {
+	return &BodyMeasurementService{measurements: measurements}
+}
+
+// List returns the measurements taken on the days from from to to, oldest
+// first.
+func (s *BodyMeasurementService) List(ctx context.Context, userID int, from, to time.Time) ([]models.BodyMeasurement, error) {
+	return s.measurements.List(ctx, userID, from, to.AddDate(0, 0, 1))
+}
+
+// Create logs a measurement, taken now unless the input says otherwise.
+func (s *BodyMeasurementService) Create(ctx context.Context, userID int, input models.BodyMeasurementInput) (models.BodyMeasurement, error) {
+	if err := checkMeasurement(&input); err != nil {
+		return models.BodyMeasurement{}, err
+	}
+	return s.measurements.Create(ctx, userID, input)
+}
+
+func (s *BodyMeasurementService) Update(ctx context.Context, userID, id int, input models.BodyMeasurementInput) (models.BodyMeasurement, error) {
+	if err := checkMeasurement(&input); err != nil {
+		return models.BodyMeasurement{}, err
+	}
+	m, err := s.measurements.Update(ctx, userID, id, input)
+	if errors.Is(err, store.ErrNotFound) {
+		return m, errMeasurementNotFound
+	}
+	return m, err
+}
+
+func (s *BodyMeasurementService) Delete(ctx context.Context, userID, id int) error {
+	err := s.measurements.Delete(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errMeasurementNotFound
+	}
+	return err
+}
+
+// checkMeasurement requires at least one measurement and defaults the time
+// it was taken to now.
+func checkMeasurement(input *models.BodyMeasurementInput) error {
+	if input.BodyweightKg == nil && input.BodyFatPct == nil && input.WaistCm == nil &&
+		input.ArmsCm == nil && input.ChestCm == nil {
+		return errorf(Validation, "At least one measurement is required")
+	}
+	if input.MeasuredAt == nil {
+		now := time.Now()
+		input.MeasuredAt = &now
+	}
+	return nil
+}
+
+// Trend smooths the user's bodyweight over the days from from to to in loc
+// with alpha, in (0, 1], and sets it beside the training of each week. The
+// best estimated 1RM on exerciseID is included unless it is 0.
+func (s *BodyMeasurementService) Trend(ctx context.Context, userID int, loc *time.Location, from, to time.Time, alpha float64, exerciseID int) (models.BodyMeasurementTrend, error) {
+	if alpha <= 0 || alpha > 1 {
+		return models.BodyMeasurementTrend{}, errorf(Validation, "alpha must be a number in (0, 1]")
+	}
+	end := to.AddDate(0, 0, 1)
+
+	entries, err := s.measurements.List(ctx, userID, from, end)
+	if err != nil {
+		return models.BodyMeasurementTrend{}, err
+	}
+
+	// The average is seeded from the full history so the first points in
+	// the range are not just raw weigh-ins.
+	samples, err := s.measurements.Bodyweights(ctx, userID, end)
+	if err != nil {
+		return models.BodyMeasurementTrend{}, err
+	}
+
+	result := models.BodyMeasurementTrend{
+		Alpha:    alpha,
+		Timezone: loc.String(),
+		Entries:  entries,
+		Trend:    []models.BodyweightTrendPoint{},
+	}
+	if exerciseID != 0 {
+		result.ExerciseID = &exerciseID
+	}
+	points := analytics.EWMA(samples, alpha)
+	for _, p := range points {
+		if p.At.Before(from) {
+			continue
+		}
+		result.Trend = append(result.Trend, models.BodyweightTrendPoint{
+			MeasuredAt:   p.At,
+			BodyweightKg: p.Value,
+			TrendKg:      p.Trend,
+			WeeklyRateKg: p.WeeklyRate,
+		})
+	}
+	if n := len(result.Trend); n > 0 {
+		result.WeeklyRateKg = result.Trend[n-1].WeeklyRateKg
+	}
+
+	result.Weeks, err = s.weeks(ctx, userID, exerciseID, loc.String(), from, end, points)
+	if err != nil {
+		return models.BodyMeasurementTrend{}, err
+	}
+	return result, nil
+}
+
+// weeks returns one row per Monday-based week in [from, end) with the
+// bodyweight trend at the end of that week and the training done during
+// it, including the best estimated 1RM on exerciseID unless it is 0.
+func (s *BodyMeasurementService) weeks(ctx context.Context, userID, exerciseID int, tz string, from, end time.Time, points []analytics.TrendPoint) ([]models.BodyweightWeek, error) {
+	training, err := s.measurements.TrainingWeeks(ctx, userID, exerciseID, tz, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	weeks := []models.BodyweightWeek{}
+	start := from.AddDate(0, 0, -((int(from.Weekday()) + 6) % 7))
+	prevTrend := trendBefore(points, start)
+	for ws := start; ws.Before(end); ws = ws.AddDate(0, 0, 7) {
+		w := training[ws.Format(time.DateOnly)]
+		w.WeekStart = ws.Format(time.DateOnly)
+		w.TrendKg = trendBefore(points, ws.AddDate(0, 0, 7))
+		if w.TrendKg != nil && prevTrend != nil {
+			change := *w.TrendKg - *prevTrend
+			w.TrendChangeKg = &change
+		}
+		prevTrend = w.TrendKg
+
+		weeks = append(weeks, w)
+	}
+	return weeks, nil
+}
+
+// trendBefore returns the trend value of the last point before t.
+func trendBefore(points []analytics.TrendPoint, t time.Time) *float64 {
+	for i := len(points) - 1; i >= 0; i-- {
+		if points[i].At.Before(t) {
+			trend := points[i].Trend
+			return &trend
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var errEquipmentNotFound = errorf(NotFound, "Equipment not found")
+
+type EquipmentService struct {
+	equipment      store.EquipmentStore
+	equipmentTypes store.EquipmentTypeStore
+}
+
+func NewEquipmentService(equipment store.EquipmentStore, equipmentTypes store.EquipmentTypeStore) *EquipmentService {
+	return &EquipmentService{equipment: equipment, equipmentTypes: equipmentTypes}
+}
+
+// ListByGym returns the gym's equipment, or a NotFound error when it has
+// none.
+func (s *EquipmentService) ListByGym(ctx context.Context, gymID int) ([]models.GymEquipmentWithDetails, error) {
+	equipment, err := s.equipment.ListByGym(ctx, gymID)
+	if err != nil {
+		return nil, err
+	}
+	if len(equipment) == 0 {
+		return nil, errorf(NotFound, "No equipments found for this gym")
+	}
+	return equipment, nil
+}
+
+func (s *EquipmentService) Get(ctx context.Context, id int) (models.GymEquipmentWithDetails, error) {
+	e, err := s.equipment.Get(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return e, errEquipmentNotFound
+	}
+	return e, err
+}
+
+func (s *EquipmentService) Create(ctx context.Context, gymID int, input models.GymEquipmentInput) (models.GymEquipment, error) {
+	return s.equipment.Create(ctx, gymID, input)
+}
+
+// Update replaces the equipment's details. The equipment type must exist.
+func (s *EquipmentService) Update(ctx context.Context, id int, input models.GymEquipmentInput) (models.GymEquipmentWithDetails, error) {
+	exists, err := s.equipment.Exists(ctx, id)
+	if err != nil {
+		return models.GymEquipmentWithDetails{}, err
+	}
+	if !exists {
+		return models.GymEquipmentWithDetails{}, errEquipmentNotFound
+	}
+
+	exists, err = s.equipmentTypes.Exists(ctx, input.EquipmentTypeID)
+	if err != nil {
+		return models.GymEquipmentWithDetails{}, err
+	}
+	if !exists {
+		return models.GymEquipmentWithDetails{}, errorf(Validation, "Equipment type not found")
+	}
+
+	e, err := s.equipment.Update(ctx, id, input)
+	if errors.Is(err, store.ErrNotFound) {
+		return e, errEquipmentNotFound
+	}
+	return e, err
+}
+
+// Delete removes equipment that no workout has used.
+func (s *EquipmentService) Delete(ctx context.Context, id int) error {
+	inUse, err := s.equipment.InUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errorf(Conflict, "Cannot delete equipment that is used in workout sessions")
+	}
+
+	err = s.equipment.Delete(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errEquipmentNotFound
+	}
+	return err
+}
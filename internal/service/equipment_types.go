@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var (
+	errEquipmentTypeNotFound  = errorf(NotFound, "Equipment type not found")
+	errEquipmentTypeNameTaken = errorf(Conflict, "Equipment type with this name already exists")
+)
+
+type EquipmentTypeService struct {
+	equipmentTypes store.EquipmentTypeStore
+}
+
+func NewEquipmentTypeService(equipmentTypes store.EquipmentTypeStore) *EquipmentTypeService {
+	return &EquipmentTypeService{equipmentTypes: equipmentTypes}
+}
+
+func (s *EquipmentTypeService) List(ctx context.Context) ([]models.EquipmentType, error) {
+	return s.equipmentTypes.List(ctx)
+}
+
+func (s *EquipmentTypeService) Get(ctx context.Context, id int) (models.EquipmentType, error) {
+	t, err := s.equipmentTypes.Get(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return t, errEquipmentTypeNotFound
+	}
+	return t, err
+}
+
+// Create adds an equipment type. Names are unique.
+func (s *EquipmentTypeService) Create(ctx context.Context, name string) (models.EquipmentType, error) {
+	taken, err := s.equipmentTypes.NameTaken(ctx, name, 0)
+	if err != nil {
+		return models.EquipmentType{}, err
+	}
+	if taken {
+		return models.EquipmentType{}, errEquipmentTypeNameTaken
+	}
+	return s.equipmentTypes.Create(ctx, name)
+}
+
+func (s *EquipmentTypeService) Update(ctx context.Context, id int, name string) (models.EquipmentType, error) {
+	exists, err := s.equipmentTypes.Exists(ctx, id)
+	if err != nil {
+		return models.EquipmentType{}, err
+	}
+	if !exists {
+		return models.EquipmentType{}, errEquipmentTypeNotFound
+	}
+
+	taken, err := s.equipmentTypes.NameTaken(ctx, name, id)
+	if err != nil {
+		return models.EquipmentType{}, err
+	}
+	if taken {
+		return models.EquipmentType{}, errEquipmentTypeNameTaken
+	}
+
+	t, err := s.equipmentTypes.Update(ctx, id, name)
+	if errors.Is(err, store.ErrNotFound) {
+		return t, errEquipmentTypeNotFound
+	}
+	return t, err
+}
+
+// Delete removes an equipment type no gym equipment is of.
+func (s *EquipmentTypeService) Delete(ctx context.Context, id int) error {
+	inUse, err := s.equipmentTypes.InUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errorf(Conflict, "Cannot delete equipment type that is in use")
+	}
+
+	err = s.equipmentTypes.Delete(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errEquipmentTypeNotFound
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+// Package service holds the business rules for the gym tracking data on
+// top of the store repositories, independent of any transport. Rule
+// violations are reported as *Error values whose Kind says what went
+// wrong, leaving it to the caller to map them to status codes or exit
+// codes.
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Kind int
+
+const (
+	// Internal marks errors that are not rule violations, such as a
+	// failing database. It is the Kind of any error that is not an *Error.
+	Internal Kind = iota
+	NotFound
+	Conflict
+	Validation
+	Unauthorized
+	Forbidden
+)
+
+func (k Kind) String() string {
+	switch k {
+	case NotFound:
+		return "not found"
+	case Conflict:
+		return "conflict"
+	case Validation:
+		return "validation"
+	case Unauthorized:
+		return "unauthorized"
+	case Forbidden:
+		return "forbidden"
+	}
+	return "internal"
+}
+
+// Error is a violated rule with a message fit to show the user.
+type Error struct {
+	Kind    Kind
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func errorf(kind Kind, format string, args ...any) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// KindOf returns the Kind of the first *Error in err's chain, or Internal.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return Internal
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var (
+	errExerciseNotFound  = errorf(NotFound, "Exercise not found")
+	errExerciseNameTaken = errorf(Conflict, "Exercise with this name already exists")
+)
+
+type ExerciseService struct {
+	exercises store.ExerciseStore
+}
+
+func NewExerciseService(exercises store.ExerciseStore) *ExerciseService {
+	return &ExerciseService{exercises: exercises}
+}
+
+func (s *ExerciseService) List(ctx context.Context) ([]models.Exercise, error) {
+	return s.exercises.List(ctx)
+}
+
+// Create adds an exercise. Names are unique.
+func (s *ExerciseService) Create(ctx context.Context, name string) (models.Exercise, error) {
+	taken, err := s.exercises.NameTaken(ctx, name, 0)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if taken {
+		return models.Exercise{}, errExerciseNameTaken
+	}
+	return s.exercises.Create(ctx, name)
+}
+
+func (s *ExerciseService) Update(ctx context.Context, id int, name string) (models.Exercise, error) {
+	exists, err := s.exercises.Exists(ctx, id)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if !exists {
+		return models.Exercise{}, errExerciseNotFound
+	}
+
+	taken, err := s.exercises.NameTaken(ctx, name, id)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if taken {
+		return models.Exercise{}, errExerciseNameTaken
+	}
+
+	e, err := s.exercises.Update(ctx, id, name)
+	if errors.Is(err, store.ErrNotFound) {
+		return e, errExerciseNotFound
+	}
+	return e, err
+}
+
+// Delete removes an exercise no workout has recorded.
+func (s *ExerciseService) Delete(ctx context.Context, id int) error {
+	inUse, err := s.exercises.InUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errorf(Conflict, "Cannot delete exercise that is used in workouts")
+	}
+
+	err = s.exercises.Delete(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errExerciseNotFound
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/foods"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var errFoodNotFound = errorf(NotFound, "Food not found")
+
+type FoodService struct {
+	foods       store.FoodStore
+	pantryItems *PantryService
+}
+
+func NewFoodService(foods store.FoodStore, pantryItems *PantryService) *FoodService {
+	return &FoodService{foods: foods, pantryItems: pantryItems}
+}
+
+// Search finds up to limit foods, between 1 and 100, matching every word of
+// text, the last one as a prefix.
+func (s *FoodService) Search(ctx context.Context, text string, limit int) ([]models.Food, error) {
+	query := searchQuery(text)
+	if query == "" {
+		return nil, errorf(Validation, "Search text is required")
+	}
+	if limit < 1 || limit > 100 {
+		return nil, errorf(Validation, "Limit must be between 1 and 100")
+	}
+	return s.foods.Search(ctx, query, limit)
+}
+
+func (s *FoodService) Get(ctx context.Context, id int) (models.Food, error) {
+	f, err := s.foods.Get(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return f, errFoodNotFound
+	}
+	return f, err
+}
+
+// FindByBarcode looks a food up by any of the barcode forms
+// foods.NormalizeBarcode accepts.
+func (s *FoodService) FindByBarcode(ctx context.Context, barcode string) (models.Food, error) {
+	code, err := foods.NormalizeBarcode(barcode)
+	if err != nil {
+		return models.Food{}, errorf(Validation, "%s", err.Error())
+	}
+	f, err := s.foods.FindByBarcode(ctx, code)
+	if errors.Is(err, store.ErrNotFound) {
+		return f, errFoodNotFound
+	}
+	return f, err
+}
+
+// CreatePantryItem adds a pantry item stocked in a unit the food is
+// measured in, with its nutrition per unit copied from the food. The item
+// takes the food's name unless the input names it.
+func (s *FoodService) CreatePantryItem(ctx context.Context, userID int, input models.PantryItemFromFoodInput) (models.PantryItem, error) {
+	unit, ok := pantry.ParseUnit(input.Unit)
+	if !ok || !pantry.IsStockUnit(unit) {
+		return models.PantryItem{}, errorf(Validation, "Unit must be one of g, kg, ml, l or item")
+	}
+
+	f, err := s.foods.Get(ctx, input.FoodID)
+	if errors.Is(err, store.ErrNotFound) {
+		return models.PantryItem{}, errorf(Validation, "Food not found")
+	} else if err != nil {
+		return models.PantryItem{}, err
+	}
+
+	perUnit, err := foods.PerUnit(f, unit)
+	if errors.Is(err, foods.ErrUnitMismatch) || errors.Is(err, foods.ErrNoServingSize) {
+		return models.PantryItem{}, errorf(Validation, "%s", err.Error())
+	} else if err != nil {
+		return models.PantryItem{}, err
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = f.Name
+	}
+
+	// A serving of a food measured by weight doubles as the weight of one
+	// item, so recipes can use the item by count or by weight.
+	var gramsPerItem *float64
+	if unit == "item" && f.BasisUnit == "g" {
+		gramsPerItem = f.ServingSize
+	}
+
+	return s.pantryItems.Create(ctx, userID, models.PantryItemInput{
+		Name:            name,
+		Quantity:        input.Quantity,
+		Unit:            unit,
+		Threshold:       input.Threshold,
+		CaloriesPerUnit: perUnit.Calories,
+		ProteinPerUnit:  perUnit.Protein,
+		CarbsPerUnit:    perUnit.Carbs,
+		FatPerUnit:      perUnit.Fat,
+		FiberPerUnit:    perUnit.Fiber,
+		GramsPerItem:    gramsPerItem,
+	})
+}
+
+// searchQuery turns free text into a tsquery requiring every word, with
+// the last word matched as a prefix. It returns "" when the text has no
+// searchable words.
+func searchQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	words[len(words)-1] += ":*"
+	return strings.Join(words, " & ")
+}
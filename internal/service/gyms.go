@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var errGymNotFound = errorf(NotFound, "Gym not found")
+
+type GymService struct {
+	gyms store.GymStore
+}
+
+func NewGymService(gyms store.GymStore) *GymService {
+	return &GymService{gyms: gyms}
+}
+
+func (s *GymService) List(ctx context.Context) ([]models.Gym, error) {
+	return s.gyms.List(ctx)
+}
+
+// ListByUser returns the user's gyms, or a NotFound error when they have
+// none.
+func (s *GymService) ListByUser(ctx context.Context, userID int) ([]models.Gym, error) {
+	gyms, err := s.gyms.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(gyms) == 0 {
+		return nil, errorf(NotFound, "No gyms found for this user")
+	}
+	return gyms, nil
+}
+
+func (s *GymService) Get(ctx context.Context, id int) (models.Gym, error) {
+	gym, err := s.gyms.Get(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return gym, errGymNotFound
+	}
+	return gym, err
+}
+
+func (s *GymService) Create(ctx context.Context, gym models.Gym) (models.Gym, error) {
+	if gym.Name == "" {
+		return gym, errorf(Validation, "Gym name is required")
+	}
+	return s.gyms.Create(ctx, gym)
+}
+
+func (s *GymService) Update(ctx context.Context, id int, gym models.Gym) (models.Gym, error) {
+	gym, err := s.gyms.Update(ctx, id, gym)
+	if errors.Is(err, store.ErrNotFound) {
+		return gym, errGymNotFound
+	}
+	return gym, err
+}
+
+func (s *GymService) Delete(ctx context.Context, id int) error {
+	err := s.gyms.Delete(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errGymNotFound
+	}
+	return err
+}
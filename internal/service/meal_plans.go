@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var errMealPlanEntryNotFound = errorf(NotFound, "Meal plan entry not found")
+
+type MealPlanService struct {
+	plans   store.MealPlanStore
+	recipes store.RecipeStore
+}
+
+func NewMealPlanService(plans store.MealPlanStore, recipes store.RecipeStore) *MealPlanService {
+	return &MealPlanService{plans: plans, recipes: recipes}
+}
+
+// List returns the entries planned from from to to (YYYY-MM-DD).
+func (s *MealPlanService) List(ctx context.Context, userID int, from, to string) ([]models.MealPlanEntry, error) {
+	return s.plans.List(ctx, userID, from, to)
+}
+
+func (s *MealPlanService) Get(ctx context.Context, userID, id int) (models.MealPlanEntry, error) {
+	e, err := s.plans.Get(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return e, errMealPlanEntryNotFound
+	}
+	return e, err
+}
+
+// Create plans a recipe or a list of ingredients for a day. A recipe
+// without servings is planned whole.
+func (s *MealPlanService) Create(ctx context.Context, userID int, input models.MealPlanEntryInput) (models.MealPlanEntry, error) {
+	var err error
+	if input.Servings, err = s.servings(ctx, userID, input); err != nil {
+		return models.MealPlanEntry{}, err
+	}
+
+	e, err := s.plans.Create(ctx, userID, input)
+	return e, ingredientError(err)
+}
+
+func (s *MealPlanService) Update(ctx context.Context, userID, id int, input models.MealPlanEntryInput) (models.MealPlanEntry, error) {
+	var err error
+	if input.Servings, err = s.servings(ctx, userID, input); err != nil {
+		return models.MealPlanEntry{}, err
+	}
+
+	e, err := s.plans.Update(ctx, userID, id, input)
+	if errors.Is(err, store.ErrNotFound) {
+		return e, errMealPlanEntryNotFound
+	}
+	return e, ingredientError(err)
+}
+
+func (s *MealPlanService) Delete(ctx context.Context, userID, id int) error {
+	err := s.plans.Delete(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errMealPlanEntryNotFound
+	}
+	return err
+}
+
+// Requirements compares what the plan uses over the window with current
+// stock.
+func (s *MealPlanService) Requirements(ctx context.Context, userID int, plan pantry.PlanWindow) ([]models.MealPlanRequirement, error) {
+	return s.plans.Requirements(ctx, userID, plan)
+}
+
+// servings checks that a planned recipe belongs to the user and returns
+// the servings to store, defaulting to the whole recipe. Entries without a
+// recipe have no servings.
+func (s *MealPlanService) servings(ctx context.Context, userID int, input models.MealPlanEntryInput) (*float64, error) {
+	if input.RecipeID == nil {
+		return nil, nil
+	}
+
+	r, err := s.recipes.Get(ctx, userID, *input.RecipeID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, errorf(Validation, "Entry refers to an unknown recipe")
+	} else if err != nil {
+		return nil, err
+	}
+
+	servings := r.Servings
+	if input.Servings != nil {
+		servings = *input.Servings
+	}
+	return &servings, nil
+}
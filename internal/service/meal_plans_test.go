@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+)
+
+func TestMealPlanServiceServings(t *testing.T) {
+	ctx := context.Background()
+	s := newServices()
+	rice := s.pantryItem(t, "Rice", 500, "g")
+	r, err := s.recipes.Create(ctx, userID, models.RecipeInput{
+		Name:        "Rice bowl",
+		Servings:    4,
+		Ingredients: []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 400}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	two := 2.0
+
+	tests := []struct {
+		name     string
+		servings *float64
+		want     float64
+	}{
+		{"whole recipe", nil, 4},
+		{"given servings", &two, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := s.plans.Create(ctx, userID, models.MealPlanEntryInput{Date: "2025-03-17", RecipeID: &r.ID, Servings: tt.servings})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Servings == nil || *e.Servings != tt.want {
+				t.Errorf("servings = %v, want %v", e.Servings, tt.want)
+			}
+		})
+	}
+}
+
+func TestMealPlanServiceErrors(t *testing.T) {
+	ctx := context.Background()
+	s := newServices()
+	rice := s.pantryItem(t, "Rice", 500, "g")
+	unknown := 999
+	ingredients := []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 100}}
+
+	_, err := s.plans.Create(ctx, userID, models.MealPlanEntryInput{Date: "2025-03-17", RecipeID: &unknown})
+	wantKind(t, err, service.Validation)
+
+	_, err = s.plans.Create(ctx, userID, models.MealPlanEntryInput{
+		Date:        "2025-03-17",
+		Ingredients: []models.RecipeIngredientInput{{PantryItemID: unknown, Quantity: 100}},
+	})
+	wantKind(t, err, service.Validation)
+
+	_, err = s.plans.Update(ctx, userID, 999, models.MealPlanEntryInput{Date: "2025-03-17", Ingredients: ingredients})
+	wantKind(t, err, service.NotFound)
+	_, err = s.plans.Get(ctx, userID, 999)
+	wantKind(t, err, service.NotFound)
+	wantKind(t, s.plans.Delete(ctx, userID, 999), service.NotFound)
+}
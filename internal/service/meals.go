@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var errMealNotFound = errorf(NotFound, "Meal not found")
+
+type MealService struct {
+	meals store.MealStore
+}
+
+func NewMealService(meals store.MealStore) *MealService {
+	return &MealService{meals: meals}
+}
+
+// List returns the meals eaten in [from, until), oldest first.
+func (s *MealService) List(ctx context.Context, userID int, from, until time.Time) ([]models.Meal, error) {
+	return s.meals.List(ctx, userID, from, until)
+}
+
+func (s *MealService) Get(ctx context.Context, userID, id int) (models.Meal, error) {
+	m, err := s.meals.Get(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return m, errMealNotFound
+	}
+	return m, err
+}
+
+// Create logs a meal and deducts its ingredients from the pantry.
+func (s *MealService) Create(ctx context.Context, userID int, eatenAt time.Time, ingredients []models.MealIngredientInput) (models.Meal, error) {
+	m, err := s.meals.Create(ctx, userID, eatenAt, ingredients)
+	return m, ingredientError(err)
+}
+
+// Update replaces the meal's ingredients, returning the stock the old ones
+// used. A nil eatenAt keeps the original time.
+func (s *MealService) Update(ctx context.Context, userID, id int, eatenAt *time.Time, ingredients []models.MealIngredientInput) (models.Meal, error) {
+	m, err := s.meals.Update(ctx, userID, id, eatenAt, ingredients)
+	if errors.Is(err, store.ErrNotFound) {
+		return m, errMealNotFound
+	}
+	return m, ingredientError(err)
+}
+
+// Delete removes the meal and returns its stock to the pantry.
+func (s *MealService) Delete(ctx context.Context, userID, id int) error {
+	err := s.meals.Delete(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errMealNotFound
+	}
+	return err
+}
+
+// ingredientError turns the errors the stores give for ingredients they
+// cannot record, an unknown pantry item or a unit that does not convert,
+// into Validation errors. A *pantry.InsufficientStockError is returned
+// as is so that the caller can report the shortfalls.
+func ingredientError(err error) error {
+	var unitErr *pantry.UnitError
+	switch {
+	case errors.Is(err, pantry.ErrItemNotFound):
+		return errorf(Validation, "Ingredient refers to an unknown pantry item")
+	case errors.As(err, &unitErr):
+		return errorf(Validation, "%s", unitErr.Error())
+	}
+	return err
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/analytics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+type NutritionService struct {
+	users        store.UserStore
+	profiles     store.ProfileStore
+	measurements store.BodyMeasurementStore
+	nutrition    store.NutritionStore
+}
+
+func NewNutritionService(users store.UserStore, profiles store.ProfileStore, measurements store.BodyMeasurementStore, nutrition store.NutritionStore) *NutritionService {
+	return &NutritionService{users: users, profiles: profiles, measurements: measurements, nutrition: nutrition}
+}
+
+// Summary totals the meals eaten on the days from from to to in loc per day
+// or per Monday-to-Sunday week. Days without meals count towards neither
+// the days logged nor the daily averages.
+func (s *NutritionService) Summary(ctx context.Context, userID int, loc *time.Location, period string, from, to time.Time) (models.NutritionSummary, error) {
+	if period != "day" && period != "week" {
+		return models.NutritionSummary{}, errorf(Validation, "Period must be day or week")
+	}
+
+	days, err := s.nutrition.Days(ctx, userID, loc.String(), from.Format(time.DateOnly), to.Format(time.DateOnly))
+	if err != nil {
+		return models.NutritionSummary{}, err
+	}
+
+	summary := models.NutritionSummary{
+		Timezone: loc.String(),
+		Period:   period,
+		From:     from.Format(time.DateOnly),
+		To:       to.Format(time.DateOnly),
+		Periods:  []models.NutritionPeriod{},
+	}
+
+	var current *models.NutritionPeriod
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		date := d.Format(time.DateOnly)
+		if current == nil || period == "day" || d.Weekday() == time.Monday {
+			summary.Periods = append(summary.Periods, models.NutritionPeriod{Start: date})
+			current = &summary.Periods[len(summary.Periods)-1]
+		}
+		current.End = date
+
+		day, logged := days[date]
+		if !logged {
+			continue
+		}
+		current.Meals += day.Meals
+		current.DaysLogged++
+		addMacros(&current.Totals, day.Macros)
+	}
+
+	for i := range summary.Periods {
+		p := &summary.Periods[i]
+		if p.DaysLogged > 0 {
+			n := float64(p.DaysLogged)
+			p.DailyAverage = models.Macros{
+				Calories: p.Totals.Calories / n,
+				Protein:  p.Totals.Protein / n,
+				Carbs:    p.Totals.Carbs / n,
+				Fat:      p.Totals.Fat / n,
+				Fiber:    p.Totals.Fiber / n,
+			}
+		}
+	}
+	return summary, nil
+}
+
+// Today compares what the user has eaten so far today in loc with their
+// targets.
+func (s *NutritionService) Today(ctx context.Context, userID int, loc *time.Location) (models.NutritionToday, error) {
+	today := time.Now().In(loc).Format(time.DateOnly)
+	days, err := s.nutrition.Days(ctx, userID, loc.String(), today, today)
+	if err != nil {
+		return models.NutritionToday{}, err
+	}
+	targets, err := s.nutrition.Targets(ctx, userID)
+	if err != nil {
+		return models.NutritionToday{}, err
+	}
+
+	day := days[today]
+	return models.NutritionToday{
+		Date:      today,
+		Timezone:  loc.String(),
+		Meals:     day.Meals,
+		Consumed:  day.Macros,
+		Targets:   targets.MacroTargets,
+		Remaining: remainingMacros(targets.MacroTargets, day.Macros),
+	}, nil
+}
+
+// Targets returns the user's targets, all unset when none have been saved.
+func (s *NutritionService) Targets(ctx context.Context, userID int) (models.NutritionTargets, error) {
+	return s.nutrition.Targets(ctx, userID)
+}
+
+// SaveTargets creates or replaces the user's targets, clearing those the
+// input omits.
+func (s *NutritionService) SaveTargets(ctx context.Context, userID int, input models.NutritionTargetsInput) (models.NutritionTargets, error) {
+	exists, err := s.users.Exists(ctx, userID)
+	if err != nil {
+		return models.NutritionTargets{}, err
+	}
+	if !exists {
+		return models.NutritionTargets{}, errUserNotFound
+	}
+	return s.nutrition.SaveTargets(ctx, userID, input)
+}
+
+// TargetOverrides are calculator inputs given by the caller in place of
+// those taken from the user's data or the defaults. Nil fields are not
+// overridden.
+type TargetOverrides struct {
+	BodyweightKg        *float64
+	HeightCm            *float64
+	Age                 *float64
+	Sex                 *string
+	ActivityFactor      *float64
+	LookbackDays        *float64
+	SetsPerDay          *float64
+	CardioMinutesPerDay *float64
+	KcalPerSetPerKg     *float64
+	CardioMET           *float64
+	ProteinPerKg        *float64
+	AdjustmentKcal      *float64
+}
+
+// CalculateTargets recommends daily calories and protein. Bodyweight comes
+// from the smoothed body log, then the profile; height, age and sex from
+// the profile; training from the workouts logged over the lookback window;
+// and the remaining factors from their defaults. Every input records where
+// it came from.
+func (s *NutritionService) CalculateTargets(ctx context.Context, userID int, o TargetOverrides) (models.TargetCalculation, error) {
+	profile, err := s.profiles.Get(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return models.TargetCalculation{}, errUserNotFound
+	} else if err != nil {
+		return models.TargetCalculation{}, err
+	}
+
+	var in models.TargetCalculatorInputs
+
+	// Prefer the smoothed trend from recent weigh-ins over the single value
+	// stored on the profile.
+	var weightFallback *models.CalculatorFactor
+	now := time.Now()
+	entries, err := s.measurements.List(ctx, userID, now.AddDate(0, 0, -60), now)
+	if err != nil {
+		return models.TargetCalculation{}, err
+	}
+	var samples []analytics.Sample
+	for _, m := range entries {
+		if m.BodyweightKg != nil {
+			samples = append(samples, analytics.Sample{At: m.MeasuredAt, Value: *m.BodyweightKg})
+		}
+	}
+	if points := analytics.EWMA(samples, analytics.DefaultAlpha); len(points) > 0 {
+		weightFallback = &models.CalculatorFactor{Value: points[len(points)-1].Trend, Source: "body_log"}
+	} else if profile.BodyweightKg != nil {
+		weightFallback = &models.CalculatorFactor{Value: *profile.BodyweightKg, Source: "profile"}
+	}
+
+	var heightFallback, ageFallback *models.CalculatorFactor
+	if profile.HeightCm != nil {
+		heightFallback = &models.CalculatorFactor{Value: *profile.HeightCm, Source: "profile"}
+	}
+	if profile.BirthYear != nil {
+		ageFallback = &models.CalculatorFactor{Value: float64(now.Year() - *profile.BirthYear), Source: "profile"}
+	}
+
+	factors := []struct {
+		name     string
+		override *float64
+		min, max float64
+		fallback *models.CalculatorFactor
+		out      *models.CalculatorFactor
+	}{
+		{"bodyweight_kg", o.BodyweightKg, 20, 700, weightFallback, &in.BodyweightKg},
+		{"height_cm", o.HeightCm, 50, 300, heightFallback, &in.HeightCm},
+		{"age", o.Age, 10, 120, ageFallback, &in.Age},
+		{"activity_factor", o.ActivityFactor, 1, 2.5, defaultFactor(analytics.DefaultActivityFactor), &in.ActivityFactor},
+		{"lookback_days", o.LookbackDays, 7, 365, defaultFactor(28), &in.LookbackDays},
+		{"kcal_per_set_per_kg", o.KcalPerSetPerKg, 0, 1, defaultFactor(analytics.DefaultKcalPerSetPerKg), &in.KcalPerSetPerKg},
+		{"cardio_met", o.CardioMET, 1, 20, defaultFactor(analytics.DefaultCardioMET), &in.CardioMET},
+		{"protein_per_kg", o.ProteinPerKg, 0.5, 4, defaultFactor(analytics.DefaultProteinPerKg), &in.ProteinPerKg},
+		{"adjustment_kcal", o.AdjustmentKcal, -1500, 1500, defaultFactor(0), &in.AdjustmentKcal},
+	}
+	for _, f := range factors {
+		if *f.out, err = factor(f.name, f.override, f.min, f.max, f.fallback); err != nil {
+			return models.TargetCalculation{}, err
+		}
+	}
+	in.Age.Value = math.Round(in.Age.Value)
+	in.LookbackDays.Value = math.Round(in.LookbackDays.Value)
+
+	in.Sex, in.SexSource = "unspecified", "default"
+	if profile.Sex != nil {
+		in.Sex, in.SexSource = *profile.Sex, "profile"
+	}
+	if o.Sex != nil {
+		if *o.Sex != "male" && *o.Sex != "female" && *o.Sex != "other" {
+			return models.TargetCalculation{}, errorf(Validation, "sex must be male, female or other")
+		}
+		in.Sex, in.SexSource = *o.Sex, "override"
+	}
+
+	sets, cardioMinutes, err := s.nutrition.Training(ctx, userID, now.AddDate(0, 0, -int(in.LookbackDays.Value)))
+	if err != nil {
+		return models.TargetCalculation{}, err
+	}
+	setsFallback := models.CalculatorFactor{Value: sets / in.LookbackDays.Value, Source: "workouts"}
+	cardioFallback := models.CalculatorFactor{Value: cardioMinutes / in.LookbackDays.Value, Source: "workouts"}
+	if in.SetsPerDay, err = factor("sets_per_day", o.SetsPerDay, 0, 200, &setsFallback); err != nil {
+		return models.TargetCalculation{}, err
+	}
+	if in.CardioMinutesPerDay, err = factor("cardio_minutes_per_day", o.CardioMinutesPerDay, 0, 600, &cardioFallback); err != nil {
+		return models.TargetCalculation{}, err
+	}
+
+	estimate := analytics.EstimateEnergy(analytics.EnergyInputs{
+		BodyweightKg:        in.BodyweightKg.Value,
+		HeightCm:            in.HeightCm.Value,
+		Age:                 int(in.Age.Value),
+		Sex:                 in.Sex,
+		ActivityFactor:      in.ActivityFactor.Value,
+		SetsPerDay:          in.SetsPerDay.Value,
+		CardioMinutesPerDay: in.CardioMinutesPerDay.Value,
+		KcalPerSetPerKg:     in.KcalPerSetPerKg.Value,
+		CardioMET:           in.CardioMET.Value,
+		ProteinPerKg:        in.ProteinPerKg.Value,
+		AdjustmentKcal:      in.AdjustmentKcal.Value,
+	})
+
+	return models.TargetCalculation{
+		Inputs:              in,
+		BMR:                 math.Round(estimate.BMR),
+		BaselineCalories:    math.Round(estimate.Baseline),
+		TrainingCalories:    math.Round(estimate.Training),
+		CardioCalories:      math.Round(estimate.Cardio),
+		MaintenanceCalories: math.Round(estimate.Maintenance),
+		TargetCalories:      math.Round(estimate.Target),
+		ProteinGrams:        math.Round(estimate.ProteinG),
+		Reasoning:           estimate.Reasoning,
+	}, nil
+}
+
+// factor returns the override when there is one and fallback otherwise. It
+// fails when the override is outside [min, max] or when there is neither.
+func factor(name string, override *float64, min, max float64, fallback *models.CalculatorFactor) (models.CalculatorFactor, error) {
+	if override == nil {
+		if fallback == nil {
+			return models.CalculatorFactor{}, errorf(Validation,
+				"%s is unknown: add it to the user profile or pass it as a query parameter", name)
+		}
+		return *fallback, nil
+	}
+	if *override < min || *override > max {
+		return models.CalculatorFactor{}, errorf(Validation, "%s must be a number between %g and %g", name, min, max)
+	}
+	return models.CalculatorFactor{Value: *override, Source: "override"}, nil
+}
+
+func defaultFactor(value float64) *models.CalculatorFactor {
+	return &models.CalculatorFactor{Value: value, Source: "default"}
+}
+
+func addMacros(total *models.Macros, m models.Macros) {
+	total.Calories += m.Calories
+	total.Protein += m.Protein
+	total.Carbs += m.Carbs
+	total.Fat += m.Fat
+	total.Fiber += m.Fiber
+}
+
+func remainingMacros(targets models.MacroTargets, consumed models.Macros) models.MacroTargets {
+	remaining := func(target *float64, used float64) *float64 {
+		if target == nil {
+			return nil
+		}
+		r := *target - used
+		return &r
+	}
+	return models.MacroTargets{
+		Calories: remaining(targets.Calories, consumed.Calories),
+		Protein:  remaining(targets.Protein, consumed.Protein),
+		Carbs:    remaining(targets.Carbs, consumed.Carbs),
+		Fat:      remaining(targets.Fat, consumed.Fat),
+		Fiber:    remaining(targets.Fiber, consumed.Fiber),
+	}
+}
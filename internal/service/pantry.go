@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var (
+	errPantryItemNotFound  = errorf(NotFound, "Pantry item not found")
+	errPantryItemNameTaken = errorf(Conflict, "Pantry item with this name already exists")
+)
+
+type PantryService struct {
+	items store.PantryStore
+}
+
+func NewPantryService(items store.PantryStore) *PantryService {
+	return &PantryService{items: items}
+}
+
+func (s *PantryService) List(ctx context.Context, userID int, lowStock bool) ([]models.PantryItem, error) {
+	return s.items.List(ctx, userID, lowStock)
+}
+
+func (s *PantryService) Get(ctx context.Context, userID, id int) (models.PantryItem, error) {
+	p, err := s.items.Get(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return p, errPantryItemNotFound
+	}
+	return p, err
+}
+
+// Create adds an item to the user's pantry. Names are unique per user.
+func (s *PantryService) Create(ctx context.Context, userID int, input models.PantryItemInput) (models.PantryItem, error) {
+	taken, err := s.items.NameTaken(ctx, userID, input.Name, 0)
+	if err != nil {
+		return models.PantryItem{}, err
+	}
+	if taken {
+		return models.PantryItem{}, errPantryItemNameTaken
+	}
+	return s.items.Create(ctx, userID, input)
+}
+
+// Update replaces the item. Its unit may only change to one that does not
+// convert while nothing records amounts of the item.
+func (s *PantryService) Update(ctx context.Context, userID, id int, input models.PantryItemInput) (models.PantryItem, error) {
+	taken, err := s.items.NameTaken(ctx, userID, input.Name, id)
+	if err != nil {
+		return models.PantryItem{}, err
+	}
+	if taken {
+		return models.PantryItem{}, errPantryItemNameTaken
+	}
+
+	p, err := s.items.Update(ctx, userID, id, input)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return p, errPantryItemNotFound
+	case errors.Is(err, pantry.ErrUnitInUse):
+		return p, errorf(Conflict, "Unit cannot change to %s while the item is used in meals, recipes, meal plans or the shopping list", input.Unit)
+	}
+	return p, err
+}
+
+func (s *PantryService) Restock(ctx context.Context, userID, id int, input models.PantryRestockInput) (models.PantryItem, error) {
+	p, err := s.items.Restock(ctx, userID, id, input)
+	if errors.Is(err, store.ErrNotFound) {
+		return p, errPantryItemNotFound
+	}
+	return p, err
+}
+
+// Delete removes an item no logged meal, recipe or meal plan uses. Other
+// users' items are reported as not found whether or not they are in use.
+func (s *PantryService) Delete(ctx context.Context, userID, id int) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	inUse, err := s.items.InUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errorf(Conflict, "Cannot delete pantry item that is used in logged meals, recipes or meal plans")
+	}
+
+	err = s.items.Delete(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errPantryItemNotFound
+	}
+	return err
+}
+
+// Expiring returns the batches expiring within days of today (YYYY-MM-DD).
+func (s *PantryService) Expiring(ctx context.Context, userID int, today string, days int) ([]models.ExpiringBatch, error) {
+	return s.items.Expiring(ctx, userID, today, days)
+}
+
+func (s *PantryService) Batches(ctx context.Context, userID, id int) (models.PantryItemBatches, error) {
+	b, err := s.items.Batches(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return b, errPantryItemNotFound
+	}
+	return b, err
+}
+
+func (s *PantryService) DiscardBatch(ctx context.Context, userID, id, batchID int) error {
+	err := s.items.DiscardBatch(ctx, userID, id, batchID)
+	if errors.Is(err, store.ErrNotFound) {
+		return errorf(NotFound, "Batch not found")
+	}
+	return err
+}
+
+func (s *PantryService) Forecast(ctx context.Context, userID int, outlook pantry.Outlook, leadDays float64) ([]models.PantryForecast, error) {
+	return s.items.Forecast(ctx, userID, outlook, leadDays)
+}
+
+// TuneThresholds applies the forecast's suggested thresholds and returns
+// the items that changed.
+func (s *PantryService) TuneThresholds(ctx context.Context, userID int, outlook pantry.Outlook, leadDays float64) ([]models.PantryItem, error) {
+	return s.items.TuneThresholds(ctx, userID, outlook, leadDays)
+}
@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+)
+
+func TestPantryServiceNames(t *testing.T) {
+	ctx := context.Background()
+	s := newServices()
+	oats := s.pantryItem(t, "Oats", 750, "g")
+	milk := s.pantryItem(t, "Milk", 1, "l")
+
+	_, err := s.pantry.Create(ctx, userID, models.PantryItemInput{Name: "Oats", Unit: "g"})
+	wantKind(t, err, service.Conflict)
+
+	// Names are unique per user.
+	if _, err := s.pantry.Create(ctx, userID+1, models.PantryItemInput{Name: "Oats", Unit: "g"}); err != nil {
+		t.Errorf("another user's oats: %v", err)
+	}
+
+	_, err = s.pantry.Update(ctx, userID, milk.ID, models.PantryItemInput{Name: "Oats", Unit: "l"})
+	wantKind(t, err, service.Conflict)
+
+	if _, err := s.pantry.Update(ctx, userID, oats.ID, models.PantryItemInput{Name: "Oats", Quantity: 500, Unit: "g"}); err != nil {
+		t.Errorf("keeping the name: %v", err)
+	}
+
+	_, err = s.pantry.Update(ctx, userID, 999, models.PantryItemInput{Name: "Rice", Unit: "g"})
+	wantKind(t, err, service.NotFound)
+}
+
+func TestPantryServiceNotFound(t *testing.T) {
+	ctx := context.Background()
+	s := newServices()
+	oats := s.pantryItem(t, "Oats", 750, "g")
+	// A meal puts the oats in use, which must not show through to another
+	// user deleting them.
+	_, err := s.meals.Create(ctx, userID, testNow, []models.MealIngredientInput{{PantryItemID: oats.ID, QuantityUsed: 80}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"get", func() error { _, err := s.pantry.Get(ctx, userID, 999); return err }},
+		{"other user", func() error { _, err := s.pantry.Get(ctx, userID+1, oats.ID); return err }},
+		{"delete other user's item in use", func() error { return s.pantry.Delete(ctx, userID+1, oats.ID) }},
+		{"restock", func() error {
+			_, err := s.pantry.Restock(ctx, userID, 999, models.PantryRestockInput{Quantity: 1})
+			return err
+		}},
+		{"delete", func() error { return s.pantry.Delete(ctx, userID, 999) }},
+		{"batches", func() error { _, err := s.pantry.Batches(ctx, userID, 999); return err }},
+		{"discard batch", func() error { return s.pantry.DiscardBatch(ctx, userID, oats.ID, 999) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantKind(t, tt.call(), service.NotFound)
+		})
+	}
+}
+
+func TestPantryServiceInUse(t *testing.T) {
+	ctx := context.Background()
+	s := newServices()
+	eggs := s.pantryItem(t, "Eggs", 6, "item")
+	_, err := s.meals.Create(ctx, userID, testNow, []models.MealIngredientInput{{PantryItemID: eggs.ID, QuantityUsed: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantKind(t, s.pantry.Delete(ctx, userID, eggs.ID), service.Conflict)
+
+	// Eggs counted in items cannot be weighed while a meal records them.
+	_, err = s.pantry.Update(ctx, userID, eggs.ID, models.PantryItemInput{Name: "Eggs", Quantity: 4, Unit: "g"})
+	wantKind(t, err, service.Conflict)
+}
+
+// TestPantryServiceBatches checks that meals draw dated stock first and
+// put it back when deleted.
+func TestPantryServiceBatches(t *testing.T) {
+	ctx := context.Background()
+	s := newServices()
+	oats := s.pantryItem(t, "Oats", 500, "g")
+	expires := "2025-04-01"
+	if _, err := s.pantry.Restock(ctx, userID, oats.ID, models.PantryRestockInput{Quantity: 300, ExpiresOn: &expires}); err != nil {
+		t.Fatal(err)
+	}
+
+	meal, err := s.meals.Create(ctx, userID, testNow, []models.MealIngredientInput{{PantryItemID: oats.ID, QuantityUsed: 200}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantBatches(t, s, oats.ID, 100, 500)
+
+	if err := s.meals.Delete(ctx, userID, meal.ID); err != nil {
+		t.Fatal(err)
+	}
+	wantBatches(t, s, oats.ID, 300, 500)
+}
+
+// wantBatches checks how much of an item's stock is dated and undated.
+func wantBatches(t *testing.T, s services, id int, dated, undated float64) {
+	t.Helper()
+	b, err := s.pantry.Batches(context.Background(), userID, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got float64
+	for _, batch := range b.Batches {
+		got += batch.Quantity
+	}
+	if got != dated || b.Undated != undated {
+		t.Errorf("batches hold %v with %v undated, want %v with %v undated", got, b.Undated, dated, undated)
+	}
+}
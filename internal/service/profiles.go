@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+type ProfileService struct {
+	users    store.UserStore
+	profiles store.ProfileStore
+}
+
+func NewProfileService(users store.UserStore, profiles store.ProfileStore) *ProfileService {
+	return &ProfileService{users: users, profiles: profiles}
+}
+
+// Get returns the user's profile, with the defaults filled in when none has
+// been saved.
+func (s *ProfileService) Get(ctx context.Context, userID int) (models.UserProfile, error) {
+	p, err := s.profiles.Get(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return p, errUserNotFound
+	}
+	return p, err
+}
+
+// Save creates or replaces the user's profile. Omitted units default to
+// metric and an omitted time zone to UTC.
+func (s *ProfileService) Save(ctx context.Context, userID int, input models.UserProfileInput) (models.UserProfile, error) {
+	if input.PreferredUnits == "" {
+		input.PreferredUnits = "metric"
+	}
+	if input.Timezone == "" {
+		input.Timezone = "UTC"
+	}
+	// "Local" would follow the server's zone rather than the user's.
+	if _, err := time.LoadLocation(input.Timezone); err != nil || input.Timezone == "Local" {
+		return models.UserProfile{}, errorf(Validation, "Unknown timezone")
+	}
+	if input.BirthYear != nil && *input.BirthYear > time.Now().Year() {
+		return models.UserProfile{}, errorf(Validation, "Birth year cannot be in the future")
+	}
+
+	exists, err := s.users.Exists(ctx, userID)
+	if err != nil {
+		return models.UserProfile{}, err
+	}
+	if !exists {
+		return models.UserProfile{}, errUserNotFound
+	}
+	return s.profiles.Save(ctx, userID, input)
+}
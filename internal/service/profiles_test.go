@@ -0,0 +1,49 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/memory"
+)
+
+func TestProfileServiceSave(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	users := memory.NewUserStore(db)
+	profiles := service.NewProfileService(users, memory.NewProfileStore(db))
+	u, err := users.Create(ctx, "lifter@example.com", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nextYear := time.Now().Year() + 1
+	tests := []struct {
+		name   string
+		userID int
+		input  models.UserProfileInput
+		kind   service.Kind
+	}{
+		{"unknown timezone", u.ID, models.UserProfileInput{Timezone: "Mars/Olympus_Mons"}, service.Validation},
+		{"server timezone", u.ID, models.UserProfileInput{Timezone: "Local"}, service.Validation},
+		{"future birth year", u.ID, models.UserProfileInput{BirthYear: &nextYear}, service.Validation},
+		{"unknown user", 999, models.UserProfileInput{}, service.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := profiles.Save(ctx, tt.userID, tt.input)
+			wantKind(t, err, tt.kind)
+		})
+	}
+
+	p, err := profiles.Save(ctx, u.ID, models.UserProfileInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PreferredUnits != "metric" || p.Timezone != "UTC" {
+		t.Errorf("defaults = %q, %q, want metric, UTC", p.PreferredUnits, p.Timezone)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var (
+	errRecipeNotFound  = errorf(NotFound, "Recipe not found")
+	errRecipeNameTaken = errorf(Conflict, "Recipe with this name already exists")
+)
+
+type RecipeService struct {
+	recipes store.RecipeStore
+}
+
+func NewRecipeService(recipes store.RecipeStore) *RecipeService {
+	return &RecipeService{recipes: recipes}
+}
+
+func (s *RecipeService) List(ctx context.Context, userID int) ([]models.Recipe, error) {
+	return s.recipes.List(ctx, userID)
+}
+
+func (s *RecipeService) Get(ctx context.Context, userID, id int) (models.Recipe, error) {
+	r, err := s.recipes.Get(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return r, errRecipeNotFound
+	}
+	return r, err
+}
+
+// Create adds a recipe. Names are unique per user.
+func (s *RecipeService) Create(ctx context.Context, userID int, input models.RecipeInput) (models.Recipe, error) {
+	taken, err := s.recipes.NameTaken(ctx, userID, input.Name, 0)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+	if taken {
+		return models.Recipe{}, errRecipeNameTaken
+	}
+
+	r, err := s.recipes.Create(ctx, userID, input)
+	return r, ingredientError(err)
+}
+
+func (s *RecipeService) Update(ctx context.Context, userID, id int, input models.RecipeInput) (models.Recipe, error) {
+	taken, err := s.recipes.NameTaken(ctx, userID, input.Name, id)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+	if taken {
+		return models.Recipe{}, errRecipeNameTaken
+	}
+
+	r, err := s.recipes.Update(ctx, userID, id, input)
+	if errors.Is(err, store.ErrNotFound) {
+		return r, errRecipeNotFound
+	}
+	return r, ingredientError(err)
+}
+
+// Delete removes a recipe along with the meal plan entries using it.
+func (s *RecipeService) Delete(ctx context.Context, userID, id int) error {
+	err := s.recipes.Delete(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errRecipeNotFound
+	}
+	return err
+}
+
+// Log logs servings of the recipe as a meal, deducting the scaled
+// ingredients from the pantry.
+func (s *RecipeService) Log(ctx context.Context, userID, id int, servings float64, eatenAt time.Time) (models.Meal, error) {
+	m, err := s.recipes.Log(ctx, userID, id, servings, eatenAt)
+	if errors.Is(err, store.ErrNotFound) {
+		return m, errRecipeNotFound
+	}
+	return m, ingredientError(err)
+}
@@ -0,0 +1,95 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+)
+
+func TestRecipeServiceNames(t *testing.T) {
+	ctx := context.Background()
+	s := newServices()
+	rice := s.pantryItem(t, "Rice", 1000, "g")
+	input := func(name string) models.RecipeInput {
+		return models.RecipeInput{
+			Name:        name,
+			Servings:    2,
+			Ingredients: []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 300}},
+		}
+	}
+
+	bowl, err := s.recipes.Create(ctx, userID, input("Rice bowl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	curry, err := s.recipes.Create(ctx, userID, input("Curry"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.recipes.Create(ctx, userID, input("Rice bowl"))
+	wantKind(t, err, service.Conflict)
+	_, err = s.recipes.Update(ctx, userID, curry.ID, input("Rice bowl"))
+	wantKind(t, err, service.Conflict)
+	if _, err := s.recipes.Update(ctx, userID, bowl.ID, input("Rice bowl")); err != nil {
+		t.Errorf("keeping the name: %v", err)
+	}
+	_, err = s.recipes.Update(ctx, userID, 999, input("Stew"))
+	wantKind(t, err, service.NotFound)
+}
+
+func TestRecipeServiceIngredients(t *testing.T) {
+	ctx := context.Background()
+	s := newServices()
+	rice := s.pantryItem(t, "Rice", 1000, "g")
+
+	tests := []struct {
+		name       string
+		ingredient models.RecipeIngredientInput
+	}{
+		{"unknown pantry item", models.RecipeIngredientInput{PantryItemID: 999, Quantity: 1}},
+		{"unit that does not convert", models.RecipeIngredientInput{PantryItemID: rice.ID, Quantity: 1, Unit: "ml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.recipes.Create(ctx, userID, models.RecipeInput{
+				Name:        tt.name,
+				Servings:    1,
+				Ingredients: []models.RecipeIngredientInput{tt.ingredient},
+			})
+			wantKind(t, err, service.Validation)
+		})
+	}
+}
+
+func TestRecipeServiceLog(t *testing.T) {
+	ctx := context.Background()
+	s := newServices()
+	rice := s.pantryItem(t, "Rice", 500, "g")
+	r, err := s.recipes.Create(ctx, userID, models.RecipeInput{
+		Name:        "Rice bowl",
+		Servings:    4,
+		Ingredients: []models.RecipeIngredientInput{{PantryItemID: rice.ID, Quantity: 400}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.recipes.Log(ctx, userID, r.ID, 2, testNow); err != nil {
+		t.Fatal(err)
+	}
+
+	// The shortfall is passed on with the items that are short.
+	_, err = s.recipes.Log(ctx, userID, r.ID, 4, testNow)
+	var stockErr *pantry.InsufficientStockError
+	if !errors.As(err, &stockErr) || len(stockErr.Shortfalls) != 1 || stockErr.Shortfalls[0].PantryItemID != rice.ID {
+		t.Errorf("err = %v, want a shortfall of rice", err)
+	}
+
+	_, err = s.recipes.Log(ctx, userID, 999, 1, testNow)
+	wantKind(t, err, service.NotFound)
+}
@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/memory"
+)
+
+const userID = 1
+
+var testNow = time.Date(2025, 3, 17, 12, 0, 0, 0, time.UTC)
+
+// services holds the food services over one in-memory database.
+type services struct {
+	pantry  *service.PantryService
+	meals   *service.MealService
+	recipes *service.RecipeService
+	plans   *service.MealPlanService
+	list    *service.ShoppingListService
+}
+
+func newServices() services {
+	db := memory.New()
+	pantry := memory.NewPantryStore(db)
+	recipes := memory.NewRecipeStore(db)
+	return services{
+		pantry:  service.NewPantryService(pantry),
+		meals:   service.NewMealService(memory.NewMealStore(db)),
+		recipes: service.NewRecipeService(recipes),
+		plans:   service.NewMealPlanService(memory.NewMealPlanStore(db), recipes),
+		list:    service.NewShoppingListService(pantry, memory.NewShoppingListStore(db)),
+	}
+}
+
+// pantryItem adds an item measured in unit to the user's pantry.
+func (s services) pantryItem(t *testing.T, name string, quantity float64, unit string) models.PantryItem {
+	t.Helper()
+	p, err := s.pantry.Create(context.Background(), userID, models.PantryItemInput{Name: name, Quantity: quantity, Unit: unit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// wantKind fails the test unless err is a service error of the kind.
+func wantKind(t *testing.T, err error, kind service.Kind) {
+	t.Helper()
+	if got := service.KindOf(err); err == nil || got != kind {
+		t.Errorf("err = %v (%v), want a %v error", err, got, kind)
+	}
+}
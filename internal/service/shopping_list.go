@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var errShoppingListItemNotFound = errorf(NotFound, "Shopping list item not found")
+
+type ShoppingListService struct {
+	items store.PantryStore
+	list  store.ShoppingListStore
+}
+
+func NewShoppingListService(items store.PantryStore, list store.ShoppingListStore) *ShoppingListService {
+	return &ShoppingListService{items: items, list: list}
+}
+
+// List builds the shopping list. A nil plan or outlook leaves the meal plan
+// or the projection out.
+func (s *ShoppingListService) List(ctx context.Context, userID int, plan *pantry.PlanWindow, outlook *pantry.Outlook) ([]models.ShoppingListItem, error) {
+	return s.list.List(ctx, userID, plan, outlook)
+}
+
+// Add records an item added by hand. An item tied to a pantry item takes
+// its unit and, unless named, its name.
+func (s *ShoppingListService) Add(ctx context.Context, userID int, input models.ShoppingListItemInput) (models.ShoppingListItem, error) {
+	input.Name = strings.TrimSpace(input.Name)
+
+	if input.PantryItemID != nil {
+		p, err := s.items.Get(ctx, userID, *input.PantryItemID)
+		if errors.Is(err, store.ErrNotFound) {
+			return models.ShoppingListItem{}, errorf(Validation, "Pantry item not found")
+		} else if err != nil {
+			return models.ShoppingListItem{}, err
+		}
+
+		if input.Unit != nil && !sameUnit(*input.Unit, p.Unit) {
+			return models.ShoppingListItem{}, errorf(Validation, "Unit must match the pantry item's unit (%s)", p.Unit)
+		}
+		input.Unit = &p.Unit
+		if input.Name == "" {
+			input.Name = p.Name
+		}
+	}
+
+	if input.Name == "" {
+		return models.ShoppingListItem{}, errorf(Validation, "Name or pantry_item_id is required")
+	}
+	return s.list.Add(ctx, userID, input)
+}
+
+func (s *ShoppingListService) Check(ctx context.Context, userID, id int, checked bool) error {
+	err := s.list.Check(ctx, userID, id, checked)
+	if errors.Is(err, store.ErrNotFound) {
+		return errShoppingListItemNotFound
+	}
+	return err
+}
+
+// CheckPantryItem sets the checked state of the row for one of the user's
+// pantry items.
+func (s *ShoppingListService) CheckPantryItem(ctx context.Context, userID, pantryItemID int, checked bool) error {
+	err := s.list.CheckPantryItem(ctx, userID, pantryItemID, checked)
+	if errors.Is(err, store.ErrNotFound) {
+		return errPantryItemNotFound
+	}
+	return err
+}
+
+func (s *ShoppingListService) Delete(ctx context.Context, userID, id int) error {
+	err := s.list.Delete(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errShoppingListItemNotFound
+	}
+	return err
+}
+
+// Purchase restocks the checked rows and clears them.
+func (s *ShoppingListService) Purchase(ctx context.Context, userID int, plan *pantry.PlanWindow, outlook *pantry.Outlook) (models.ShoppingListPurchase, error) {
+	return s.list.Purchase(ctx, userID, plan, outlook)
+}
+
+// sameUnit reports whether s spells the canonical unit.
+func sameUnit(s, unit string) bool {
+	u, ok := pantry.ParseUnit(s)
+	return ok && u == unit
+}
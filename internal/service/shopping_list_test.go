@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+)
+
+func TestShoppingListServiceAdd(t *testing.T) {
+	ctx := context.Background()
+	s := newServices()
+	oats := s.pantryItem(t, "Oats", 750, "g")
+	other, err := s.pantry.Create(ctx, userID+1, models.PantryItemInput{Name: "Rice", Unit: "g"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grams, litres := "grams", "l"
+	tests := []struct {
+		name  string
+		input models.ShoppingListItemInput
+		kind  service.Kind
+	}{
+		{"no name", models.ShoppingListItemInput{Name: "  "}, service.Validation},
+		{"unknown pantry item", models.ShoppingListItemInput{PantryItemID: ptr(999)}, service.Validation},
+		{"other user's pantry item", models.ShoppingListItemInput{PantryItemID: &other.ID}, service.Validation},
+		{"other unit", models.ShoppingListItemInput{PantryItemID: &oats.ID, Unit: &litres}, service.Validation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.list.Add(ctx, userID, tt.input)
+			wantKind(t, err, tt.kind)
+		})
+	}
+
+	// The pantry item fills in the name and the canonical spelling of its
+	// unit.
+	item, err := s.list.Add(ctx, userID, models.ShoppingListItemInput{PantryItemID: &oats.ID, Quantity: ptr(500.0), Unit: &grams})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ItemID == nil || item.Name != "Oats" || item.Unit == nil || *item.Unit != "g" {
+		t.Fatalf("item = %+v, want Oats in g", item)
+	}
+
+	wantKind(t, s.list.Check(ctx, userID, 999, true), service.NotFound)
+	wantKind(t, s.list.CheckPantryItem(ctx, userID+1, oats.ID, true), service.NotFound)
+	wantKind(t, s.list.Delete(ctx, userID+1, *item.ItemID), service.NotFound)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
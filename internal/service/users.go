@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/account"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var errUserNotFound = errorf(NotFound, "User not found")
+
+type UserService struct {
+	users store.UserStore
+}
+
+func NewUserService(users store.UserStore) *UserService {
+	return &UserService{users: users}
+}
+
+func (s *UserService) List(ctx context.Context) ([]models.User, error) {
+	return s.users.List(ctx)
+}
+
+func (s *UserService) Get(ctx context.Context, id int) (models.User, error) {
+	u, err := s.users.Get(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return u, errUserNotFound
+	}
+	return u, err
+}
+
+func (s *UserService) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	u, err := s.users.FindByEmail(ctx, email)
+	if errors.Is(err, store.ErrNotFound) {
+		return u, errUserNotFound
+	}
+	return u, err
+}
+
+// Create registers a user, storing only a hash of the password. Emails are
+// unique regardless of case.
+func (s *UserService) Create(ctx context.Context, email, password string) (models.User, error) {
+	if email == "" || password == "" {
+		return models.User{}, errorf(Validation, "Email and password are required")
+	}
+	if err := s.checkEmailFree(ctx, email, 0); err != nil {
+		return models.User{}, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return models.User{}, err
+	}
+	return s.users.Create(ctx, email, string(hash))
+}
+
+// UpdateEmail changes the user's email once their current password has
+// been checked.
+func (s *UserService) UpdateEmail(ctx context.Context, id int, email, currentPassword string) (models.User, error) {
+	if err := s.Reauthenticate(ctx, id, currentPassword); err != nil {
+		return models.User{}, err
+	}
+	if err := s.checkEmailFree(ctx, email, id); err != nil {
+		return models.User{}, err
+	}
+	u, err := s.users.UpdateEmail(ctx, id, email)
+	if errors.Is(err, store.ErrNotFound) {
+		return u, errUserNotFound
+	}
+	return u, err
+}
+
+// UpdatePassword changes the user's password once their current one has
+// been checked.
+func (s *UserService) UpdatePassword(ctx context.Context, id int, currentPassword, newPassword string) error {
+	if err := s.Reauthenticate(ctx, id, currentPassword); err != nil {
+		return err
+	}
+	return s.setPassword(ctx, id, newPassword)
+}
+
+// ResetPassword sets a new password without checking the current one, for
+// administrators.
+func (s *UserService) ResetPassword(ctx context.Context, id int, newPassword string) error {
+	return s.setPassword(ctx, id, newPassword)
+}
+
+// setPassword stores a hash of a new, non-empty password.
+func (s *UserService) setPassword(ctx context.Context, id int, password string) error {
+	if password == "" {
+		return errorf(Validation, "Password is required")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	err = s.users.UpdatePassword(ctx, id, string(hash))
+	if errors.Is(err, store.ErrNotFound) {
+		return errUserNotFound
+	}
+	return err
+}
+
+// SetDisabled disables the account, after which its password is no longer
+// accepted, or enables it again.
+func (s *UserService) SetDisabled(ctx context.Context, id int, disabled bool) error {
+	err := s.users.SetDisabled(ctx, id, disabled)
+	if errors.Is(err, store.ErrNotFound) {
+		return errUserNotFound
+	}
+	return err
+}
+
+// ScheduleDeletion marks the account for deletion after
+// account.DeletionGracePeriod once the password has been checked. A
+// repeated request keeps the original schedule.
+func (s *UserService) ScheduleDeletion(ctx context.Context, id int, password string) (models.AccountDeletionStatus, error) {
+	if err := s.Reauthenticate(ctx, id, password); err != nil {
+		return models.AccountDeletionStatus{}, err
+	}
+	status, err := s.users.ScheduleDeletion(ctx, id, account.DeletionGracePeriod)
+	if errors.Is(err, store.ErrNotFound) {
+		return status, errUserNotFound
+	}
+	return status, err
+}
+
+func (s *UserService) DeletionStatus(ctx context.Context, id int) (models.AccountDeletionStatus, error) {
+	status, err := s.users.DeletionStatus(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return status, errUserNotFound
+	}
+	return status, err
+}
+
+// CancelDeletion clears a pending deletion once the password has been
+// checked.
+func (s *UserService) CancelDeletion(ctx context.Context, id int, password string) error {
+	if err := s.Reauthenticate(ctx, id, password); err != nil {
+		return err
+	}
+	err := s.users.CancelDeletion(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errorf(NotFound, "No account deletion is pending")
+	}
+	return err
+}
+
+// Reauthenticate checks password against the user's stored hash, returning
+// an Unauthorized error when it does not match and a Forbidden error when
+// the account is disabled.
+func (s *UserService) Reauthenticate(ctx context.Context, id int, password string) error {
+	u, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if u.DisabledAt != nil {
+		return errorf(Forbidden, "Account is disabled")
+	}
+	hash, err := s.users.PasswordHash(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return errUserNotFound
+	} else if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return errorf(Unauthorized, "Current password is incorrect")
+	}
+	return nil
+}
+
+func (s *UserService) checkEmailFree(ctx context.Context, email string, exceptID int) error {
+	taken, err := s.users.EmailTaken(ctx, email, exceptID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errorf(Conflict, "Email is already in use")
+	}
+	return nil
+}
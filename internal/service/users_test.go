@@ -0,0 +1,33 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/memory"
+)
+
+func TestUserServicePasswords(t *testing.T) {
+	ctx := context.Background()
+	users := service.NewUserService(memory.NewUserStore(memory.New()))
+	u, err := users.Create(ctx, "lifter@example.com", "hunter22")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantKind(t, users.UpdatePassword(ctx, u.ID, "hunter22", ""), service.Validation)
+	wantKind(t, users.ResetPassword(ctx, u.ID, ""), service.Validation)
+	wantKind(t, users.UpdatePassword(ctx, u.ID, "wrong", "squat-rack"), service.Unauthorized)
+	if err := users.Reauthenticate(ctx, u.ID, "hunter22"); err != nil {
+		t.Fatalf("rejected passwords replaced the old one: %v", err)
+	}
+
+	if err := users.UpdatePassword(ctx, u.ID, "hunter22", "squat-rack"); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Reauthenticate(ctx, u.ID, "squat-rack"); err != nil {
+		t.Errorf("new password: %v", err)
+	}
+	wantKind(t, users.ResetPassword(ctx, 999, "deadlift"), service.NotFound)
+}
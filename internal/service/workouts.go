@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+// historyLimit is how many sets History returns.
+const historyLimit = 10
+
+type WorkoutService struct {
+	workouts store.WorkoutStore
+}
+
+func NewWorkoutService(workouts store.WorkoutStore) *WorkoutService {
+	return &WorkoutService{workouts: workouts}
+}
+
+func (s *WorkoutService) ListByUser(ctx context.Context, userID int) ([]models.WorkoutSession, error) {
+	return s.workouts.ListByUser(ctx, userID)
+}
+
+// Get returns one of the user's sessions. Sessions of other users are
+// reported as not found.
+func (s *WorkoutService) Get(ctx context.Context, userID, id int) (models.WorkoutSessionWithExercises, error) {
+	w, err := s.workouts.Get(ctx, userID, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return w, errorf(NotFound, "Workout not found or not authorized")
+	}
+	return w, err
+}
+
+// Calendar totals the user's sessions for each day from from to to in loc.
+func (s *WorkoutService) Calendar(ctx context.Context, userID int, loc *time.Location, from, to time.Time) (models.WorkoutCalendar, error) {
+	if from.After(to) {
+		return models.WorkoutCalendar{}, errorf(Validation, "from must not be after to")
+	}
+	calendar := models.WorkoutCalendar{
+		Timezone: loc.String(),
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+	}
+	var err error
+	calendar.Days, err = s.workouts.DailySummary(ctx, userID, calendar.Timezone, calendar.From, calendar.To)
+	return calendar, err
+}
+
+// History returns the user's latest sets of an exercise on a piece of
+// equipment, newest first.
+func (s *WorkoutService) History(ctx context.Context, userID, exerciseID, equipmentID int) ([]models.WorkoutExerciseWithDetails, error) {
+	return s.workouts.History(ctx, userID, exerciseID, equipmentID, historyLimit)
+}
+
+// Latest returns the user's last set of an exercise on a piece of
+// equipment.
+func (s *WorkoutService) Latest(ctx context.Context, userID, exerciseID, equipmentID int) (models.WorkoutExerciseWithDetails, error) {
+	history, err := s.workouts.History(ctx, userID, exerciseID, equipmentID, 1)
+	if err != nil {
+		return models.WorkoutExerciseWithDetails{}, err
+	}
+	if len(history) == 0 {
+		return models.WorkoutExerciseWithDetails{}, errorf(NotFound, "No previous workout found for this exercise and equipment")
+	}
+	return history[0], nil
+}
+
+func (s *WorkoutService) Create(ctx context.Context, userID int, input models.WorkoutSessionWithExercisesInput) (models.WorkoutSessionWithExercises, error) {
+	w, err := s.workouts.Create(ctx, userID, input)
+	if err != nil {
+		return w, err
+	}
+	metrics.SessionsCreated.Inc()
+	for _, e := range w.Exercises {
+		countExercise(e.Sets, e.PersonalRecord)
+	}
+	return w, nil
+}
+
+func (s *WorkoutService) AddExercise(ctx context.Context, sessionID int, input models.WorkoutExerciseInput) (models.WorkoutExercise, error) {
+	e, err := s.workouts.AddExercise(ctx, sessionID, input)
+	if errors.Is(err, store.ErrNotFound) {
+		return e, errorf(NotFound, "Workout session not found")
+	}
+	if err != nil {
+		return e, err
+	}
+	countExercise(e.Sets, e.PersonalRecord)
+	return e, nil
+}
+
+func countExercise(sets int, personalRecord bool) {
+	metrics.SetsLogged.Add(float64(sets))
+	if personalRecord {
+		metrics.PersonalRecords.Inc()
+	}
+}
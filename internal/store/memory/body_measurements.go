@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/analytics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+type BodyMeasurementStore struct {
+	db *DB
+}
+
+func NewBodyMeasurementStore(db *DB) *BodyMeasurementStore {
+	return &BodyMeasurementStore{db: db}
+}
+
+func (s *BodyMeasurementStore) List(ctx context.Context, userID int, from, until time.Time) ([]models.BodyMeasurement, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	entries := []models.BodyMeasurement{}
+	for _, m := range s.db.sortedMeasurements(userID) {
+		if !m.MeasuredAt.Before(from) && m.MeasuredAt.Before(until) {
+			entries = append(entries, *m)
+		}
+	}
+	return entries, nil
+}
+
+func (s *BodyMeasurementStore) Create(ctx context.Context, userID int, input models.BodyMeasurementInput) (models.BodyMeasurement, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	m := &models.BodyMeasurement{ID: s.db.id(), UserID: userID, CreatedAt: s.db.Now()}
+	setBodyMeasurement(m, input)
+	s.db.measurements[m.ID] = m
+	return *m, nil
+}
+
+func (s *BodyMeasurementStore) Update(ctx context.Context, userID, id int, input models.BodyMeasurementInput) (models.BodyMeasurement, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	m, ok := s.db.measurements[id]
+	if !ok || m.UserID != userID {
+		return models.BodyMeasurement{}, store.ErrNotFound
+	}
+	setBodyMeasurement(m, input)
+	return *m, nil
+}
+
+func (s *BodyMeasurementStore) Delete(ctx context.Context, userID, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	m, ok := s.db.measurements[id]
+	if !ok || m.UserID != userID {
+		return store.ErrNotFound
+	}
+	delete(s.db.measurements, id)
+	return nil
+}
+
+func (s *BodyMeasurementStore) Bodyweights(ctx context.Context, userID int, until time.Time) ([]analytics.Sample, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var samples []analytics.Sample
+	for _, m := range s.db.sortedMeasurements(userID) {
+		if m.BodyweightKg != nil && m.MeasuredAt.Before(until) {
+			samples = append(samples, analytics.Sample{At: m.MeasuredAt, Value: *m.BodyweightKg})
+		}
+	}
+	return samples, nil
+}
+
+// TrainingWeeks returns the weeks set in Training, whatever the range.
+// Their estimated 1RM is taken to be for whichever exercise is asked
+// about and is dropped when none is.
+func (s *BodyMeasurementStore) TrainingWeeks(ctx context.Context, userID, exerciseID int, tz string, from, until time.Time) (map[string]models.BodyweightWeek, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	weeks := map[string]models.BodyweightWeek{}
+	for week, w := range s.db.Training[userID].Weeks {
+		if exerciseID == 0 {
+			w.BestEstimated1RM = nil
+		}
+		weeks[week] = w
+	}
+	return weeks, nil
+}
+
+func setBodyMeasurement(m *models.BodyMeasurement, input models.BodyMeasurementInput) {
+	m.MeasuredAt = input.MeasuredAt.UTC()
+	m.BodyweightKg = input.BodyweightKg
+	m.BodyFatPct = input.BodyFatPct
+	m.WaistCm = input.WaistCm
+	m.ArmsCm = input.ArmsCm
+	m.ChestCm = input.ChestCm
+	m.Notes = input.Notes
+}
+
+func (db *DB) sortedMeasurements(userID int) []*models.BodyMeasurement {
+	var list []*models.BodyMeasurement
+	for _, m := range db.measurements {
+		if m.UserID == userID {
+			list = append(list, m)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].MeasuredAt.Before(list[j].MeasuredAt) })
+	return list
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+type FoodStore struct {
+	db *DB
+}
+
+func NewFoodStore(db *DB) *FoodStore {
+	return &FoodStore{db: db}
+}
+
+// AddFood adds a food to the catalogue, which the API only reads.
+func (db *DB) AddFood(f models.Food) models.Food {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	f.ID = db.id()
+	db.foods[f.ID] = f
+	return f
+}
+
+// Search matches foods whose name contains every word of the tsquery,
+// ignoring case and prefix markers.
+func (s *FoodStore) Search(ctx context.Context, query string, limit int) ([]models.Food, error) {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return r == '&' || r == '|' || r == ' ' || r == '(' || r == ')' || r == '!'
+	})
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	results := []models.Food{}
+	for _, f := range s.db.foods {
+		name := strings.ToLower(f.Name)
+		match := true
+		for _, w := range words {
+			if !strings.Contains(name, strings.TrimSuffix(w, ":*")) {
+				match = false
+			}
+		}
+		if match {
+			results = append(results, f)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (s *FoodStore) Get(ctx context.Context, id int) (models.Food, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	f, ok := s.db.foods[id]
+	if !ok {
+		return models.Food{}, store.ErrNotFound
+	}
+	return f, nil
+}
+
+func (s *FoodStore) FindByBarcode(ctx context.Context, code string) (models.Food, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var found *models.Food
+	for _, f := range s.db.foods {
+		if f.Barcode == nil || *f.Barcode != code {
+			continue
+		}
+		if found == nil || f.UpdatedAt.After(found.UpdatedAt) || (f.UpdatedAt.Equal(found.UpdatedAt) && f.ID > found.ID) {
+			f := f
+			found = &f
+		}
+	}
+	if found == nil {
+		return models.Food{}, store.ErrNotFound
+	}
+	return *found, nil
+}
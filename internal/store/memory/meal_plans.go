@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+// Entries keep the ingredients planned without a recipe; a recipe's
+// ingredients are scaled to the planned servings when they are read.
+
+type MealPlanStore struct {
+	db *DB
+}
+
+func NewMealPlanStore(db *DB) *MealPlanStore {
+	return &MealPlanStore{db: db}
+}
+
+func (s *MealPlanStore) List(ctx context.Context, userID int, from, to string) ([]models.MealPlanEntry, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return s.db.mealPlanEntries(userID, from, to), nil
+}
+
+func (s *MealPlanStore) Get(ctx context.Context, userID, id int) (models.MealPlanEntry, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	e, ok := s.db.mealPlans[id]
+	if !ok || e.UserID != userID {
+		return models.MealPlanEntry{}, store.ErrNotFound
+	}
+	return s.db.mealPlanEntry(e), nil
+}
+
+func (s *MealPlanStore) Create(ctx context.Context, userID int, input models.MealPlanEntryInput) (models.MealPlanEntry, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	e := &models.MealPlanEntry{ID: s.db.id(), UserID: userID, CreatedAt: s.db.Now()}
+	if err := s.db.setMealPlanEntry(e, input); err != nil {
+		return models.MealPlanEntry{}, err
+	}
+	s.db.mealPlans[e.ID] = e
+	return s.db.mealPlanEntry(e), nil
+}
+
+func (s *MealPlanStore) Update(ctx context.Context, userID, id int, input models.MealPlanEntryInput) (models.MealPlanEntry, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	e, ok := s.db.mealPlans[id]
+	if !ok || e.UserID != userID {
+		return models.MealPlanEntry{}, store.ErrNotFound
+	}
+	updated := *e
+	if err := s.db.setMealPlanEntry(&updated, input); err != nil {
+		return models.MealPlanEntry{}, err
+	}
+	*e = updated
+	return s.db.mealPlanEntry(e), nil
+}
+
+func (s *MealPlanStore) Delete(ctx context.Context, userID, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	e, ok := s.db.mealPlans[id]
+	if !ok || e.UserID != userID {
+		return store.ErrNotFound
+	}
+	delete(s.db.mealPlans, id)
+	return nil
+}
+
+func (s *MealPlanStore) Requirements(ctx context.Context, userID int, plan pantry.PlanWindow) ([]models.MealPlanRequirement, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	items := []models.MealPlanRequirement{}
+	for id, quantity := range s.db.planned(userID, plan) {
+		p := s.db.pantryItems[id]
+		items = append(items, models.MealPlanRequirement{
+			PantryItemID: id,
+			Name:         p.Name,
+			Unit:         p.Unit,
+			Required:     quantity,
+			Available:    p.Quantity,
+			Shortfall:    max(quantity-p.Quantity, 0),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Shortfall != items[j].Shortfall {
+			return items[i].Shortfall > items[j].Shortfall
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+func (db *DB) setMealPlanEntry(e *models.MealPlanEntry, input models.MealPlanEntryInput) error {
+	e.Ingredients = nil
+	if len(input.Ingredients) > 0 {
+		ingredients, err := db.recipeIngredients(e.UserID, input.Ingredients)
+		if err != nil {
+			return err
+		}
+		for _, ing := range ingredients {
+			e.Ingredients = append(e.Ingredients, models.MealPlanIngredient{PantryItemID: ing.PantryItemID, Quantity: ing.Quantity})
+		}
+	}
+	e.Date, e.RecipeID, e.Servings, e.Notes = input.Date, input.RecipeID, input.Servings, input.Notes
+	return nil
+}
+
+func (db *DB) mealPlanEntries(userID int, from, to string) []models.MealPlanEntry {
+	entries := []models.MealPlanEntry{}
+	for _, e := range db.mealPlans {
+		if e.UserID == userID && e.Date >= from && e.Date <= to {
+			entries = append(entries, db.mealPlanEntry(e))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Date != entries[j].Date {
+			return entries[i].Date < entries[j].Date
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// mealPlanEntry returns a copy of e with the recipe's name and the pantry
+// items it uses filled in.
+func (db *DB) mealPlanEntry(e *models.MealPlanEntry) models.MealPlanEntry {
+	out := *e
+	out.RecipeName = nil
+	out.Ingredients = []models.MealPlanIngredient{}
+	if e.RecipeID != nil {
+		if r, ok := db.recipes[*e.RecipeID]; ok {
+			name := r.Name
+			out.RecipeName = &name
+			for _, ing := range r.Ingredients {
+				out.Ingredients = append(out.Ingredients, models.MealPlanIngredient{
+					PantryItemID: ing.PantryItemID,
+					Quantity:     ing.Quantity * *e.Servings / r.Servings,
+				})
+			}
+		}
+	}
+	out.Ingredients = append(out.Ingredients, e.Ingredients...)
+	for i := range out.Ingredients {
+		p := db.pantryItems[out.Ingredients[i].PantryItemID]
+		out.Ingredients[i].Name, out.Ingredients[i].Unit = p.Name, p.Unit
+	}
+	sort.SliceStable(out.Ingredients, func(i, j int) bool { return out.Ingredients[i].Name < out.Ingredients[j].Name })
+	return out
+}
+
+// planned totals what the user's plan entries in the window use, by pantry
+// item.
+func (db *DB) planned(userID int, plan pantry.PlanWindow) map[int]float64 {
+	totals := map[int]float64{}
+	for _, e := range db.mealPlanEntries(userID, plan.From, plan.To) {
+		for _, ing := range e.Ingredients {
+			totals[ing.PantryItemID] += ing.Quantity
+		}
+	}
+	return totals
+}
@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+// Meals keep only the pantry item and quantity of each ingredient; names
+// and nutrition are filled in from the pantry when they are read, as the
+// Postgres store does. The dated batches a meal drew from are kept with it
+// so that they can be put back.
+
+type MealStore struct {
+	db *DB
+}
+
+func NewMealStore(db *DB) *MealStore {
+	return &MealStore{db: db}
+}
+
+func (s *MealStore) List(ctx context.Context, userID int, from, until time.Time) ([]models.Meal, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	list := []models.Meal{}
+	for _, m := range s.db.meals {
+		if m.UserID == userID && !m.CreatedAt.Before(from) && m.CreatedAt.Before(until) {
+			meal, err := s.db.meal(m)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, meal)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+func (s *MealStore) Get(ctx context.Context, userID, id int) (models.Meal, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	m, ok := s.db.meals[id]
+	if !ok || m.UserID != userID {
+		return models.Meal{}, store.ErrNotFound
+	}
+	return s.db.meal(m)
+}
+
+func (s *MealStore) Create(ctx context.Context, userID int, eatenAt time.Time, ingredients []models.MealIngredientInput) (models.Meal, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return s.db.insertMeal(userID, eatenAt, ingredients)
+}
+
+func (s *MealStore) Update(ctx context.Context, userID, id int, eatenAt *time.Time, ingredients []models.MealIngredientInput) (models.Meal, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	m, ok := s.db.meals[id]
+	if !ok || m.UserID != userID {
+		return models.Meal{}, store.ErrNotFound
+	}
+
+	old := m.Ingredients
+	s.db.restore(m)
+	used, drawn, err := s.db.consume(userID, ingredients)
+	if err != nil {
+		// Stock only came back from the meal, so it covers the meal again.
+		m.Ingredients, s.db.mealBatches[id], _ = s.db.consume(userID, mealIngredientInputs(old))
+		return models.Meal{}, err
+	}
+	m.Ingredients, s.db.mealBatches[id] = used, drawn
+	if eatenAt != nil {
+		m.CreatedAt = eatenAt.UTC()
+	}
+	return s.db.meal(m)
+}
+
+func (s *MealStore) Delete(ctx context.Context, userID, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	m, ok := s.db.meals[id]
+	if !ok || m.UserID != userID {
+		return store.ErrNotFound
+	}
+	s.db.restore(m)
+	delete(s.db.meals, id)
+	return nil
+}
+
+func (db *DB) insertMeal(userID int, eatenAt time.Time, ingredients []models.MealIngredientInput) (models.Meal, error) {
+	used, drawn, err := db.consume(userID, ingredients)
+	if err != nil {
+		return models.Meal{}, err
+	}
+	m := &models.Meal{ID: db.id(), UserID: userID, Ingredients: used, CreatedAt: eatenAt.UTC()}
+	db.meals[m.ID] = m
+	db.mealBatches[m.ID] = drawn
+	return db.meal(m)
+}
+
+// consume converts the ingredients into their items' units and deducts
+// them from the pantry, drawing dated batches first as pantry.Consume
+// does, or changes nothing when any item falls short. It returns what was
+// used and the batches it was drawn from.
+func (db *DB) consume(userID int, ingredients []models.MealIngredientInput) ([]models.MealIngredient, []mealBatch, error) {
+	amounts := make([]pantry.Amount, len(ingredients))
+	for i, ing := range ingredients {
+		amounts[i] = pantry.Amount{PantryItemID: ing.PantryItemID, Quantity: ing.QuantityUsed, Unit: ing.Unit}
+	}
+	amounts, err := db.inStockUnits(userID, amounts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var shortfalls []models.StockShortfall
+	for _, a := range amounts {
+		p := db.pantryItems[a.PantryItemID]
+		if a.Quantity > p.Quantity {
+			shortfalls = append(shortfalls, models.StockShortfall{
+				PantryItemID: p.ID,
+				Name:         p.Name,
+				Unit:         p.Unit,
+				Required:     a.Quantity,
+				Available:    p.Quantity,
+			})
+		}
+	}
+	if len(shortfalls) > 0 {
+		sort.Slice(shortfalls, func(i, j int) bool { return shortfalls[i].PantryItemID < shortfalls[j].PantryItemID })
+		return nil, nil, &pantry.InsufficientStockError{Shortfalls: shortfalls}
+	}
+
+	used := make([]models.MealIngredient, len(amounts))
+	var drawn []mealBatch
+	for i, a := range amounts {
+		p := db.pantryItems[a.PantryItemID]
+		p.Quantity -= a.Quantity
+		p.UpdatedAt = db.Now()
+		drawn = append(drawn, db.drawBatches(p.ID, a.Quantity)...)
+		used[i] = models.MealIngredient{PantryItemID: a.PantryItemID, QuantityUsed: a.Quantity}
+	}
+	return used, drawn, nil
+}
+
+// restore puts the stock used by a meal back into the pantry, including
+// the dated batches it was drawn from.
+func (db *DB) restore(m *models.Meal) {
+	for _, ing := range m.Ingredients {
+		if p, ok := db.pantryItems[ing.PantryItemID]; ok {
+			p.Quantity += ing.QuantityUsed
+			p.UpdatedAt = db.Now()
+		}
+	}
+	for _, b := range db.mealBatches[m.ID] {
+		if _, ok := db.pantryItems[b.pantryItemID]; ok {
+			db.addBatch(b.pantryItemID, b.quantity, b.expiresOn)
+		}
+	}
+	delete(db.mealBatches, m.ID)
+}
+
+// meal returns a copy of m with its ingredients and totals worked out from
+// the pantry items' current per-unit values.
+func (db *DB) meal(m *models.Meal) (models.Meal, error) {
+	out := *m
+	out.Ingredients = []models.MealIngredient{}
+	for _, ing := range m.Ingredients {
+		p, ok := db.pantryItems[ing.PantryItemID]
+		if !ok {
+			return models.Meal{}, fmt.Errorf("memory: meal %d uses deleted pantry item %d: %w", m.ID, ing.PantryItemID, store.ErrNotFound)
+		}
+		q := ing.QuantityUsed
+		ing.Name, ing.Unit = p.Name, p.Unit
+		ing.Calories = q * p.CaloriesPerUnit
+		ing.Protein = q * p.ProteinPerUnit
+		ing.Carbs = q * p.CarbsPerUnit
+		ing.Fat = q * p.FatPerUnit
+		ing.Fiber = q * p.FiberPerUnit
+		out.Ingredients = append(out.Ingredients, ing)
+		out.TotalCalories += ing.Calories
+		out.TotalProtein += ing.Protein
+		out.TotalCarbs += ing.Carbs
+		out.TotalFat += ing.Fat
+		out.TotalFiber += ing.Fiber
+	}
+	sort.Slice(out.Ingredients, func(i, j int) bool { return out.Ingredients[i].Name < out.Ingredients[j].Name })
+	return out, nil
+}
+
+func mealIngredientInputs(ingredients []models.MealIngredient) []models.MealIngredientInput {
+	in := make([]models.MealIngredientInput, len(ingredients))
+	for i, ing := range ingredients {
+		in[i] = models.MealIngredientInput{PantryItemID: ing.PantryItemID, QuantityUsed: ing.QuantityUsed}
+	}
+	return in
+}
@@ -0,0 +1,106 @@
+// Package memory implements the store interfaces in memory so that
+// handlers and services can be tested without a database. The stores share
+// one DB, as the Postgres stores share one connection pool, so stock used
+// by a meal is gone from the pantry store too.
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+var (
+	_ store.UserStore            = (*UserStore)(nil)
+	_ store.ProfileStore         = (*ProfileStore)(nil)
+	_ store.PantryStore          = (*PantryStore)(nil)
+	_ store.MealStore            = (*MealStore)(nil)
+	_ store.RecipeStore          = (*RecipeStore)(nil)
+	_ store.MealPlanStore        = (*MealPlanStore)(nil)
+	_ store.ShoppingListStore    = (*ShoppingListStore)(nil)
+	_ store.BodyMeasurementStore = (*BodyMeasurementStore)(nil)
+	_ store.NutritionStore       = (*NutritionStore)(nil)
+	_ store.FoodStore            = (*FoodStore)(nil)
+)
+
+// DB holds the rows of every store. The zero value is not usable; call
+// New.
+type DB struct {
+	mu     sync.Mutex
+	nextID int
+
+	// Now is the clock used for created and updated times.
+	Now func() time.Time
+
+	users        map[int]*user
+	profiles     map[int]models.UserProfile
+	pantryItems  map[int]*models.PantryItem
+	batches      map[int]*models.PantryBatch
+	meals        map[int]*models.Meal
+	mealBatches  map[int][]mealBatch
+	recipes      map[int]*models.Recipe
+	mealPlans    map[int]*models.MealPlanEntry
+	shopping     map[int]*shoppingItem
+	checked      map[int]bool
+	measurements map[int]*models.BodyMeasurement
+	targets      map[int]models.NutritionTargets
+	foods        map[int]models.Food
+
+	// Training is what TrainingWeeks and Training report, keyed by user.
+	Training map[int]Training
+}
+
+// Training is the logged training a user's stores report. Workouts are
+// not stored here, so tests set it directly.
+type Training struct {
+	Weeks         map[string]models.BodyweightWeek
+	Sets          float64
+	CardioMinutes float64
+}
+
+type user struct {
+	models.User
+	deletion models.AccountDeletionStatus
+}
+
+// mealBatch is stock a meal drew from a dated batch, as recorded in
+// meal_ingredient_batches.
+type mealBatch struct {
+	pantryItemID int
+	expiresOn    string
+	quantity     float64
+}
+
+type shoppingItem struct {
+	models.ShoppingListItem
+	userID int
+}
+
+func New() *DB {
+	return &DB{
+		Now:          time.Now,
+		users:        map[int]*user{},
+		profiles:     map[int]models.UserProfile{},
+		pantryItems:  map[int]*models.PantryItem{},
+		batches:      map[int]*models.PantryBatch{},
+		meals:        map[int]*models.Meal{},
+		mealBatches:  map[int][]mealBatch{},
+		recipes:      map[int]*models.Recipe{},
+		mealPlans:    map[int]*models.MealPlanEntry{},
+		shopping:     map[int]*shoppingItem{},
+		checked:      map[int]bool{},
+		measurements: map[int]*models.BodyMeasurement{},
+		targets:      map[int]models.NutritionTargets{},
+		foods:        map[int]models.Food{},
+		Training:     map[int]Training{},
+	}
+}
+
+// id returns the next ID. IDs are unique across tables, which catches
+// handlers that look a row up in the wrong store.
+func (db *DB) id() int {
+	db.nextID++
+	return db.nextID
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+type NutritionStore struct {
+	db *DB
+}
+
+func NewNutritionStore(db *DB) *NutritionStore {
+	return &NutritionStore{db: db}
+}
+
+func (s *NutritionStore) Days(ctx context.Context, userID int, tz, from, to string) (map[string]models.NutritionDay, error) {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	days := map[string]models.NutritionDay{}
+	for _, m := range s.db.meals {
+		day := m.CreatedAt.In(loc).Format("2006-01-02")
+		if m.UserID != userID || day < from || day > to {
+			continue
+		}
+		meal, err := s.db.meal(m)
+		if err != nil {
+			return nil, err
+		}
+		d := days[day]
+		d.Meals++
+		d.Calories += meal.TotalCalories
+		d.Protein += meal.TotalProtein
+		d.Carbs += meal.TotalCarbs
+		d.Fat += meal.TotalFat
+		d.Fiber += meal.TotalFiber
+		days[day] = d
+	}
+	return days, nil
+}
+
+func (s *NutritionStore) Targets(ctx context.Context, userID int) (models.NutritionTargets, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if t, ok := s.db.targets[userID]; ok {
+		return t, nil
+	}
+	return models.NutritionTargets{UserID: userID}, nil
+}
+
+func (s *NutritionStore) SaveTargets(ctx context.Context, userID int, input models.NutritionTargetsInput) (models.NutritionTargets, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	now := s.db.Now()
+	t := models.NutritionTargets{
+		UserID: userID,
+		MacroTargets: models.MacroTargets{
+			Calories: input.Calories,
+			Protein:  input.Protein,
+			Carbs:    input.Carbs,
+			Fat:      input.Fat,
+			Fiber:    input.Fiber,
+		},
+		UpdatedAt: &now,
+	}
+	s.db.targets[userID] = t
+	return t, nil
+}
+
+// Training returns the totals set in Training, whatever since is.
+func (s *NutritionStore) Training(ctx context.Context, userID int, since time.Time) (sets, cardioMinutes float64, err error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	t := s.db.Training[userID]
+	return t.Sets, t.CardioMinutes, nil
+}
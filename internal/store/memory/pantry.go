@@ -0,0 +1,434 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+type PantryStore struct {
+	db *DB
+}
+
+func NewPantryStore(db *DB) *PantryStore {
+	return &PantryStore{db: db}
+}
+
+func (s *PantryStore) List(ctx context.Context, userID int, lowStock bool) ([]models.PantryItem, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	items := []models.PantryItem{}
+	for _, p := range s.db.pantryItems {
+		if p.UserID == userID && (!lowStock || p.Quantity < p.Threshold) {
+			items = append(items, s.db.pantryItem(p))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items, nil
+}
+
+func (s *PantryStore) Get(ctx context.Context, userID, id int) (models.PantryItem, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	p, ok := s.db.userPantryItem(userID, id)
+	if !ok {
+		return models.PantryItem{}, store.ErrNotFound
+	}
+	return s.db.pantryItem(p), nil
+}
+
+func (s *PantryStore) NameTaken(ctx context.Context, userID int, name string, exceptID int) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for _, p := range s.db.pantryItems {
+		if p.UserID == userID && p.Name == name && p.ID != exceptID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *PantryStore) Create(ctx context.Context, userID int, input models.PantryItemInput) (models.PantryItem, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	now := s.db.Now()
+	p := &models.PantryItem{ID: s.db.id(), UserID: userID, CreatedAt: now}
+	setPantryItem(p, input, now)
+	s.db.pantryItems[p.ID] = p
+	if input.ExpiresOn != nil && input.Quantity > 0 {
+		s.db.addBatch(p.ID, input.Quantity, *input.ExpiresOn)
+	}
+	return s.db.pantryItem(p), nil
+}
+
+func (s *PantryStore) Update(ctx context.Context, userID, id int, input models.PantryItemInput) (models.PantryItem, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	p, ok := s.db.userPantryItem(userID, id)
+	if !ok {
+		return models.PantryItem{}, store.ErrNotFound
+	}
+
+	oldQuantity, oldUnit := p.Quantity, p.Unit
+	if err := s.db.changeUnit(id, oldUnit, input.Unit, input.GramsPerItem); err != nil {
+		return models.PantryItem{}, err
+	}
+	setPantryItem(p, input, s.db.Now())
+
+	oldQuantity, converted := pantry.Convert(oldQuantity, oldUnit, input.Unit, input.GramsPerItem)
+	if input.ExpiresOn != nil && converted && input.Quantity > oldQuantity {
+		s.db.addBatch(id, input.Quantity-oldQuantity, *input.ExpiresOn)
+	}
+	s.db.trimBatches(p)
+	return s.db.pantryItem(p), nil
+}
+
+func (s *PantryStore) Restock(ctx context.Context, userID, id int, input models.PantryRestockInput) (models.PantryItem, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	p, ok := s.db.userPantryItem(userID, id)
+	if !ok {
+		return models.PantryItem{}, store.ErrNotFound
+	}
+	p.Quantity += input.Quantity
+	p.UpdatedAt = s.db.Now()
+	if input.ExpiresOn != nil {
+		s.db.addBatch(id, input.Quantity, *input.ExpiresOn)
+	}
+	if p.Quantity >= p.Threshold {
+		delete(s.db.checked, id)
+	}
+	return s.db.pantryItem(p), nil
+}
+
+func (s *PantryStore) InUse(ctx context.Context, id int) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return s.db.pantryItemUsed(id), nil
+}
+
+func (s *PantryStore) Delete(ctx context.Context, userID, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.userPantryItem(userID, id); !ok {
+		return store.ErrNotFound
+	}
+	delete(s.db.pantryItems, id)
+	for batchID, b := range s.db.batches {
+		if b.PantryItemID == id {
+			delete(s.db.batches, batchID)
+		}
+	}
+	for itemID, i := range s.db.shopping {
+		if i.PantryItemID != nil && *i.PantryItemID == id {
+			delete(s.db.shopping, itemID)
+		}
+	}
+	delete(s.db.checked, id)
+	return nil
+}
+
+func (s *PantryStore) Expiring(ctx context.Context, userID int, today string, days int) ([]models.ExpiringBatch, error) {
+	day, err := time.Parse("2006-01-02", today)
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	expiring := []models.ExpiringBatch{}
+	for _, b := range s.db.sortedBatches(0) {
+		p := s.db.pantryItems[b.PantryItemID]
+		expiresOn, _ := time.Parse("2006-01-02", b.ExpiresOn)
+		left := int(expiresOn.Sub(day).Hours() / 24)
+		if p.UserID != userID || left > days {
+			continue
+		}
+		expiring = append(expiring, models.ExpiringBatch{
+			BatchID:      b.ID,
+			PantryItemID: p.ID,
+			Name:         p.Name,
+			Unit:         p.Unit,
+			Quantity:     b.Quantity,
+			ExpiresOn:    b.ExpiresOn,
+			DaysLeft:     left,
+		})
+	}
+	return expiring, nil
+}
+
+func (s *PantryStore) Batches(ctx context.Context, userID, id int) (models.PantryItemBatches, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	result := models.PantryItemBatches{PantryItemID: id, Batches: []models.PantryBatch{}}
+	p, ok := s.db.userPantryItem(userID, id)
+	if !ok {
+		return result, store.ErrNotFound
+	}
+	result.Name, result.Unit, result.Quantity = p.Name, p.Unit, p.Quantity
+	result.Undated = p.Quantity
+	for _, b := range s.db.sortedBatches(id) {
+		result.Batches = append(result.Batches, *b)
+		result.Undated -= b.Quantity
+	}
+	result.Undated = max(result.Undated, 0)
+	return result, nil
+}
+
+func (s *PantryStore) DiscardBatch(ctx context.Context, userID, id, batchID int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	p, ok := s.db.userPantryItem(userID, id)
+	b, found := s.db.batches[batchID]
+	if !ok || !found || b.PantryItemID != id {
+		return store.ErrNotFound
+	}
+	delete(s.db.batches, batchID)
+	p.Quantity = max(p.Quantity-b.Quantity, 0)
+	p.UpdatedAt = s.db.Now()
+	return nil
+}
+
+// Forecast reports every item with no usage: meals are not dated against
+// the outlook's history here.
+func (s *PantryStore) Forecast(ctx context.Context, userID int, outlook pantry.Outlook, leadDays float64) ([]models.PantryForecast, error) {
+	items, err := s.List(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	forecast := []models.PantryForecast{}
+	for _, p := range items {
+		forecast = append(forecast, models.PantryForecast{
+			PantryItemID: p.ID,
+			Name:         p.Name,
+			Unit:         p.Unit,
+			Quantity:     p.Quantity,
+			Threshold:    p.Threshold,
+		})
+	}
+	return forecast, nil
+}
+
+// TuneThresholds changes nothing, as Forecast suggests no thresholds.
+func (s *PantryStore) TuneThresholds(ctx context.Context, userID int, outlook pantry.Outlook, leadDays float64) ([]models.PantryItem, error) {
+	return []models.PantryItem{}, nil
+}
+
+func setPantryItem(p *models.PantryItem, input models.PantryItemInput, now time.Time) {
+	p.Name = input.Name
+	p.Quantity = input.Quantity
+	p.Unit = input.Unit
+	p.Threshold = input.Threshold
+	p.CaloriesPerUnit = input.CaloriesPerUnit
+	p.ProteinPerUnit = input.ProteinPerUnit
+	p.CarbsPerUnit = input.CarbsPerUnit
+	p.FatPerUnit = input.FatPerUnit
+	p.FiberPerUnit = input.FiberPerUnit
+	p.GramsPerItem = input.GramsPerItem
+	p.UpdatedAt = now
+}
+
+func (db *DB) userPantryItem(userID, id int) (*models.PantryItem, bool) {
+	p, ok := db.pantryItems[id]
+	if !ok || p.UserID != userID {
+		return nil, false
+	}
+	return p, true
+}
+
+// pantryItem returns a copy of p with its derived fields filled in.
+func (db *DB) pantryItem(p *models.PantryItem) models.PantryItem {
+	item := *p
+	item.LowStock = p.Quantity < p.Threshold
+	item.NextExpiry = nil
+	if batches := db.sortedBatches(p.ID); len(batches) > 0 {
+		next := batches[0].ExpiresOn
+		item.NextExpiry = &next
+	}
+	return item
+}
+
+// pantryItemUsed reports whether meals, recipes or meal plans use the
+// item.
+func (db *DB) pantryItemUsed(id int) bool {
+	for _, m := range db.meals {
+		for _, ing := range m.Ingredients {
+			if ing.PantryItemID == id {
+				return true
+			}
+		}
+	}
+	for _, r := range db.recipes {
+		for _, ing := range r.Ingredients {
+			if ing.PantryItemID == id {
+				return true
+			}
+		}
+	}
+	for _, e := range db.mealPlans {
+		for _, ing := range e.Ingredients {
+			if ing.PantryItemID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// changeUnit mirrors pantry.ChangeUnit: it restates every recorded amount
+// of the item in its new unit, or, when the units do not convert, drops
+// its batches and refuses while anything else holds amounts of it.
+func (db *DB) changeUnit(id int, from, to string, gramsPerItem *float64) error {
+	if from == to {
+		return nil
+	}
+	factor, ok := pantry.Convert(1, from, to, gramsPerItem)
+	if !ok {
+		if db.pantryItemUsed(id) {
+			return pantry.ErrUnitInUse
+		}
+		for _, i := range db.shopping {
+			if i.PantryItemID != nil && *i.PantryItemID == id && i.Quantity != nil {
+				return pantry.ErrUnitInUse
+			}
+		}
+		for batchID, b := range db.batches {
+			if b.PantryItemID == id {
+				delete(db.batches, batchID)
+			}
+		}
+		db.setShoppingUnit(id, to)
+		return nil
+	}
+
+	for _, b := range db.batches {
+		if b.PantryItemID == id {
+			b.Quantity *= factor
+		}
+	}
+	for _, m := range db.meals {
+		for i := range m.Ingredients {
+			if m.Ingredients[i].PantryItemID == id {
+				m.Ingredients[i].QuantityUsed *= factor
+			}
+		}
+	}
+	for _, batches := range db.mealBatches {
+		for i := range batches {
+			if batches[i].pantryItemID == id {
+				batches[i].quantity *= factor
+			}
+		}
+	}
+	for _, r := range db.recipes {
+		for i := range r.Ingredients {
+			if r.Ingredients[i].PantryItemID == id {
+				r.Ingredients[i].Quantity *= factor
+			}
+		}
+	}
+	for _, e := range db.mealPlans {
+		for i := range e.Ingredients {
+			if e.Ingredients[i].PantryItemID == id {
+				e.Ingredients[i].Quantity *= factor
+			}
+		}
+	}
+	for _, i := range db.shopping {
+		if i.PantryItemID != nil && *i.PantryItemID == id && i.Quantity != nil {
+			i.Quantity = ptr(*i.Quantity * factor)
+		}
+	}
+	db.setShoppingUnit(id, to)
+	return nil
+}
+
+// setShoppingUnit sets the unit of the shopping list entries for an item.
+func (db *DB) setShoppingUnit(id int, unit string) {
+	for _, i := range db.shopping {
+		if i.PantryItemID != nil && *i.PantryItemID == id {
+			i.Unit = ptr(unit)
+		}
+	}
+}
+
+func (db *DB) addBatch(pantryItemID int, quantity float64, expiresOn string) {
+	id := db.id()
+	db.batches[id] = &models.PantryBatch{
+		ID:           id,
+		PantryItemID: pantryItemID,
+		Quantity:     quantity,
+		ExpiresOn:    expiresOn,
+		ReceivedAt:   db.Now(),
+	}
+}
+
+// sortedBatches returns the batches of an item, or of every item when
+// pantryItemID is 0, in the order stock is drawn: earliest expiry first,
+// then in the order they were received.
+func (db *DB) sortedBatches(pantryItemID int) []*models.PantryBatch {
+	var batches []*models.PantryBatch
+	for _, b := range db.batches {
+		if pantryItemID == 0 || b.PantryItemID == pantryItemID {
+			batches = append(batches, b)
+		}
+	}
+	sort.Slice(batches, func(i, j int) bool {
+		if batches[i].ExpiresOn != batches[j].ExpiresOn {
+			return batches[i].ExpiresOn < batches[j].ExpiresOn
+		}
+		if !batches[i].ReceivedAt.Equal(batches[j].ReceivedAt) {
+			return batches[i].ReceivedAt.Before(batches[j].ReceivedAt)
+		}
+		return batches[i].ID < batches[j].ID
+	})
+	return batches
+}
+
+// drawBatches takes up to amount from the item's batches with pantry.Draw,
+// deleting the batches it empties, and returns what it took.
+func (db *DB) drawBatches(pantryItemID int, amount float64) []mealBatch {
+	batches := db.sortedBatches(pantryItemID)
+	quantities := make([]float64, len(batches))
+	for i, b := range batches {
+		quantities[i] = b.Quantity
+	}
+	var drawn []mealBatch
+	for i, take := range pantry.Draw(quantities, amount) {
+		b := batches[i]
+		if pantry.Emptied(b.Quantity, take) {
+			delete(db.batches, b.ID)
+		} else {
+			b.Quantity -= take
+		}
+		drawn = append(drawn, mealBatch{pantryItemID: pantryItemID, expiresOn: b.ExpiresOn, quantity: take})
+	}
+	return drawn
+}
+
+// trimBatches draws down the item's batches until they fit within its
+// quantity, as pantry.TrimBatches does.
+func (db *DB) trimBatches(p *models.PantryItem) {
+	excess := -p.Quantity
+	for _, b := range db.batches {
+		if b.PantryItemID == p.ID {
+			excess += b.Quantity
+		}
+	}
+	db.drawBatches(p.ID, excess)
+}
+
+// inStockUnits is pantry.InStockUnits against the in-memory pantry.
+func (db *DB) inStockUnits(userID int, amounts []pantry.Amount) ([]pantry.Amount, error) {
+	items := map[int]pantry.StockItem{}
+	for _, a := range amounts {
+		if p, ok := db.userPantryItem(userID, a.PantryItemID); ok {
+			items[p.ID] = pantry.StockItem{Name: p.Name, Unit: p.Unit, GramsPerItem: p.GramsPerItem}
+		}
+	}
+	return pantry.ToStockUnits(items, amounts)
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+type RecipeStore struct {
+	db *DB
+}
+
+func NewRecipeStore(db *DB) *RecipeStore {
+	return &RecipeStore{db: db}
+}
+
+func (s *RecipeStore) List(ctx context.Context, userID int) ([]models.Recipe, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	list := []models.Recipe{}
+	for _, r := range s.db.recipes {
+		if r.UserID == userID {
+			list = append(list, s.db.recipe(r))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (s *RecipeStore) Get(ctx context.Context, userID, id int) (models.Recipe, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	r, ok := s.db.recipes[id]
+	if !ok || r.UserID != userID {
+		return models.Recipe{}, store.ErrNotFound
+	}
+	return s.db.recipe(r), nil
+}
+
+func (s *RecipeStore) NameTaken(ctx context.Context, userID int, name string, exceptID int) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for _, r := range s.db.recipes {
+		if r.UserID == userID && r.Name == name && r.ID != exceptID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *RecipeStore) Create(ctx context.Context, userID int, input models.RecipeInput) (models.Recipe, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	ingredients, err := s.db.recipeIngredients(userID, input.Ingredients)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+	now := s.db.Now()
+	r := &models.Recipe{
+		ID:          s.db.id(),
+		UserID:      userID,
+		Name:        input.Name,
+		Servings:    input.Servings,
+		Notes:       input.Notes,
+		Ingredients: ingredients,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.db.recipes[r.ID] = r
+	return s.db.recipe(r), nil
+}
+
+func (s *RecipeStore) Update(ctx context.Context, userID, id int, input models.RecipeInput) (models.Recipe, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	r, ok := s.db.recipes[id]
+	if !ok || r.UserID != userID {
+		return models.Recipe{}, store.ErrNotFound
+	}
+	ingredients, err := s.db.recipeIngredients(userID, input.Ingredients)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+	r.Name, r.Servings, r.Notes, r.Ingredients = input.Name, input.Servings, input.Notes, ingredients
+	r.UpdatedAt = s.db.Now()
+	return s.db.recipe(r), nil
+}
+
+func (s *RecipeStore) Delete(ctx context.Context, userID, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	r, ok := s.db.recipes[id]
+	if !ok || r.UserID != userID {
+		return store.ErrNotFound
+	}
+	delete(s.db.recipes, id)
+	for entryID, e := range s.db.mealPlans {
+		if e.RecipeID != nil && *e.RecipeID == id {
+			delete(s.db.mealPlans, entryID)
+		}
+	}
+	return nil
+}
+
+func (s *RecipeStore) Log(ctx context.Context, userID, id int, servings float64, eatenAt time.Time) (models.Meal, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	r, ok := s.db.recipes[id]
+	if !ok || r.UserID != userID {
+		return models.Meal{}, store.ErrNotFound
+	}
+
+	scale := servings / r.Servings
+	ingredients := make([]models.MealIngredientInput, len(r.Ingredients))
+	for i, ing := range r.Ingredients {
+		ingredients[i] = models.MealIngredientInput{
+			PantryItemID: ing.PantryItemID,
+			QuantityUsed: ing.Quantity * scale,
+		}
+	}
+	return s.db.insertMeal(userID, eatenAt, ingredients)
+}
+
+// recipeIngredients converts ingredients into their pantry items' units,
+// folding repeated items into one ingredient.
+func (db *DB) recipeIngredients(userID int, in []models.RecipeIngredientInput) ([]models.RecipeIngredient, error) {
+	amounts := make([]pantry.Amount, len(in))
+	for i, ing := range in {
+		amounts[i] = pantry.Amount{PantryItemID: ing.PantryItemID, Quantity: ing.Quantity, Unit: ing.Unit}
+	}
+	amounts, err := db.inStockUnits(userID, amounts)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.RecipeIngredient, len(amounts))
+	for i, a := range amounts {
+		out[i] = models.RecipeIngredient{PantryItemID: a.PantryItemID, Quantity: a.Quantity}
+	}
+	return out, nil
+}
+
+// recipe returns a copy of r with its ingredients' nutrition worked out
+// from the pantry items' current per-unit values.
+func (db *DB) recipe(r *models.Recipe) models.Recipe {
+	out := *r
+	out.Ingredients = []models.RecipeIngredient{}
+	out.Total = models.Macros{}
+	for _, ing := range r.Ingredients {
+		p := db.pantryItems[ing.PantryItemID]
+		q := ing.Quantity
+		ing.Name, ing.Unit = p.Name, p.Unit
+		ing.Macros = models.Macros{
+			Calories: q * p.CaloriesPerUnit,
+			Protein:  q * p.ProteinPerUnit,
+			Carbs:    q * p.CarbsPerUnit,
+			Fat:      q * p.FatPerUnit,
+			Fiber:    q * p.FiberPerUnit,
+		}
+		out.Ingredients = append(out.Ingredients, ing)
+		out.Total.Calories += ing.Calories
+		out.Total.Protein += ing.Protein
+		out.Total.Carbs += ing.Carbs
+		out.Total.Fat += ing.Fat
+		out.Total.Fiber += ing.Fiber
+	}
+	sort.Slice(out.Ingredients, func(i, j int) bool { return out.Ingredients[i].Name < out.Ingredients[j].Name })
+	out.PerServing = models.Macros{
+		Calories: out.Total.Calories / r.Servings,
+		Protein:  out.Total.Protein / r.Servings,
+		Carbs:    out.Total.Carbs / r.Servings,
+		Fat:      out.Total.Fat / r.Servings,
+		Fiber:    out.Total.Fiber / r.Servings,
+	}
+	return out
+}
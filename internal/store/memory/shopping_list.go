@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+// The shopping list has no usage history to project from, so an outlook
+// adds nothing: rows come from thresholds, the meal plan and items added by
+// hand.
+
+type ShoppingListStore struct {
+	db *DB
+}
+
+func NewShoppingListStore(db *DB) *ShoppingListStore {
+	return &ShoppingListStore{db: db}
+}
+
+func (s *ShoppingListStore) List(ctx context.Context, userID int, plan *pantry.PlanWindow, outlook *pantry.Outlook) ([]models.ShoppingListItem, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	items := []models.ShoppingListItem{}
+	for id, needed := range s.db.needed(userID, plan) {
+		p := s.db.pantryItems[id]
+		item := models.ShoppingListItem{
+			PantryItemID: &p.ID,
+			Name:         p.Name,
+			Quantity:     ptr(p.Threshold + needed - p.Quantity),
+			Unit:         ptr(p.Unit),
+			Source:       "threshold",
+			Checked:      s.db.checked[id],
+		}
+		if needed > 0 {
+			item.Source = "plan"
+			item.Planned = ptr(needed)
+		}
+		items = append(items, item)
+	}
+	for _, i := range s.db.shopping {
+		if i.userID == userID {
+			items = append(items, i.ShoppingListItem)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Checked != items[j].Checked {
+			return !items[i].Checked
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+func (s *ShoppingListStore) Add(ctx context.Context, userID int, input models.ShoppingListItemInput) (models.ShoppingListItem, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	id := s.db.id()
+	item := &shoppingItem{userID: userID, ShoppingListItem: models.ShoppingListItem{
+		ItemID:       &id,
+		PantryItemID: input.PantryItemID,
+		Name:         input.Name,
+		Quantity:     input.Quantity,
+		Unit:         input.Unit,
+		Source:       "manual",
+	}}
+	s.db.shopping[id] = item
+	return item.ShoppingListItem, nil
+}
+
+func (s *ShoppingListStore) Check(ctx context.Context, userID, id int, checked bool) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	item, ok := s.db.shopping[id]
+	if !ok || item.userID != userID {
+		return store.ErrNotFound
+	}
+	item.Checked = checked
+	return nil
+}
+
+func (s *ShoppingListStore) CheckPantryItem(ctx context.Context, userID, pantryItemID int, checked bool) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.userPantryItem(userID, pantryItemID); !ok {
+		return store.ErrNotFound
+	}
+	if checked {
+		s.db.checked[pantryItemID] = true
+	} else {
+		delete(s.db.checked, pantryItemID)
+	}
+	return nil
+}
+
+func (s *ShoppingListStore) Delete(ctx context.Context, userID, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	item, ok := s.db.shopping[id]
+	if !ok || item.userID != userID {
+		return store.ErrNotFound
+	}
+	delete(s.db.shopping, id)
+	return nil
+}
+
+func (s *ShoppingListStore) Purchase(ctx context.Context, userID int, plan *pantry.PlanWindow, outlook *pantry.Outlook) (models.ShoppingListPurchase, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	now := s.db.Now()
+	restocked := map[int]bool{}
+	for _, i := range s.db.shopping {
+		if i.userID != userID || !i.Checked || i.PantryItemID == nil || i.Quantity == nil {
+			continue
+		}
+		if p, ok := s.db.userPantryItem(userID, *i.PantryItemID); ok {
+			p.Quantity += *i.Quantity
+			p.UpdatedAt = now
+			restocked[p.ID] = true
+		}
+	}
+
+	planned := map[int]float64{}
+	if plan != nil {
+		planned = s.db.planned(userID, *plan)
+	}
+	for id := range s.db.checked {
+		p, ok := s.db.userPantryItem(userID, id)
+		if !ok {
+			continue
+		}
+		if target := p.Threshold + planned[id]; p.Quantity < target {
+			p.Quantity = target
+			p.UpdatedAt = now
+			restocked[id] = true
+		}
+		delete(s.db.checked, id)
+	}
+
+	var removed int64
+	for id, i := range s.db.shopping {
+		if i.userID == userID && i.Checked {
+			delete(s.db.shopping, id)
+			removed++
+		}
+	}
+
+	result := models.ShoppingListPurchase{Restocked: []models.PantryItem{}, ItemsRemoved: removed}
+	for id := range restocked {
+		result.Restocked = append(result.Restocked, s.db.pantryItem(s.db.pantryItems[id]))
+	}
+	sort.Slice(result.Restocked, func(i, j int) bool { return result.Restocked[i].Name < result.Restocked[j].Name })
+	return result, nil
+}
+
+// needed returns, for each of the user's pantry items that should be
+// bought, how much of it the meal plan uses over the window.
+func (db *DB) needed(userID int, plan *pantry.PlanWindow) map[int]float64 {
+	planned := map[int]float64{}
+	if plan != nil {
+		planned = db.planned(userID, *plan)
+	}
+	needed := map[int]float64{}
+	for _, p := range db.pantryItems {
+		if p.UserID == userID && p.Quantity < p.Threshold+planned[p.ID] {
+			needed[p.ID] = planned[p.ID]
+		}
+	}
+	return needed
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,213 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+type UserStore struct {
+	db *DB
+}
+
+func NewUserStore(db *DB) *UserStore {
+	return &UserStore{db: db}
+}
+
+func (s *UserStore) List(ctx context.Context) ([]models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	users := []models.User{}
+	for _, u := range s.db.users {
+		users = append(users, u.User)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+func (s *UserStore) Get(ctx context.Context, id int) (models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	u, ok := s.db.users[id]
+	if !ok {
+		return models.User{}, store.ErrNotFound
+	}
+	return u.User, nil
+}
+
+func (s *UserStore) Exists(ctx context.Context, id int) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	_, ok := s.db.users[id]
+	return ok, nil
+}
+
+func (s *UserStore) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for _, u := range s.db.users {
+		if strings.EqualFold(u.Email, email) {
+			return u.User, nil
+		}
+	}
+	return models.User{}, store.ErrNotFound
+}
+
+func (s *UserStore) Create(ctx context.Context, email, passwordHash string) (models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	now := s.db.Now()
+	u := &user{User: models.User{
+		ID:           s.db.id(),
+		Email:        email,
+		PasswordHash: passwordHash,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}}
+	s.db.users[u.ID] = u
+	return u.User, nil
+}
+
+func (s *UserStore) EmailTaken(ctx context.Context, email string, exceptID int) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for _, u := range s.db.users {
+		if u.ID != exceptID && strings.EqualFold(u.Email, email) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *UserStore) UpdateEmail(ctx context.Context, id int, email string) (models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	u, ok := s.db.users[id]
+	if !ok {
+		return models.User{}, store.ErrNotFound
+	}
+	u.Email = email
+	u.UpdatedAt = s.db.Now()
+	return u.User, nil
+}
+
+func (s *UserStore) PasswordHash(ctx context.Context, id int) (string, error) {
+	u, err := s.Get(ctx, id)
+	return u.PasswordHash, err
+}
+
+func (s *UserStore) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	u, ok := s.db.users[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	u.PasswordHash = passwordHash
+	u.UpdatedAt = s.db.Now()
+	return nil
+}
+
+func (s *UserStore) SetDisabled(ctx context.Context, id int, disabled bool) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	u, ok := s.db.users[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	u.DisabledAt = nil
+	if disabled {
+		now := s.db.Now()
+		u.DisabledAt = &now
+	}
+	return nil
+}
+
+func (s *UserStore) ScheduleDeletion(ctx context.Context, id int, grace time.Duration) (models.AccountDeletionStatus, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	u, ok := s.db.users[id]
+	if !ok {
+		return models.AccountDeletionStatus{}, store.ErrNotFound
+	}
+	if !u.deletion.Pending {
+		now := s.db.Now()
+		scheduled := now.Add(grace)
+		u.deletion = models.AccountDeletionStatus{UserID: id, Pending: true, RequestedAt: &now, ScheduledFor: &scheduled}
+	}
+	return u.deletion, nil
+}
+
+func (s *UserStore) DeletionStatus(ctx context.Context, id int) (models.AccountDeletionStatus, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	u, ok := s.db.users[id]
+	if !ok {
+		return models.AccountDeletionStatus{}, store.ErrNotFound
+	}
+	status := u.deletion
+	status.UserID = id
+	return status, nil
+}
+
+func (s *UserStore) CancelDeletion(ctx context.Context, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	u, ok := s.db.users[id]
+	if !ok || !u.deletion.Pending {
+		return store.ErrNotFound
+	}
+	u.deletion = models.AccountDeletionStatus{}
+	return nil
+}
+
+func (s *UserStore) Timezone(ctx context.Context, id int) (string, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if p, ok := s.db.profiles[id]; ok {
+		return p.Timezone, nil
+	}
+	return "UTC", nil
+}
+
+type ProfileStore struct {
+	db *DB
+}
+
+func NewProfileStore(db *DB) *ProfileStore {
+	return &ProfileStore{db: db}
+}
+
+func (s *ProfileStore) Get(ctx context.Context, userID int) (models.UserProfile, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	u, ok := s.db.users[userID]
+	if !ok {
+		return models.UserProfile{}, store.ErrNotFound
+	}
+	if p, ok := s.db.profiles[userID]; ok {
+		return p, nil
+	}
+	return models.UserProfile{UserID: userID, PreferredUnits: "metric", Timezone: "UTC", UpdatedAt: u.UpdatedAt}, nil
+}
+
+func (s *ProfileStore) Save(ctx context.Context, userID int, input models.UserProfileInput) (models.UserProfile, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	p := models.UserProfile{
+		UserID:         userID,
+		BodyweightKg:   input.BodyweightKg,
+		HeightCm:       input.HeightCm,
+		BirthYear:      input.BirthYear,
+		Sex:            input.Sex,
+		PreferredUnits: input.PreferredUnits,
+		Timezone:       input.Timezone,
+		UpdatedAt:      s.db.Now(),
+	}
+	s.db.profiles[userID] = p
+	return p, nil
+}
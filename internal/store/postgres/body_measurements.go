@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/analytics"
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+const bodyMeasurementColumns = `
+		id, user_id, measured_at, bodyweight_kg, body_fat_pct, waist_cm, arms_cm, chest_cm, notes, created_at`
+
+func scanBodyMeasurement(row interface{ Scan(...any) error }, m *models.BodyMeasurement) error {
+	return row.Scan(
+		&m.ID,
+		&m.UserID,
+		&m.MeasuredAt,
+		&m.BodyweightKg,
+		&m.BodyFatPct,
+		&m.WaistCm,
+		&m.ArmsCm,
+		&m.ChestCm,
+		&m.Notes,
+		&m.CreatedAt,
+	)
+}
+
+type BodyMeasurementStore struct {
+	db *sql.DB
+}
+
+func NewBodyMeasurementStore(db *sql.DB) *BodyMeasurementStore {
+	return &BodyMeasurementStore{db: db}
+}
+
+func (s *BodyMeasurementStore) List(ctx context.Context, userID int, from, until time.Time) ([]models.BodyMeasurement, error) {
+	defer metrics.ObserveQuery("body_measurements", "List")()
+	rows, err := s.db.QueryContext(ctx, `
+			SELECT`+bodyMeasurementColumns+`
+			FROM body_measurements
+			WHERE user_id = $1 AND measured_at >= $2 AND measured_at < $3
+			ORDER BY measured_at
+	`, userID, from.UTC(), until.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.BodyMeasurement{}
+	for rows.Next() {
+		var m models.BodyMeasurement
+		if err := scanBodyMeasurement(rows, &m); err != nil {
+			return nil, err
+		}
+		entries = append(entries, m)
+	}
+	return entries, rows.Err()
+}
+
+func (s *BodyMeasurementStore) Create(ctx context.Context, userID int, input models.BodyMeasurementInput) (models.BodyMeasurement, error) {
+	defer metrics.ObserveQuery("body_measurements", "Create")()
+	query := `
+			INSERT INTO body_measurements
+			(user_id, measured_at, bodyweight_kg, body_fat_pct, waist_cm, arms_cm, chest_cm, notes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING` + bodyMeasurementColumns
+
+	var m models.BodyMeasurement
+	err := scanBodyMeasurement(s.db.QueryRowContext(ctx,
+		query,
+		userID,
+		input.MeasuredAt.UTC(),
+		input.BodyweightKg,
+		input.BodyFatPct,
+		input.WaistCm,
+		input.ArmsCm,
+		input.ChestCm,
+		input.Notes,
+	), &m)
+	return m, err
+}
+
+func (s *BodyMeasurementStore) Update(ctx context.Context, userID, id int, input models.BodyMeasurementInput) (models.BodyMeasurement, error) {
+	defer metrics.ObserveQuery("body_measurements", "Update")()
+	query := `
+			UPDATE body_measurements
+			SET measured_at = $3, bodyweight_kg = $4, body_fat_pct = $5, waist_cm = $6, arms_cm = $7, chest_cm = $8, notes = $9
+			WHERE id = $1 AND user_id = $2
+			RETURNING` + bodyMeasurementColumns
+
+	var m models.BodyMeasurement
+	err := scanBodyMeasurement(s.db.QueryRowContext(ctx,
+		query,
+		id,
+		userID,
+		input.MeasuredAt.UTC(),
+		input.BodyweightKg,
+		input.BodyFatPct,
+		input.WaistCm,
+		input.ArmsCm,
+		input.ChestCm,
+		input.Notes,
+	), &m)
+	return m, notFound(err)
+}
+
+func (s *BodyMeasurementStore) Delete(ctx context.Context, userID, id int) error {
+	defer metrics.ObserveQuery("body_measurements", "Delete")()
+	return affected(s.db.ExecContext(ctx, "DELETE FROM body_measurements WHERE id = $1 AND user_id = $2", id, userID))
+}
+
+func (s *BodyMeasurementStore) Bodyweights(ctx context.Context, userID int, until time.Time) ([]analytics.Sample, error) {
+	defer metrics.ObserveQuery("body_measurements", "Bodyweights")()
+	rows, err := s.db.QueryContext(ctx, `
+			SELECT measured_at, bodyweight_kg
+			FROM body_measurements
+			WHERE user_id = $1 AND bodyweight_kg IS NOT NULL AND measured_at < $2
+			ORDER BY measured_at
+	`, userID, until.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []analytics.Sample
+	for rows.Next() {
+		var s analytics.Sample
+		if err := rows.Scan(&s.At, &s.Value); err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
+
+func (s *BodyMeasurementStore) TrainingWeeks(ctx context.Context, userID, exerciseID int, tz string, from, until time.Time) (map[string]models.BodyweightWeek, error) {
+	defer metrics.ObserveQuery("body_measurements", "TrainingWeeks")()
+	rows, err := s.db.QueryContext(ctx, `
+			SELECT
+					date_trunc('week', ws.created_at AT TIME ZONE 'UTC' AT TIME ZONE $2)::date AS week,
+					COUNT(DISTINCT ws.id),
+					COALESCE(SUM(we.sets), 0),
+					COALESCE(SUM(we.weight * we.reps * we.sets), 0)
+			FROM workout_sessions ws
+			LEFT JOIN workout_exercises we ON we.workout_session_id = ws.id
+			WHERE ws.user_id = $1 AND ws.created_at >= $3 AND ws.created_at < $4
+			GROUP BY week
+	`, userID, tz, from.UTC(), until.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	weeks := map[string]models.BodyweightWeek{}
+	for rows.Next() {
+		var week time.Time
+		var w models.BodyweightWeek
+		if err := rows.Scan(&week, &w.Sessions, &w.Sets, &w.Volume); err != nil {
+			return nil, err
+		}
+		weeks[week.Format("2006-01-02")] = w
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if exerciseID == 0 {
+		return weeks, nil
+	}
+
+	rows, err = s.db.QueryContext(ctx, `
+			SELECT date_trunc('week', ws.created_at AT TIME ZONE 'UTC' AT TIME ZONE $2)::date AS week, we.weight, we.reps
+			FROM workout_sessions ws
+			JOIN workout_exercises we ON we.workout_session_id = ws.id
+			WHERE ws.user_id = $1 AND we.exercise_id = $3 AND ws.created_at >= $4 AND ws.created_at < $5
+	`, userID, tz, exerciseID, from.UTC(), until.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var week time.Time
+		var weight float64
+		var reps int
+		if err := rows.Scan(&week, &weight, &reps); err != nil {
+			return nil, err
+		}
+		key := week.Format("2006-01-02")
+		w := weeks[key]
+		if e1rm := analytics.EstimatedOneRepMax(weight, reps); w.BestEstimated1RM == nil || e1rm > *w.BestEstimated1RM {
+			w.BestEstimated1RM = &e1rm
+		}
+		weeks[key] = w
+	}
+	return weeks, rows.Err()
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+const gymEquipmentQuery = `
+		SELECT ge.id, ge.gym_id, ge.equipment_type_id, et.name AS equipment_name, ge.weight, ge.notes
+		FROM gym_equipment ge
+		JOIN equipment_types et ON ge.equipment_type_id = et.id`
+
+func scanGymEquipment(row interface{ Scan(...any) error }) (models.GymEquipmentWithDetails, error) {
+	var e models.GymEquipmentWithDetails
+	err := row.Scan(&e.ID, &e.GymID, &e.EquipmentTypeID, &e.EquipmentName, &e.Weight, &e.Notes)
+	return e, err
+}
+
+type EquipmentStore struct {
+	db *sql.DB
+}
+
+func NewEquipmentStore(db *sql.DB) *EquipmentStore {
+	return &EquipmentStore{db: db}
+}
+
+func (s *EquipmentStore) ListByGym(ctx context.Context, gymID int) ([]models.GymEquipmentWithDetails, error) {
+	defer metrics.ObserveQuery("equipment", "ListByGym")()
+	rows, err := s.db.QueryContext(ctx, gymEquipmentQuery+" WHERE ge.gym_id = $1 ORDER BY ge.id", gymID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	equipment := []models.GymEquipmentWithDetails{}
+	for rows.Next() {
+		e, err := scanGymEquipment(rows)
+		if err != nil {
+			return nil, err
+		}
+		equipment = append(equipment, e)
+	}
+	return equipment, rows.Err()
+}
+
+func (s *EquipmentStore) Get(ctx context.Context, id int) (models.GymEquipmentWithDetails, error) {
+	defer metrics.ObserveQuery("equipment", "Get")()
+	e, err := scanGymEquipment(s.db.QueryRowContext(ctx, gymEquipmentQuery+" WHERE ge.id = $1", id))
+	return e, notFound(err)
+}
+
+func (s *EquipmentStore) Exists(ctx context.Context, id int) (bool, error) {
+	defer metrics.ObserveQuery("equipment", "Exists")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM gym_equipment WHERE id = $1)", id)
+}
+
+func (s *EquipmentStore) Create(ctx context.Context, gymID int, input models.GymEquipmentInput) (models.GymEquipment, error) {
+	defer metrics.ObserveQuery("equipment", "Create")()
+	e := models.GymEquipment{
+		GymID:           gymID,
+		EquipmentTypeID: input.EquipmentTypeID,
+		Weight:          input.Weight,
+		Notes:           input.Notes,
+	}
+	err := s.db.QueryRowContext(ctx,
+		"INSERT INTO gym_equipment (gym_id, equipment_type_id, weight, notes) VALUES ($1, $2, $3, $4) RETURNING id",
+		gymID, input.EquipmentTypeID, input.Weight, input.Notes,
+	).Scan(&e.ID)
+	return e, err
+}
+
+func (s *EquipmentStore) Update(ctx context.Context, id int, input models.GymEquipmentInput) (models.GymEquipmentWithDetails, error) {
+	defer metrics.ObserveQuery("equipment", "Update")()
+	err := affected(s.db.ExecContext(ctx,
+		"UPDATE gym_equipment SET equipment_type_id = $2, weight = $3, notes = $4 WHERE id = $1",
+		id, input.EquipmentTypeID, input.Weight, input.Notes,
+	))
+	if err != nil {
+		return models.GymEquipmentWithDetails{}, err
+	}
+	return s.Get(ctx, id)
+}
+
+func (s *EquipmentStore) InUse(ctx context.Context, id int) (bool, error) {
+	defer metrics.ObserveQuery("equipment", "InUse")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM workout_exercises WHERE gym_equipment_id = $1)", id)
+}
+
+func (s *EquipmentStore) Delete(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery("equipment", "Delete")()
+	return affected(s.db.ExecContext(ctx, "DELETE FROM gym_equipment WHERE id = $1", id))
+}
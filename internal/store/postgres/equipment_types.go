@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+type EquipmentTypeStore struct {
+	db *sql.DB
+}
+
+func NewEquipmentTypeStore(db *sql.DB) *EquipmentTypeStore {
+	return &EquipmentTypeStore{db: db}
+}
+
+func (s *EquipmentTypeStore) List(ctx context.Context) ([]models.EquipmentType, error) {
+	defer metrics.ObserveQuery("equipment_types", "List")()
+	rows, err := s.db.QueryContext(ctx, "SELECT id, name FROM equipment_types ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	equipmentTypes := []models.EquipmentType{}
+	for rows.Next() {
+		var t models.EquipmentType
+		if err := rows.Scan(&t.ID, &t.Name); err != nil {
+			return nil, err
+		}
+		equipmentTypes = append(equipmentTypes, t)
+	}
+	return equipmentTypes, rows.Err()
+}
+
+func (s *EquipmentTypeStore) Get(ctx context.Context, id int) (models.EquipmentType, error) {
+	defer metrics.ObserveQuery("equipment_types", "Get")()
+	var t models.EquipmentType
+	err := s.db.QueryRowContext(ctx, "SELECT id, name FROM equipment_types WHERE id = $1", id).Scan(&t.ID, &t.Name)
+	return t, notFound(err)
+}
+
+func (s *EquipmentTypeStore) Exists(ctx context.Context, id int) (bool, error) {
+	defer metrics.ObserveQuery("equipment_types", "Exists")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM equipment_types WHERE id = $1)", id)
+}
+
+func (s *EquipmentTypeStore) NameTaken(ctx context.Context, name string, exceptID int) (bool, error) {
+	defer metrics.ObserveQuery("equipment_types", "NameTaken")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM equipment_types WHERE name = $1 AND id != $2)", name, exceptID)
+}
+
+func (s *EquipmentTypeStore) Create(ctx context.Context, name string) (models.EquipmentType, error) {
+	defer metrics.ObserveQuery("equipment_types", "Create")()
+	t := models.EquipmentType{Name: name}
+	err := s.db.QueryRowContext(ctx, "INSERT INTO equipment_types (name) VALUES ($1) RETURNING id", name).Scan(&t.ID)
+	return t, err
+}
+
+func (s *EquipmentTypeStore) Update(ctx context.Context, id int, name string) (models.EquipmentType, error) {
+	defer metrics.ObserveQuery("equipment_types", "Update")()
+	err := affected(s.db.ExecContext(ctx, "UPDATE equipment_types SET name = $2 WHERE id = $1", id, name))
+	return models.EquipmentType{ID: id, Name: name}, err
+}
+
+func (s *EquipmentTypeStore) InUse(ctx context.Context, id int) (bool, error) {
+	defer metrics.ObserveQuery("equipment_types", "InUse")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM gym_equipment WHERE equipment_type_id = $1)", id)
+}
+
+func (s *EquipmentTypeStore) Delete(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery("equipment_types", "Delete")()
+	return affected(s.db.ExecContext(ctx, "DELETE FROM equipment_types WHERE id = $1", id))
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+type ExerciseStore struct {
+	db *sql.DB
+}
+
+func NewExerciseStore(db *sql.DB) *ExerciseStore {
+	return &ExerciseStore{db: db}
+}
+
+func (s *ExerciseStore) List(ctx context.Context) ([]models.Exercise, error) {
+	defer metrics.ObserveQuery("exercises", "List")()
+	rows, err := s.db.QueryContext(ctx, "SELECT id, name FROM exercises ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exercises := []models.Exercise{}
+	for rows.Next() {
+		var e models.Exercise
+		if err := rows.Scan(&e.ID, &e.Name); err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, e)
+	}
+	return exercises, rows.Err()
+}
+
+func (s *ExerciseStore) Exists(ctx context.Context, id int) (bool, error) {
+	defer metrics.ObserveQuery("exercises", "Exists")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM exercises WHERE id = $1)", id)
+}
+
+func (s *ExerciseStore) NameTaken(ctx context.Context, name string, exceptID int) (bool, error) {
+	defer metrics.ObserveQuery("exercises", "NameTaken")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM exercises WHERE name = $1 AND id != $2)", name, exceptID)
+}
+
+func (s *ExerciseStore) Create(ctx context.Context, name string) (models.Exercise, error) {
+	defer metrics.ObserveQuery("exercises", "Create")()
+	e := models.Exercise{Name: name}
+	err := s.db.QueryRowContext(ctx, "INSERT INTO exercises (name) VALUES ($1) RETURNING id", name).Scan(&e.ID)
+	return e, err
+}
+
+func (s *ExerciseStore) Update(ctx context.Context, id int, name string) (models.Exercise, error) {
+	defer metrics.ObserveQuery("exercises", "Update")()
+	err := affected(s.db.ExecContext(ctx, "UPDATE exercises SET name = $2 WHERE id = $1", id, name))
+	return models.Exercise{ID: id, Name: name}, err
+}
+
+func (s *ExerciseStore) InUse(ctx context.Context, id int) (bool, error) {
+	defer metrics.ObserveQuery("exercises", "InUse")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM workout_exercises WHERE exercise_id = $1)", id)
+}
+
+func (s *ExerciseStore) Delete(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery("exercises", "Delete")()
+	return affected(s.db.ExecContext(ctx, "DELETE FROM exercises WHERE id = $1", id))
+}
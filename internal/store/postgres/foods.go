@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+const foodColumns = `
+		id, source, source_id, barcode, name, brand, basis_unit, serving_size,
+		calories_per_100, protein_per_100, carbs_per_100, fat_per_100, fiber_per_100, updated_at`
+
+func scanFood(row interface{ Scan(...any) error }, f *models.Food) error {
+	return row.Scan(
+		&f.ID,
+		&f.Source,
+		&f.SourceID,
+		&f.Barcode,
+		&f.Name,
+		&f.Brand,
+		&f.BasisUnit,
+		&f.ServingSize,
+		&f.CaloriesPer100,
+		&f.ProteinPer100,
+		&f.CarbsPer100,
+		&f.FatPer100,
+		&f.FiberPer100,
+		&f.UpdatedAt,
+	)
+}
+
+type FoodStore struct {
+	db *sql.DB
+}
+
+func NewFoodStore(db *sql.DB) *FoodStore {
+	return &FoodStore{db: db}
+}
+
+func (s *FoodStore) Search(ctx context.Context, query string, limit int) ([]models.Food, error) {
+	defer metrics.ObserveQuery("foods", "Search")()
+	rows, err := s.db.QueryContext(ctx, `
+			SELECT`+foodColumns+`
+			FROM foods
+			WHERE search_vector @@ to_tsquery('simple', $1)
+			ORDER BY ts_rank(search_vector, to_tsquery('simple', $1)) DESC, name
+			LIMIT $2`, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.Food{}
+	for rows.Next() {
+		var f models.Food
+		if err := scanFood(rows, &f); err != nil {
+			return nil, err
+		}
+		results = append(results, f)
+	}
+	return results, rows.Err()
+}
+
+func (s *FoodStore) Get(ctx context.Context, id int) (models.Food, error) {
+	defer metrics.ObserveQuery("foods", "Get")()
+	var f models.Food
+	err := scanFood(s.db.QueryRowContext(ctx, "SELECT"+foodColumns+" FROM foods WHERE id = $1", id), &f)
+	return f, notFound(err)
+}
+
+func (s *FoodStore) FindByBarcode(ctx context.Context, code string) (models.Food, error) {
+	defer metrics.ObserveQuery("foods", "FindByBarcode")()
+	var f models.Food
+	err := scanFood(s.db.QueryRowContext(ctx, `
+			SELECT`+foodColumns+`
+			FROM foods
+			WHERE barcode = $1
+			ORDER BY updated_at DESC, id DESC
+			LIMIT 1`, code), &f)
+	return f, notFound(err)
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+type GymStore struct {
+	db *sql.DB
+}
+
+func NewGymStore(db *sql.DB) *GymStore {
+	return &GymStore{db: db}
+}
+
+func (s *GymStore) List(ctx context.Context) ([]models.Gym, error) {
+	defer metrics.ObserveQuery("gyms", "List")()
+	return s.query(ctx, "SELECT id, user_id, name, created_at FROM gyms")
+}
+
+func (s *GymStore) ListByUser(ctx context.Context, userID int) ([]models.Gym, error) {
+	defer metrics.ObserveQuery("gyms", "ListByUser")()
+	return s.query(ctx, "SELECT id, user_id, name, created_at FROM gyms WHERE user_id = $1", userID)
+}
+
+func (s *GymStore) query(ctx context.Context, query string, args ...any) ([]models.Gym, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	gyms := []models.Gym{}
+	for rows.Next() {
+		var gym models.Gym
+		if err := rows.Scan(&gym.ID, &gym.UserID, &gym.Name, &gym.CreatedAt); err != nil {
+			return nil, err
+		}
+		gyms = append(gyms, gym)
+	}
+	return gyms, rows.Err()
+}
+
+func (s *GymStore) Get(ctx context.Context, id int) (models.Gym, error) {
+	defer metrics.ObserveQuery("gyms", "Get")()
+	var gym models.Gym
+	err := s.db.QueryRowContext(ctx, "SELECT id, user_id, name, created_at FROM gyms WHERE id = $1", id).Scan(
+		&gym.ID, &gym.UserID, &gym.Name, &gym.CreatedAt)
+	return gym, notFound(err)
+}
+
+func (s *GymStore) Create(ctx context.Context, gym models.Gym) (models.Gym, error) {
+	defer metrics.ObserveQuery("gyms", "Create")()
+	err := s.db.QueryRowContext(ctx,
+		"INSERT INTO gyms (user_id, name) VALUES ($1, $2) RETURNING id, created_at",
+		gym.UserID, gym.Name,
+	).Scan(&gym.ID, &gym.CreatedAt)
+	return gym, err
+}
+
+func (s *GymStore) Update(ctx context.Context, id int, gym models.Gym) (models.Gym, error) {
+	defer metrics.ObserveQuery("gyms", "Update")()
+	err := s.db.QueryRowContext(ctx,
+		"UPDATE gyms SET user_id = $2, name = $3 WHERE id = $1 RETURNING id, user_id, name, created_at",
+		id, gym.UserID, gym.Name,
+	).Scan(&gym.ID, &gym.UserID, &gym.Name, &gym.CreatedAt)
+	return gym, notFound(err)
+}
+
+func (s *GymStore) Delete(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery("gyms", "Delete")()
+	return affected(s.db.ExecContext(ctx, "DELETE FROM gyms WHERE id = $1", id))
+}
@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/lib/pq"
+)
+
+const mealPlanEntryColumns = `
+		e.id, e.user_id, e.planned_date, e.recipe_id, r.name, e.servings, e.notes, e.created_at`
+
+type MealPlanStore struct {
+	db *sql.DB
+}
+
+func NewMealPlanStore(db *sql.DB) *MealPlanStore {
+	return &MealPlanStore{db: db}
+}
+
+func (s *MealPlanStore) List(ctx context.Context, userID int, from, to string) ([]models.MealPlanEntry, error) {
+	defer metrics.ObserveQuery("meal_plan", "List")()
+	return mealPlanEntries(ctx, s.db, `
+			SELECT`+mealPlanEntryColumns+`
+			FROM meal_plan_entries e
+			LEFT JOIN recipes r ON r.id = e.recipe_id
+			WHERE e.user_id = $1 AND e.planned_date BETWEEN $2 AND $3
+			ORDER BY e.planned_date, e.id`, userID, from, to)
+}
+
+func (s *MealPlanStore) Get(ctx context.Context, userID, id int) (models.MealPlanEntry, error) {
+	defer metrics.ObserveQuery("meal_plan", "Get")()
+	return mealPlanEntry(ctx, s.db, userID, id)
+}
+
+func (s *MealPlanStore) Create(ctx context.Context, userID int, input models.MealPlanEntryInput) (models.MealPlanEntry, error) {
+	defer metrics.ObserveQuery("meal_plan", "Create")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.MealPlanEntry{}, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `
+			INSERT INTO meal_plan_entries (user_id, planned_date, recipe_id, servings, notes)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id`,
+		userID, input.Date, input.RecipeID, input.Servings, input.Notes,
+	).Scan(&id)
+	if err != nil {
+		return models.MealPlanEntry{}, err
+	}
+
+	if err := setMealPlanIngredients(ctx, tx, userID, id, input.Ingredients); err != nil {
+		return models.MealPlanEntry{}, err
+	}
+
+	e, err := mealPlanEntry(ctx, tx, userID, id)
+	if err != nil {
+		return e, err
+	}
+	return e, tx.Commit()
+}
+
+func (s *MealPlanStore) Update(ctx context.Context, userID, id int, input models.MealPlanEntryInput) (models.MealPlanEntry, error) {
+	defer metrics.ObserveQuery("meal_plan", "Update")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.MealPlanEntry{}, err
+	}
+	defer tx.Rollback()
+
+	err = affected(tx.ExecContext(ctx, `
+			UPDATE meal_plan_entries
+			SET planned_date = $3, recipe_id = $4, servings = $5, notes = $6
+			WHERE id = $1 AND user_id = $2`,
+		id, userID, input.Date, input.RecipeID, input.Servings, input.Notes,
+	))
+	if err != nil {
+		return models.MealPlanEntry{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM meal_plan_ingredients WHERE entry_id = $1", id); err != nil {
+		return models.MealPlanEntry{}, err
+	}
+	if err := setMealPlanIngredients(ctx, tx, userID, id, input.Ingredients); err != nil {
+		return models.MealPlanEntry{}, err
+	}
+
+	e, err := mealPlanEntry(ctx, tx, userID, id)
+	if err != nil {
+		return e, err
+	}
+	return e, tx.Commit()
+}
+
+func (s *MealPlanStore) Delete(ctx context.Context, userID, id int) error {
+	defer metrics.ObserveQuery("meal_plan", "Delete")()
+	return affected(s.db.ExecContext(ctx, "DELETE FROM meal_plan_entries WHERE id = $1 AND user_id = $2", id, userID))
+}
+
+func (s *MealPlanStore) Requirements(ctx context.Context, userID int, plan pantry.PlanWindow) ([]models.MealPlanRequirement, error) {
+	defer metrics.ObserveQuery("meal_plan", "Requirements")()
+	return pantry.PlanRequirements(ctx, s.db, userID, plan)
+}
+
+// setMealPlanIngredients records the ingredients of an entry planned without
+// a recipe in their pantry items' units.
+func setMealPlanIngredients(ctx context.Context, tx *sql.Tx, userID, entryID int, in []models.RecipeIngredientInput) error {
+	if len(in) == 0 {
+		return nil
+	}
+	ingredients, err := recipeIngredientsInStockUnits(ctx, tx, userID, in)
+	if err != nil {
+		return err
+	}
+
+	for _, ing := range ingredients {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO meal_plan_ingredients (entry_id, pantry_item_id, quantity) VALUES ($1, $2, $3)",
+			entryID, ing.PantryItemID, ing.Quantity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mealPlanEntry(ctx context.Context, q queryer, userID, id int) (models.MealPlanEntry, error) {
+	entries, err := mealPlanEntries(ctx, q, `
+			SELECT`+mealPlanEntryColumns+`
+			FROM meal_plan_entries e
+			LEFT JOIN recipes r ON r.id = e.recipe_id
+			WHERE e.user_id = $1 AND e.id = $2`, userID, id)
+	if err != nil {
+		return models.MealPlanEntry{}, err
+	}
+	if len(entries) == 0 {
+		return models.MealPlanEntry{}, store.ErrNotFound
+	}
+	return entries[0], nil
+}
+
+// mealPlanEntries runs query, which must select mealPlanEntryColumns, and
+// fills in the pantry items each entry uses, scaling recipe ingredients to
+// the planned servings.
+func mealPlanEntries(ctx context.Context, q queryer, query string, args ...any) ([]models.MealPlanEntry, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	entries := []models.MealPlanEntry{}
+	index := map[int]int{}
+	var ids []int64
+	for rows.Next() {
+		e := models.MealPlanEntry{Ingredients: []models.MealPlanIngredient{}}
+		var date time.Time
+		err := rows.Scan(&e.ID, &e.UserID, &date, &e.RecipeID, &e.RecipeName, &e.Servings, &e.Notes, &e.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		e.Date = date.Format("2006-01-02")
+		index[e.ID] = len(entries)
+		ids = append(ids, int64(e.ID))
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	rows, err = q.QueryContext(ctx, `
+			SELECT e.id, ri.pantry_item_id, p.name, p.unit, ri.quantity * e.servings / r.servings
+			FROM meal_plan_entries e
+			JOIN recipes r ON r.id = e.recipe_id
+			JOIN recipe_ingredients ri ON ri.recipe_id = r.id
+			JOIN pantry_items p ON p.id = ri.pantry_item_id
+			WHERE e.id = ANY($1)
+			UNION ALL
+			SELECT mi.entry_id, mi.pantry_item_id, p.name, p.unit, mi.quantity
+			FROM meal_plan_ingredients mi
+			JOIN pantry_items p ON p.id = mi.pantry_item_id
+			WHERE mi.entry_id = ANY($1)
+			ORDER BY 1, 3`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entryID int
+		var ing models.MealPlanIngredient
+		if err := rows.Scan(&entryID, &ing.PantryItemID, &ing.Name, &ing.Unit, &ing.Quantity); err != nil {
+			return nil, err
+		}
+		e := &entries[index[entryID]]
+		e.Ingredients = append(e.Ingredients, ing)
+	}
+	return entries, rows.Err()
+}
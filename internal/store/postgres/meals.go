@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/lib/pq"
+)
+
+type MealStore struct {
+	db *sql.DB
+}
+
+func NewMealStore(db *sql.DB) *MealStore {
+	return &MealStore{db: db}
+}
+
+func (s *MealStore) List(ctx context.Context, userID int, from, until time.Time) ([]models.Meal, error) {
+	defer metrics.ObserveQuery("meals", "List")()
+	return meals(ctx, s.db, `
+			SELECT id, user_id, created_at
+			FROM meals
+			WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+			ORDER BY created_at, id`,
+		userID, from.UTC(), until.UTC(),
+	)
+}
+
+func (s *MealStore) Get(ctx context.Context, userID, id int) (models.Meal, error) {
+	defer metrics.ObserveQuery("meals", "Get")()
+	return meal(ctx, s.db, userID, id)
+}
+
+func (s *MealStore) Create(ctx context.Context, userID int, eatenAt time.Time, ingredients []models.MealIngredientInput) (models.Meal, error) {
+	defer metrics.ObserveQuery("meals", "Create")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Meal{}, err
+	}
+	defer tx.Rollback()
+
+	m, err := insertMeal(ctx, tx, userID, eatenAt, ingredients)
+	if err != nil {
+		return m, err
+	}
+	return m, tx.Commit()
+}
+
+func (s *MealStore) Update(ctx context.Context, userID, id int, eatenAt *time.Time, ingredients []models.MealIngredientInput) (models.Meal, error) {
+	defer metrics.ObserveQuery("meals", "Update")()
+	var at *time.Time
+	if eatenAt != nil {
+		t := eatenAt.UTC()
+		at = &t
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Meal{}, err
+	}
+	defer tx.Rollback()
+
+	if err := lockMeal(ctx, tx, userID, id); err != nil {
+		return models.Meal{}, err
+	}
+	if err := pantry.Restore(ctx, tx, id); err != nil {
+		return models.Meal{}, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM meal_ingredients WHERE meal_id = $1", id); err != nil {
+		return models.Meal{}, err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE meals SET created_at = COALESCE($2, created_at) WHERE id = $1", id, at); err != nil {
+		return models.Meal{}, err
+	}
+	if err := addMealIngredients(ctx, tx, userID, id, ingredients); err != nil {
+		return models.Meal{}, err
+	}
+
+	m, err := meal(ctx, tx, userID, id)
+	if err != nil {
+		return m, err
+	}
+	return m, tx.Commit()
+}
+
+func (s *MealStore) Delete(ctx context.Context, userID, id int) error {
+	defer metrics.ObserveQuery("meals", "Delete")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockMeal(ctx, tx, userID, id); err != nil {
+		return err
+	}
+	if err := pantry.Restore(ctx, tx, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM meals WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockMeal locks the user's meal for the rest of tx.
+func lockMeal(ctx context.Context, tx *sql.Tx, userID, id int) error {
+	var mealID int
+	err := tx.QueryRowContext(ctx, "SELECT id FROM meals WHERE id = $1 AND user_id = $2 FOR UPDATE", id, userID).Scan(&mealID)
+	return notFound(err)
+}
+
+// insertMeal logs a meal eaten at eatenAt inside tx and returns it.
+func insertMeal(ctx context.Context, tx *sql.Tx, userID int, eatenAt time.Time, ingredients []models.MealIngredientInput) (models.Meal, error) {
+	var mealID int
+	err := tx.QueryRowContext(ctx,
+		"INSERT INTO meals (user_id, created_at) VALUES ($1, $2) RETURNING id",
+		userID, eatenAt.UTC(),
+	).Scan(&mealID)
+	if err != nil {
+		return models.Meal{}, err
+	}
+
+	if err := addMealIngredients(ctx, tx, userID, mealID, ingredients); err != nil {
+		return models.Meal{}, err
+	}
+	return meal(ctx, tx, userID, mealID)
+}
+
+// addMealIngredients converts the ingredients into their pantry items'
+// units, folding repeated items together since a meal holds at most one row
+// per item, then deducts them from the pantry and records them against the
+// meal.
+func addMealIngredients(ctx context.Context, tx *sql.Tx, userID, mealID int, in []models.MealIngredientInput) error {
+	amounts := make([]pantry.Amount, len(in))
+	for i, ing := range in {
+		amounts[i] = pantry.Amount{PantryItemID: ing.PantryItemID, Quantity: ing.QuantityUsed, Unit: ing.Unit}
+	}
+	amounts, err := pantry.InStockUnits(ctx, tx, userID, amounts)
+	if err != nil {
+		return err
+	}
+	ingredients := make([]models.MealIngredientInput, len(amounts))
+	for i, a := range amounts {
+		ingredients[i] = models.MealIngredientInput{PantryItemID: a.PantryItemID, QuantityUsed: a.Quantity}
+	}
+
+	if err := pantry.Consume(ctx, tx, userID, mealID, ingredients); err != nil {
+		return err
+	}
+	for _, ing := range ingredients {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO meal_ingredients (meal_id, pantry_item_id, quantity_used) VALUES ($1, $2, $3)",
+			mealID, ing.PantryItemID, ing.QuantityUsed,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func meal(ctx context.Context, q queryer, userID, id int) (models.Meal, error) {
+	list, err := meals(ctx, q, "SELECT id, user_id, created_at FROM meals WHERE user_id = $1 AND id = $2", userID, id)
+	if err != nil {
+		return models.Meal{}, err
+	}
+	if len(list) == 0 {
+		return models.Meal{}, store.ErrNotFound
+	}
+	return list[0], nil
+}
+
+// meals runs query, which must select id, user_id and created_at from
+// meals, and fills in each meal's ingredients and totals. Nutrition is
+// worked out from the pantry items' current per-unit values.
+func meals(ctx context.Context, q queryer, query string, args ...any) ([]models.Meal, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	list := []models.Meal{}
+	index := map[int]int{}
+	var ids []int64
+	for rows.Next() {
+		m := models.Meal{Ingredients: []models.MealIngredient{}}
+		if err := rows.Scan(&m.ID, &m.UserID, &m.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		index[m.ID] = len(list)
+		ids = append(ids, int64(m.ID))
+		list = append(list, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return list, nil
+	}
+
+	rows, err = q.QueryContext(ctx, `
+			SELECT
+					mi.meal_id,
+					mi.pantry_item_id,
+					p.name,
+					p.unit,
+					mi.quantity_used,
+					mi.quantity_used * p.calories_per_unit,
+					mi.quantity_used * p.protein_per_unit,
+					mi.quantity_used * p.carbs_per_unit,
+					mi.quantity_used * p.fat_per_unit,
+					mi.quantity_used * p.fiber_per_unit
+			FROM meal_ingredients mi
+			JOIN pantry_items p ON p.id = mi.pantry_item_id
+			WHERE mi.meal_id = ANY($1)
+			ORDER BY mi.meal_id, p.name`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mealID int
+		var ing models.MealIngredient
+		err := rows.Scan(
+			&mealID,
+			&ing.PantryItemID,
+			&ing.Name,
+			&ing.Unit,
+			&ing.QuantityUsed,
+			&ing.Calories,
+			&ing.Protein,
+			&ing.Carbs,
+			&ing.Fat,
+			&ing.Fiber,
+		)
+		if err != nil {
+			return nil, err
+		}
+		m := &list[index[mealID]]
+		m.Ingredients = append(m.Ingredients, ing)
+		m.TotalCalories += ing.Calories
+		m.TotalProtein += ing.Protein
+		m.TotalCarbs += ing.Carbs
+		m.TotalFat += ing.Fat
+		m.TotalFiber += ing.Fiber
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+type NutritionStore struct {
+	db *sql.DB
+}
+
+func NewNutritionStore(db *sql.DB) *NutritionStore {
+	return &NutritionStore{db: db}
+}
+
+func (s *NutritionStore) Days(ctx context.Context, userID int, tz, from, to string) (map[string]models.NutritionDay, error) {
+	defer metrics.ObserveQuery("nutrition", "Days")()
+	rows, err := s.db.QueryContext(ctx, `
+			SELECT
+					(m.created_at AT TIME ZONE 'UTC' AT TIME ZONE $2)::date AS day,
+					COUNT(DISTINCT m.id),
+					COALESCE(SUM(mi.quantity_used * p.calories_per_unit), 0),
+					COALESCE(SUM(mi.quantity_used * p.protein_per_unit), 0),
+					COALESCE(SUM(mi.quantity_used * p.carbs_per_unit), 0),
+					COALESCE(SUM(mi.quantity_used * p.fat_per_unit), 0),
+					COALESCE(SUM(mi.quantity_used * p.fiber_per_unit), 0)
+			FROM meals m
+			LEFT JOIN meal_ingredients mi ON mi.meal_id = m.id
+			LEFT JOIN pantry_items p ON p.id = mi.pantry_item_id
+			WHERE m.user_id = $1
+			AND (m.created_at AT TIME ZONE 'UTC' AT TIME ZONE $2)::date BETWEEN $3 AND $4
+			GROUP BY day
+	`, userID, tz, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := map[string]models.NutritionDay{}
+	for rows.Next() {
+		var date time.Time
+		var d models.NutritionDay
+		err := rows.Scan(
+			&date,
+			&d.Meals,
+			&d.Calories,
+			&d.Protein,
+			&d.Carbs,
+			&d.Fat,
+			&d.Fiber,
+		)
+		if err != nil {
+			return nil, err
+		}
+		days[date.Format("2006-01-02")] = d
+	}
+	return days, rows.Err()
+}
+
+func (s *NutritionStore) Targets(ctx context.Context, userID int) (models.NutritionTargets, error) {
+	defer metrics.ObserveQuery("nutrition", "Targets")()
+	targets := models.NutritionTargets{UserID: userID}
+	err := s.db.QueryRowContext(ctx, `
+			SELECT calories, protein, carbs, fat, fiber, updated_at
+			FROM nutrition_targets
+			WHERE user_id = $1
+	`, userID).Scan(
+		&targets.Calories,
+		&targets.Protein,
+		&targets.Carbs,
+		&targets.Fat,
+		&targets.Fiber,
+		&targets.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return targets, nil
+	}
+	return targets, err
+}
+
+func (s *NutritionStore) SaveTargets(ctx context.Context, userID int, input models.NutritionTargetsInput) (models.NutritionTargets, error) {
+	defer metrics.ObserveQuery("nutrition", "SaveTargets")()
+	query := `
+			INSERT INTO nutrition_targets (user_id, calories, protein, carbs, fat, fiber)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_id) DO UPDATE SET
+					calories = EXCLUDED.calories,
+					protein = EXCLUDED.protein,
+					carbs = EXCLUDED.carbs,
+					fat = EXCLUDED.fat,
+					fiber = EXCLUDED.fiber,
+					updated_at = CURRENT_TIMESTAMP
+			RETURNING user_id, calories, protein, carbs, fat, fiber, updated_at
+	`
+
+	var targets models.NutritionTargets
+	err := s.db.QueryRowContext(ctx,
+		query,
+		userID,
+		input.Calories,
+		input.Protein,
+		input.Carbs,
+		input.Fat,
+		input.Fiber,
+	).Scan(
+		&targets.UserID,
+		&targets.Calories,
+		&targets.Protein,
+		&targets.Carbs,
+		&targets.Fat,
+		&targets.Fiber,
+		&targets.UpdatedAt,
+	)
+	return targets, err
+}
+
+func (s *NutritionStore) Training(ctx context.Context, userID int, since time.Time) (sets, cardioMinutes float64, err error) {
+	defer metrics.ObserveQuery("nutrition", "Training")()
+	err = s.db.QueryRowContext(ctx, `
+			SELECT
+					COALESCE((SELECT SUM(we.sets)
+							FROM workout_exercises we
+							JOIN workout_sessions ws ON ws.id = we.workout_session_id
+							WHERE ws.user_id = $1 AND ws.created_at >= $2), 0),
+					COALESCE((SELECT SUM(cardio_minutes)
+							FROM workout_sessions
+							WHERE user_id = $1 AND created_at >= $2), 0)
+	`, userID, since.UTC()).Scan(&sets, &cardioMinutes)
+	return sets, cardioMinutes, err
+}
@@ -0,0 +1,368 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/lib/pq"
+)
+
+const pantryItemColumns = `
+		id, user_id, name, quantity, unit, threshold, calories_per_unit, protein_per_unit,
+		carbs_per_unit, fat_per_unit, fiber_per_unit, grams_per_item, quantity < threshold,
+		(SELECT to_char(MIN(b.expires_on), 'YYYY-MM-DD') FROM pantry_batches b WHERE b.pantry_item_id = pantry_items.id),
+		created_at, updated_at`
+
+// queryer and queryRower are satisfied by both *sql.DB and *sql.Tx, so
+// that the helpers loading rows can run inside a transaction.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func scanPantryItem(row interface{ Scan(...any) error }, p *models.PantryItem) error {
+	return row.Scan(
+		&p.ID,
+		&p.UserID,
+		&p.Name,
+		&p.Quantity,
+		&p.Unit,
+		&p.Threshold,
+		&p.CaloriesPerUnit,
+		&p.ProteinPerUnit,
+		&p.CarbsPerUnit,
+		&p.FatPerUnit,
+		&p.FiberPerUnit,
+		&p.GramsPerItem,
+		&p.LowStock,
+		&p.NextExpiry,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+}
+
+type PantryStore struct {
+	db *sql.DB
+}
+
+func NewPantryStore(db *sql.DB) *PantryStore {
+	return &PantryStore{db: db}
+}
+
+func (s *PantryStore) List(ctx context.Context, userID int, lowStock bool) ([]models.PantryItem, error) {
+	defer metrics.ObserveQuery("pantry", "List")()
+	query := `
+			SELECT` + pantryItemColumns + `
+			FROM pantry_items
+			WHERE user_id = $1`
+	if lowStock {
+		query += " AND quantity < threshold"
+	}
+	query += " ORDER BY name"
+	return pantryItems(ctx, s.db, query, userID)
+}
+
+func (s *PantryStore) Get(ctx context.Context, userID, id int) (models.PantryItem, error) {
+	defer metrics.ObserveQuery("pantry", "Get")()
+	p, err := pantryItem(ctx, s.db, userID, id)
+	return p, notFound(err)
+}
+
+func (s *PantryStore) NameTaken(ctx context.Context, userID int, name string, exceptID int) (bool, error) {
+	defer metrics.ObserveQuery("pantry", "NameTaken")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM pantry_items WHERE user_id = $1 AND name = $2 AND id != $3)",
+		userID, name, exceptID)
+}
+
+func (s *PantryStore) Create(ctx context.Context, userID int, input models.PantryItemInput) (models.PantryItem, error) {
+	defer metrics.ObserveQuery("pantry", "Create")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.PantryItem{}, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `
+			INSERT INTO pantry_items
+			(user_id, name, quantity, unit, threshold, calories_per_unit, protein_per_unit,
+			carbs_per_unit, fat_per_unit, fiber_per_unit, grams_per_item)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING id`,
+		userID,
+		input.Name,
+		input.Quantity,
+		input.Unit,
+		input.Threshold,
+		input.CaloriesPerUnit,
+		input.ProteinPerUnit,
+		input.CarbsPerUnit,
+		input.FatPerUnit,
+		input.FiberPerUnit,
+		input.GramsPerItem,
+	).Scan(&id)
+	if err != nil {
+		return models.PantryItem{}, err
+	}
+
+	if input.ExpiresOn != nil && input.Quantity > 0 {
+		if err := pantry.AddBatch(ctx, tx, id, input.Quantity, *input.ExpiresOn); err != nil {
+			return models.PantryItem{}, err
+		}
+	}
+
+	p, err := pantryItem(ctx, tx, userID, id)
+	if err != nil {
+		return p, err
+	}
+	return p, tx.Commit()
+}
+
+func (s *PantryStore) Update(ctx context.Context, userID, id int, input models.PantryItemInput) (models.PantryItem, error) {
+	defer metrics.ObserveQuery("pantry", "Update")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.PantryItem{}, err
+	}
+	defer tx.Rollback()
+
+	var oldQuantity float64
+	var oldUnit string
+	err = tx.QueryRowContext(ctx, "SELECT quantity, unit FROM pantry_items WHERE id = $1 AND user_id = $2 FOR UPDATE",
+		id, userID).Scan(&oldQuantity, &oldUnit)
+	if err != nil {
+		return models.PantryItem{}, notFound(err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+			UPDATE pantry_items
+			SET name = $3, quantity = $4, unit = $5, threshold = $6,
+					calories_per_unit = $7, protein_per_unit = $8, carbs_per_unit = $9,
+					fat_per_unit = $10, fiber_per_unit = $11, grams_per_item = $12,
+					updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND user_id = $2`,
+		id,
+		userID,
+		input.Name,
+		input.Quantity,
+		input.Unit,
+		input.Threshold,
+		input.CaloriesPerUnit,
+		input.ProteinPerUnit,
+		input.CarbsPerUnit,
+		input.FatPerUnit,
+		input.FiberPerUnit,
+		input.GramsPerItem,
+	)
+	if err != nil {
+		return models.PantryItem{}, err
+	}
+
+	if err := pantry.ChangeUnit(ctx, tx, id, oldUnit, input.Unit, input.GramsPerItem); err != nil {
+		return models.PantryItem{}, err
+	}
+	oldQuantity, converted := pantry.Convert(oldQuantity, oldUnit, input.Unit, input.GramsPerItem)
+	if input.ExpiresOn != nil && converted && input.Quantity > oldQuantity {
+		if err := pantry.AddBatch(ctx, tx, id, input.Quantity-oldQuantity, *input.ExpiresOn); err != nil {
+			return models.PantryItem{}, err
+		}
+	}
+	if err := pantry.TrimBatches(ctx, tx, id); err != nil {
+		return models.PantryItem{}, err
+	}
+
+	p, err := pantryItem(ctx, tx, userID, id)
+	if err != nil {
+		return p, err
+	}
+	return p, tx.Commit()
+}
+
+func (s *PantryStore) Restock(ctx context.Context, userID, id int, input models.PantryRestockInput) (models.PantryItem, error) {
+	defer metrics.ObserveQuery("pantry", "Restock")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.PantryItem{}, err
+	}
+	defer tx.Rollback()
+
+	var lowStock bool
+	err = tx.QueryRowContext(ctx, `
+			UPDATE pantry_items
+			SET quantity = quantity + $3, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND user_id = $2
+			RETURNING quantity < threshold`, id, userID, input.Quantity).Scan(&lowStock)
+	if err != nil {
+		return models.PantryItem{}, notFound(err)
+	}
+
+	if input.ExpiresOn != nil {
+		if err := pantry.AddBatch(ctx, tx, id, input.Quantity, *input.ExpiresOn); err != nil {
+			return models.PantryItem{}, err
+		}
+	}
+
+	// An item restocked outside the shopping list should not come back
+	// already checked off the next time it runs low.
+	if !lowStock {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM shopping_list_checks WHERE pantry_item_id = $1", id); err != nil {
+			return models.PantryItem{}, err
+		}
+	}
+
+	p, err := pantryItem(ctx, tx, userID, id)
+	if err != nil {
+		return p, err
+	}
+	return p, tx.Commit()
+}
+
+func (s *PantryStore) InUse(ctx context.Context, id int) (bool, error) {
+	defer metrics.ObserveQuery("pantry", "InUse")()
+	return exists(ctx, s.db, `
+			SELECT EXISTS(SELECT 1 FROM meal_ingredients WHERE pantry_item_id = $1)
+				OR EXISTS(SELECT 1 FROM recipe_ingredients WHERE pantry_item_id = $1)
+				OR EXISTS(SELECT 1 FROM meal_plan_ingredients WHERE pantry_item_id = $1)`, id)
+}
+
+func (s *PantryStore) Delete(ctx context.Context, userID, id int) error {
+	defer metrics.ObserveQuery("pantry", "Delete")()
+	return affected(s.db.ExecContext(ctx, "DELETE FROM pantry_items WHERE id = $1 AND user_id = $2", id, userID))
+}
+
+func (s *PantryStore) Expiring(ctx context.Context, userID int, today string, days int) ([]models.ExpiringBatch, error) {
+	defer metrics.ObserveQuery("pantry", "Expiring")()
+	rows, err := s.db.QueryContext(ctx, `
+			SELECT b.id, p.id, p.name, p.unit, b.quantity,
+				to_char(b.expires_on, 'YYYY-MM-DD'), b.expires_on - $2::date
+			FROM pantry_batches b
+			JOIN pantry_items p ON p.id = b.pantry_item_id
+			WHERE p.user_id = $1 AND b.expires_on <= $2::date + $3::int
+			ORDER BY b.expires_on, p.name, b.id`, userID, today, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batches := []models.ExpiringBatch{}
+	for rows.Next() {
+		var b models.ExpiringBatch
+		if err := rows.Scan(&b.BatchID, &b.PantryItemID, &b.Name, &b.Unit, &b.Quantity, &b.ExpiresOn, &b.DaysLeft); err != nil {
+			return nil, err
+		}
+		batches = append(batches, b)
+	}
+	return batches, rows.Err()
+}
+
+func (s *PantryStore) Batches(ctx context.Context, userID, id int) (models.PantryItemBatches, error) {
+	defer metrics.ObserveQuery("pantry", "Batches")()
+	result := models.PantryItemBatches{PantryItemID: id, Batches: []models.PantryBatch{}}
+	err := s.db.QueryRowContext(ctx, "SELECT name, unit, quantity FROM pantry_items WHERE id = $1 AND user_id = $2",
+		id, userID).Scan(&result.Name, &result.Unit, &result.Quantity)
+	if err != nil {
+		return result, notFound(err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+			SELECT id, pantry_item_id, quantity, to_char(expires_on, 'YYYY-MM-DD'), received_at
+			FROM pantry_batches
+			WHERE pantry_item_id = $1
+			ORDER BY expires_on, received_at, id`, id)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	result.Undated = result.Quantity
+	for rows.Next() {
+		var b models.PantryBatch
+		if err := rows.Scan(&b.ID, &b.PantryItemID, &b.Quantity, &b.ExpiresOn, &b.ReceivedAt); err != nil {
+			return result, err
+		}
+		result.Batches = append(result.Batches, b)
+		result.Undated -= b.Quantity
+	}
+	result.Undated = max(result.Undated, 0)
+	return result, rows.Err()
+}
+
+func (s *PantryStore) DiscardBatch(ctx context.Context, userID, id, batchID int) error {
+	defer metrics.ObserveQuery("pantry", "DiscardBatch")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := pantry.DiscardBatch(ctx, tx, userID, id, batchID); err != nil {
+		return notFound(err)
+	}
+	return tx.Commit()
+}
+
+func (s *PantryStore) Forecast(ctx context.Context, userID int, outlook pantry.Outlook, leadDays float64) ([]models.PantryForecast, error) {
+	defer metrics.ObserveQuery("pantry", "Forecast")()
+	return pantry.Forecast(ctx, s.db, userID, outlook, leadDays)
+}
+
+func (s *PantryStore) TuneThresholds(ctx context.Context, userID int, outlook pantry.Outlook, leadDays float64) ([]models.PantryItem, error) {
+	defer metrics.ObserveQuery("pantry", "TuneThresholds")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids, err := pantry.TuneThresholds(ctx, tx, userID, outlook, leadDays)
+	if err != nil {
+		return nil, err
+	}
+	items, err := pantryItemsByID(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return items, tx.Commit()
+}
+
+func pantryItem(ctx context.Context, q queryRower, userID, id int) (models.PantryItem, error) {
+	var p models.PantryItem
+	err := scanPantryItem(q.QueryRowContext(ctx, `
+			SELECT`+pantryItemColumns+`
+			FROM pantry_items
+			WHERE id = $1 AND user_id = $2`, id, userID), &p)
+	return p, err
+}
+
+// pantryItemsByID loads the pantry items with the given IDs, by name.
+func pantryItemsByID(ctx context.Context, q queryer, ids []int64) ([]models.PantryItem, error) {
+	return pantryItems(ctx, q, `
+			SELECT`+pantryItemColumns+`
+			FROM pantry_items
+			WHERE id = ANY($1)
+			ORDER BY name`, pq.Array(ids))
+}
+
+func pantryItems(ctx context.Context, q queryer, query string, args ...any) ([]models.PantryItem, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.PantryItem{}
+	for rows.Next() {
+		var p models.PantryItem
+		if err := scanPantryItem(rows, &p); err != nil {
+			return nil, err
+		}
+		items = append(items, p)
+	}
+	return items, rows.Err()
+}
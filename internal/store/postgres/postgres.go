@@ -0,0 +1,58 @@
+// Package postgres implements the store repositories on PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+// notFound maps sql.ErrNoRows to store.ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return store.ErrNotFound
+	}
+	return err
+}
+
+// affected returns store.ErrNotFound when an UPDATE or DELETE matched no
+// rows.
+func affected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func exists(ctx context.Context, db *sql.DB, query string, args ...any) (bool, error) {
+	var found bool
+	err := db.QueryRowContext(ctx, query, args...).Scan(&found)
+	return found, err
+}
+
+var (
+	_ store.GymStore             = (*GymStore)(nil)
+	_ store.EquipmentStore       = (*EquipmentStore)(nil)
+	_ store.EquipmentTypeStore   = (*EquipmentTypeStore)(nil)
+	_ store.ExerciseStore        = (*ExerciseStore)(nil)
+	_ store.WorkoutStore         = (*WorkoutStore)(nil)
+	_ store.UserStore            = (*UserStore)(nil)
+	_ store.ProfileStore         = (*ProfileStore)(nil)
+	_ store.PantryStore          = (*PantryStore)(nil)
+	_ store.MealStore            = (*MealStore)(nil)
+	_ store.RecipeStore          = (*RecipeStore)(nil)
+	_ store.MealPlanStore        = (*MealPlanStore)(nil)
+	_ store.ShoppingListStore    = (*ShoppingListStore)(nil)
+	_ store.BodyMeasurementStore = (*BodyMeasurementStore)(nil)
+	_ store.NutritionStore       = (*NutritionStore)(nil)
+	_ store.FoodStore            = (*FoodStore)(nil)
+)
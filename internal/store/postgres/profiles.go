@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+type ProfileStore struct {
+	db *sql.DB
+}
+
+func NewProfileStore(db *sql.DB) *ProfileStore {
+	return &ProfileStore{db: db}
+}
+
+func scanProfile(row *sql.Row, p *models.UserProfile) error {
+	return row.Scan(
+		&p.UserID,
+		&p.BodyweightKg,
+		&p.HeightCm,
+		&p.BirthYear,
+		&p.Sex,
+		&p.PreferredUnits,
+		&p.Timezone,
+		&p.UpdatedAt,
+	)
+}
+
+func (s *ProfileStore) Get(ctx context.Context, userID int) (models.UserProfile, error) {
+	defer metrics.ObserveQuery("profiles", "Get")()
+	var p models.UserProfile
+	err := scanProfile(s.db.QueryRowContext(ctx, `
+			SELECT
+					COALESCE(p.user_id, u.id),
+					p.bodyweight_kg,
+					p.height_cm,
+					p.birth_year,
+					p.sex,
+					COALESCE(p.preferred_units, 'metric'),
+					COALESCE(p.timezone, 'UTC'),
+					COALESCE(p.updated_at, u.updated_at)
+			FROM users u
+			LEFT JOIN user_profiles p ON p.user_id = u.id
+			WHERE u.id = $1`, userID), &p)
+	return p, notFound(err)
+}
+
+func (s *ProfileStore) Save(ctx context.Context, userID int, input models.UserProfileInput) (models.UserProfile, error) {
+	defer metrics.ObserveQuery("profiles", "Save")()
+	var p models.UserProfile
+	err := scanProfile(s.db.QueryRowContext(ctx, `
+			INSERT INTO user_profiles (user_id, bodyweight_kg, height_cm, birth_year, sex, preferred_units, timezone)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (user_id) DO UPDATE SET
+					bodyweight_kg = EXCLUDED.bodyweight_kg,
+					height_cm = EXCLUDED.height_cm,
+					birth_year = EXCLUDED.birth_year,
+					sex = EXCLUDED.sex,
+					preferred_units = EXCLUDED.preferred_units,
+					timezone = EXCLUDED.timezone,
+					updated_at = CURRENT_TIMESTAMP
+			RETURNING user_id, bodyweight_kg, height_cm, birth_year, sex, preferred_units, timezone, updated_at`,
+		userID,
+		input.BodyweightKg,
+		input.HeightCm,
+		input.BirthYear,
+		input.Sex,
+		input.PreferredUnits,
+		input.Timezone,
+	), &p)
+	return p, err
+}
@@ -0,0 +1,282 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+	"github.com/lib/pq"
+)
+
+const recipeColumns = `
+		id, user_id, name, servings, notes, created_at, updated_at`
+
+type RecipeStore struct {
+	db *sql.DB
+}
+
+func NewRecipeStore(db *sql.DB) *RecipeStore {
+	return &RecipeStore{db: db}
+}
+
+func (s *RecipeStore) List(ctx context.Context, userID int) ([]models.Recipe, error) {
+	defer metrics.ObserveQuery("recipes", "List")()
+	return recipes(ctx, s.db, `
+			SELECT`+recipeColumns+`
+			FROM recipes
+			WHERE user_id = $1
+			ORDER BY name`, userID)
+}
+
+func (s *RecipeStore) Get(ctx context.Context, userID, id int) (models.Recipe, error) {
+	defer metrics.ObserveQuery("recipes", "Get")()
+	return recipe(ctx, s.db, userID, id)
+}
+
+func (s *RecipeStore) NameTaken(ctx context.Context, userID int, name string, exceptID int) (bool, error) {
+	defer metrics.ObserveQuery("recipes", "NameTaken")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM recipes WHERE user_id = $1 AND name = $2 AND id != $3)",
+		userID, name, exceptID)
+}
+
+func (s *RecipeStore) Create(ctx context.Context, userID int, input models.RecipeInput) (models.Recipe, error) {
+	defer metrics.ObserveQuery("recipes", "Create")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO recipes (user_id, name, servings, notes) VALUES ($1, $2, $3, $4) RETURNING id",
+		userID, input.Name, input.Servings, input.Notes,
+	).Scan(&id)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+
+	if err := setRecipeIngredients(ctx, tx, userID, id, input.Ingredients); err != nil {
+		return models.Recipe{}, err
+	}
+
+	r, err := recipe(ctx, tx, userID, id)
+	if err != nil {
+		return r, err
+	}
+	return r, tx.Commit()
+}
+
+func (s *RecipeStore) Update(ctx context.Context, userID, id int, input models.RecipeInput) (models.Recipe, error) {
+	defer metrics.ObserveQuery("recipes", "Update")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+	defer tx.Rollback()
+
+	err = affected(tx.ExecContext(ctx, `
+			UPDATE recipes
+			SET name = $3, servings = $4, notes = $5, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND user_id = $2`,
+		id, userID, input.Name, input.Servings, input.Notes,
+	))
+	if err != nil {
+		return models.Recipe{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recipe_ingredients WHERE recipe_id = $1", id); err != nil {
+		return models.Recipe{}, err
+	}
+	if err := setRecipeIngredients(ctx, tx, userID, id, input.Ingredients); err != nil {
+		return models.Recipe{}, err
+	}
+
+	r, err := recipe(ctx, tx, userID, id)
+	if err != nil {
+		return r, err
+	}
+	return r, tx.Commit()
+}
+
+func (s *RecipeStore) Delete(ctx context.Context, userID, id int) error {
+	defer metrics.ObserveQuery("recipes", "Delete")()
+	return affected(s.db.ExecContext(ctx, "DELETE FROM recipes WHERE id = $1 AND user_id = $2", id, userID))
+}
+
+func (s *RecipeStore) Log(ctx context.Context, userID, id int, servings float64, eatenAt time.Time) (models.Meal, error) {
+	defer metrics.ObserveQuery("recipes", "Log")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Meal{}, err
+	}
+	defer tx.Rollback()
+
+	r, err := recipe(ctx, tx, userID, id)
+	if err != nil {
+		return models.Meal{}, err
+	}
+
+	scale := servings / r.Servings
+	ingredients := make([]models.MealIngredientInput, len(r.Ingredients))
+	for i, ing := range r.Ingredients {
+		ingredients[i] = models.MealIngredientInput{
+			PantryItemID: ing.PantryItemID,
+			QuantityUsed: ing.Quantity * scale,
+		}
+	}
+
+	m, err := insertMeal(ctx, tx, userID, eatenAt, ingredients)
+	if err != nil {
+		return m, err
+	}
+	return m, tx.Commit()
+}
+
+// setRecipeIngredients records the ingredients against the recipe in their
+// pantry items' units.
+func setRecipeIngredients(ctx context.Context, tx *sql.Tx, userID, recipeID int, in []models.RecipeIngredientInput) error {
+	ingredients, err := recipeIngredientsInStockUnits(ctx, tx, userID, in)
+	if err != nil {
+		return err
+	}
+
+	for _, ing := range ingredients {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO recipe_ingredients (recipe_id, pantry_item_id, quantity) VALUES ($1, $2, $3)",
+			recipeID, ing.PantryItemID, ing.Quantity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recipeIngredientsInStockUnits converts ingredients into the units their
+// pantry items are stocked in, folding repeated items into one ingredient.
+// Items that are not the user's give pantry.ErrItemNotFound.
+func recipeIngredientsInStockUnits(ctx context.Context, tx *sql.Tx, userID int, in []models.RecipeIngredientInput) ([]models.RecipeIngredientInput, error) {
+	amounts := make([]pantry.Amount, len(in))
+	for i, ing := range in {
+		amounts[i] = pantry.Amount{PantryItemID: ing.PantryItemID, Quantity: ing.Quantity, Unit: ing.Unit}
+	}
+	amounts, err := pantry.InStockUnits(ctx, tx, userID, amounts)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.RecipeIngredientInput, len(amounts))
+	for i, a := range amounts {
+		out[i] = models.RecipeIngredientInput{PantryItemID: a.PantryItemID, Quantity: a.Quantity}
+	}
+	return out, nil
+}
+
+func recipe(ctx context.Context, q queryer, userID, id int) (models.Recipe, error) {
+	list, err := recipes(ctx, q, "SELECT"+recipeColumns+" FROM recipes WHERE user_id = $1 AND id = $2", userID, id)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+	if len(list) == 0 {
+		return models.Recipe{}, store.ErrNotFound
+	}
+	return list[0], nil
+}
+
+// recipes runs query, which must select recipeColumns, and fills in each
+// recipe's ingredients with their nutrition from the pantry items' current
+// per-unit values.
+func recipes(ctx context.Context, q queryer, query string, args ...any) ([]models.Recipe, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	list := []models.Recipe{}
+	index := map[int]int{}
+	var ids []int64
+	for rows.Next() {
+		r := models.Recipe{Ingredients: []models.RecipeIngredient{}}
+		err := rows.Scan(&r.ID, &r.UserID, &r.Name, &r.Servings, &r.Notes, &r.CreatedAt, &r.UpdatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		index[r.ID] = len(list)
+		ids = append(ids, int64(r.ID))
+		list = append(list, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return list, nil
+	}
+
+	rows, err = q.QueryContext(ctx, `
+			SELECT
+					ri.recipe_id,
+					ri.pantry_item_id,
+					p.name,
+					p.unit,
+					ri.quantity,
+					ri.quantity * p.calories_per_unit,
+					ri.quantity * p.protein_per_unit,
+					ri.quantity * p.carbs_per_unit,
+					ri.quantity * p.fat_per_unit,
+					ri.quantity * p.fiber_per_unit
+			FROM recipe_ingredients ri
+			JOIN pantry_items p ON p.id = ri.pantry_item_id
+			WHERE ri.recipe_id = ANY($1)
+			ORDER BY ri.recipe_id, p.name`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipeID int
+		var ing models.RecipeIngredient
+		err := rows.Scan(
+			&recipeID,
+			&ing.PantryItemID,
+			&ing.Name,
+			&ing.Unit,
+			&ing.Quantity,
+			&ing.Calories,
+			&ing.Protein,
+			&ing.Carbs,
+			&ing.Fat,
+			&ing.Fiber,
+		)
+		if err != nil {
+			return nil, err
+		}
+		r := &list[index[recipeID]]
+		r.Ingredients = append(r.Ingredients, ing)
+		r.Total.Calories += ing.Calories
+		r.Total.Protein += ing.Protein
+		r.Total.Carbs += ing.Carbs
+		r.Total.Fat += ing.Fat
+		r.Total.Fiber += ing.Fiber
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		r := &list[i]
+		r.PerServing = models.Macros{
+			Calories: r.Total.Calories / r.Servings,
+			Protein:  r.Total.Protein / r.Servings,
+			Carbs:    r.Total.Carbs / r.Servings,
+			Fat:      r.Total.Fat / r.Servings,
+			Fiber:    r.Total.Fiber / r.Servings,
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+type ShoppingListStore struct {
+	db *sql.DB
+}
+
+func NewShoppingListStore(db *sql.DB) *ShoppingListStore {
+	return &ShoppingListStore{db: db}
+}
+
+func (s *ShoppingListStore) List(ctx context.Context, userID int, plan *pantry.PlanWindow, outlook *pantry.Outlook) ([]models.ShoppingListItem, error) {
+	defer metrics.ObserveQuery("shopping_list", "List")()
+	return pantry.ShoppingList(ctx, s.db, userID, plan, outlook)
+}
+
+func (s *ShoppingListStore) Add(ctx context.Context, userID int, input models.ShoppingListItemInput) (models.ShoppingListItem, error) {
+	defer metrics.ObserveQuery("shopping_list", "Add")()
+	item := models.ShoppingListItem{Source: "manual"}
+	err := s.db.QueryRowContext(ctx, `
+			INSERT INTO shopping_list_items (user_id, pantry_item_id, name, quantity, unit)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, pantry_item_id, name, quantity, unit, checked
+	`, userID, input.PantryItemID, input.Name, input.Quantity, input.Unit).Scan(
+		&item.ItemID,
+		&item.PantryItemID,
+		&item.Name,
+		&item.Quantity,
+		&item.Unit,
+		&item.Checked,
+	)
+	return item, err
+}
+
+func (s *ShoppingListStore) Check(ctx context.Context, userID, id int, checked bool) error {
+	defer metrics.ObserveQuery("shopping_list", "Check")()
+	return affected(s.db.ExecContext(ctx, "UPDATE shopping_list_items SET checked = $3 WHERE id = $1 AND user_id = $2",
+		id, userID, checked))
+}
+
+func (s *ShoppingListStore) CheckPantryItem(ctx context.Context, userID, pantryItemID int, checked bool) error {
+	defer metrics.ObserveQuery("shopping_list", "CheckPantryItem")()
+	found, err := exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM pantry_items WHERE id = $1 AND user_id = $2)",
+		pantryItemID, userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return store.ErrNotFound
+	}
+
+	if checked {
+		_, err = s.db.ExecContext(ctx, "INSERT INTO shopping_list_checks (pantry_item_id) VALUES ($1) ON CONFLICT DO NOTHING", pantryItemID)
+	} else {
+		_, err = s.db.ExecContext(ctx, "DELETE FROM shopping_list_checks WHERE pantry_item_id = $1", pantryItemID)
+	}
+	return err
+}
+
+func (s *ShoppingListStore) Delete(ctx context.Context, userID, id int) error {
+	defer metrics.ObserveQuery("shopping_list", "Delete")()
+	return affected(s.db.ExecContext(ctx, "DELETE FROM shopping_list_items WHERE id = $1 AND user_id = $2", id, userID))
+}
+
+func (s *ShoppingListStore) Purchase(ctx context.Context, userID int, plan *pantry.PlanWindow, outlook *pantry.Outlook) (models.ShoppingListPurchase, error) {
+	defer metrics.ObserveQuery("shopping_list", "Purchase")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.ShoppingListPurchase{}, err
+	}
+	defer tx.Rollback()
+
+	ids, removed, err := pantry.Purchase(ctx, tx, userID, plan, outlook)
+	if err != nil {
+		return models.ShoppingListPurchase{}, err
+	}
+	restocked, err := pantryItemsByID(ctx, tx, ids)
+	if err != nil {
+		return models.ShoppingListPurchase{}, err
+	}
+	return models.ShoppingListPurchase{Restocked: restocked, ItemsRemoved: removed}, tx.Commit()
+}
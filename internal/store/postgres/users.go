@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/account"
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+)
+
+const userColumns = "id, email, created_at, updated_at, disabled_at"
+
+type UserStore struct {
+	db *sql.DB
+}
+
+func NewUserStore(db *sql.DB) *UserStore {
+	return &UserStore{db: db}
+}
+
+func (s *UserStore) List(ctx context.Context) ([]models.User, error) {
+	defer metrics.ObserveQuery("users", "List")()
+	rows, err := s.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DisabledAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (s *UserStore) Get(ctx context.Context, id int) (models.User, error) {
+	defer metrics.ObserveQuery("users", "Get")()
+	var u models.User
+	err := s.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", id).Scan(
+		&u.ID, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DisabledAt)
+	return u, notFound(err)
+}
+
+func (s *UserStore) Exists(ctx context.Context, id int) (bool, error) {
+	defer metrics.ObserveQuery("users", "Exists")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", id)
+}
+
+func (s *UserStore) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	defer metrics.ObserveQuery("users", "FindByEmail")()
+	var u models.User
+	err := s.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE LOWER(email) = LOWER($1)", email).Scan(
+		&u.ID, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DisabledAt)
+	return u, notFound(err)
+}
+
+func (s *UserStore) Create(ctx context.Context, email, passwordHash string) (models.User, error) {
+	defer metrics.ObserveQuery("users", "Create")()
+	var u models.User
+	err := s.db.QueryRowContext(ctx, `
+			INSERT INTO users (email, password_hash, created_at, updated_at)
+			VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			RETURNING `+userColumns, email, passwordHash,
+	).Scan(&u.ID, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DisabledAt)
+	return u, err
+}
+
+func (s *UserStore) EmailTaken(ctx context.Context, email string, exceptID int) (bool, error) {
+	defer metrics.ObserveQuery("users", "EmailTaken")()
+	return exists(ctx, s.db, "SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(email) = LOWER($1) AND id != $2)", email, exceptID)
+}
+
+func (s *UserStore) UpdateEmail(ctx context.Context, id int, email string) (models.User, error) {
+	defer metrics.ObserveQuery("users", "UpdateEmail")()
+	var u models.User
+	err := s.db.QueryRowContext(ctx,
+		"UPDATE users SET email = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING "+userColumns,
+		id, email,
+	).Scan(&u.ID, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DisabledAt)
+	return u, notFound(err)
+}
+
+func (s *UserStore) PasswordHash(ctx context.Context, id int) (string, error) {
+	defer metrics.ObserveQuery("users", "PasswordHash")()
+	var hash string
+	err := s.db.QueryRowContext(ctx, "SELECT password_hash FROM users WHERE id = $1", id).Scan(&hash)
+	return hash, notFound(err)
+}
+
+func (s *UserStore) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	defer metrics.ObserveQuery("users", "UpdatePassword")()
+	return affected(s.db.ExecContext(ctx,
+		"UPDATE users SET password_hash = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", id, passwordHash))
+}
+
+func (s *UserStore) SetDisabled(ctx context.Context, id int, disabled bool) error {
+	defer metrics.ObserveQuery("users", "SetDisabled")()
+	return affected(s.db.ExecContext(ctx, `
+			UPDATE users
+			SET disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, CURRENT_TIMESTAMP) END,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1`, id, disabled))
+}
+
+func (s *UserStore) ScheduleDeletion(ctx context.Context, id int, grace time.Duration) (models.AccountDeletionStatus, error) {
+	defer metrics.ObserveQuery("users", "ScheduleDeletion")()
+	status := models.AccountDeletionStatus{UserID: id, Pending: true}
+	err := s.db.QueryRowContext(ctx, `
+			UPDATE users
+			SET deletion_requested_at = COALESCE(deletion_requested_at, CURRENT_TIMESTAMP),
+				deletion_scheduled_for = COALESCE(deletion_scheduled_for, CURRENT_TIMESTAMP + $2 * INTERVAL '1 second'),
+				updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING deletion_requested_at, deletion_scheduled_for`,
+		id, int64(grace/time.Second),
+	).Scan(&status.RequestedAt, &status.ScheduledFor)
+	return status, notFound(err)
+}
+
+func (s *UserStore) DeletionStatus(ctx context.Context, id int) (models.AccountDeletionStatus, error) {
+	defer metrics.ObserveQuery("users", "DeletionStatus")()
+	status := models.AccountDeletionStatus{UserID: id}
+	err := s.db.QueryRowContext(ctx, "SELECT deletion_requested_at, deletion_scheduled_for FROM users WHERE id = $1", id).Scan(
+		&status.RequestedAt, &status.ScheduledFor)
+	status.Pending = status.ScheduledFor != nil
+	return status, notFound(err)
+}
+
+func (s *UserStore) CancelDeletion(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery("users", "CancelDeletion")()
+	return affected(s.db.ExecContext(ctx, `
+			UPDATE users
+			SET deletion_requested_at = NULL, deletion_scheduled_for = NULL, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND deletion_scheduled_for IS NOT NULL`, id))
+}
+
+func (s *UserStore) Timezone(ctx context.Context, id int) (string, error) {
+	defer metrics.ObserveQuery("users", "Timezone")()
+	return account.Timezone(ctx, s.db, id)
+}
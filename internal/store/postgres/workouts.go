@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/store"
+)
+
+const workoutExerciseQuery = `
+		SELECT we.id, we.workout_session_id, we.exercise_id, e.name AS exercise_name,
+				we.gym_equipment_id, et.name AS equipment_name, we.weight, we.reps, we.sets, we.created_at
+		FROM workout_exercises we
+		JOIN exercises e ON e.id = we.exercise_id
+		JOIN workout_sessions ws ON ws.id = we.workout_session_id
+		JOIN gym_equipment ge ON ge.id = we.gym_equipment_id
+		JOIN equipment_types et ON et.id = ge.equipment_type_id`
+
+type WorkoutStore struct {
+	db *sql.DB
+}
+
+func NewWorkoutStore(db *sql.DB) *WorkoutStore {
+	return &WorkoutStore{db: db}
+}
+
+func (s *WorkoutStore) ListByUser(ctx context.Context, userID int) ([]models.WorkoutSession, error) {
+	defer metrics.ObserveQuery("workouts", "ListByUser")()
+	rows, err := s.db.QueryContext(ctx, `
+			SELECT id, user_id, gym_id, cardio_minutes, created_at
+			FROM workout_sessions
+			WHERE user_id = $1
+			ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workouts := []models.WorkoutSession{}
+	for rows.Next() {
+		var w models.WorkoutSession
+		if err := rows.Scan(&w.ID, &w.UserID, &w.GymID, &w.CardioMinutes, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		workouts = append(workouts, w)
+	}
+	return workouts, rows.Err()
+}
+
+func (s *WorkoutStore) Get(ctx context.Context, userID, id int) (models.WorkoutSessionWithExercises, error) {
+	defer metrics.ObserveQuery("workouts", "Get")()
+	var w models.WorkoutSessionWithExercises
+	err := s.db.QueryRowContext(ctx,
+		"SELECT id, user_id, gym_id, cardio_minutes, created_at FROM workout_sessions WHERE id = $1 AND user_id = $2",
+		id, userID,
+	).Scan(&w.ID, &w.UserID, &w.GymID, &w.CardioMinutes, &w.CreatedAt)
+	if err != nil {
+		return w, notFound(err)
+	}
+
+	w.Exercises, err = s.exercises(ctx, workoutExerciseQuery+" WHERE we.workout_session_id = $1 ORDER BY we.id", id)
+	return w, err
+}
+
+func (s *WorkoutStore) DailySummary(ctx context.Context, userID int, tz, from, to string) ([]models.WorkoutDay, error) {
+	defer metrics.ObserveQuery("workouts", "DailySummary")()
+	rows, err := s.db.QueryContext(ctx, `
+			SELECT (ws.created_at AT TIME ZONE 'UTC' AT TIME ZONE $2)::date AS day,
+					COUNT(DISTINCT ws.id),
+					COUNT(we.id),
+					COALESCE(SUM(we.sets), 0),
+					COALESCE(SUM(we.weight * we.reps * we.sets), 0)
+			FROM workout_sessions ws
+			LEFT JOIN workout_exercises we ON we.workout_session_id = ws.id
+			WHERE ws.user_id = $1
+			AND (ws.created_at AT TIME ZONE 'UTC' AT TIME ZONE $2)::date BETWEEN $3 AND $4
+			GROUP BY day
+			ORDER BY day DESC`, userID, tz, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []models.WorkoutDay{}
+	for rows.Next() {
+		var day models.WorkoutDay
+		var date time.Time
+		if err := rows.Scan(&date, &day.Sessions, &day.ExerciseEntries, &day.Sets, &day.Volume); err != nil {
+			return nil, err
+		}
+		day.Date = date.Format("2006-01-02")
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+func (s *WorkoutStore) History(ctx context.Context, userID, exerciseID, equipmentID, limit int) ([]models.WorkoutExerciseWithDetails, error) {
+	defer metrics.ObserveQuery("workouts", "History")()
+	return s.exercises(ctx, workoutExerciseQuery+`
+			WHERE we.exercise_id = $1 AND we.gym_equipment_id = $2 AND ws.user_id = $3
+			ORDER BY we.created_at DESC
+			LIMIT $4`, exerciseID, equipmentID, userID, limit)
+}
+
+func (s *WorkoutStore) exercises(ctx context.Context, query string, args ...any) ([]models.WorkoutExerciseWithDetails, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exercises := []models.WorkoutExerciseWithDetails{}
+	for rows.Next() {
+		var e models.WorkoutExerciseWithDetails
+		if err := rows.Scan(
+			&e.ID, &e.WorkoutSessionID, &e.ExerciseID, &e.ExerciseName, &e.GymEquipmentID,
+			&e.EquipmentName, &e.Weight, &e.Reps, &e.Sets, &e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, e)
+	}
+	return exercises, rows.Err()
+}
+
+func (s *WorkoutStore) Create(ctx context.Context, userID int, input models.WorkoutSessionWithExercisesInput) (models.WorkoutSessionWithExercises, error) {
+	defer metrics.ObserveQuery("workouts", "Create")()
+	w := models.WorkoutSessionWithExercises{
+		WorkoutSession: models.WorkoutSession{UserID: userID, GymID: input.GymID, CardioMinutes: input.CardioMinutes},
+		Exercises:      make([]models.WorkoutExerciseWithDetails, 0, len(input.Exercises)),
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return w, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO workout_sessions (user_id, gym_id, cardio_minutes) VALUES ($1, $2, $3) RETURNING id, created_at",
+		userID, input.GymID, input.CardioMinutes,
+	).Scan(&w.ID, &w.CreatedAt)
+	if err != nil {
+		return w, err
+	}
+
+	for _, in := range input.Exercises {
+		e, err := insertWorkoutExercise(ctx, tx, w.ID, in)
+		if err != nil {
+			return w, err
+		}
+		details := models.WorkoutExerciseWithDetails{
+			ID:               e.ID,
+			WorkoutSessionID: w.ID,
+			ExerciseID:       e.ExerciseID,
+			ExerciseName:     "Unknown",
+			GymEquipmentID:   e.GymEquipmentID,
+			EquipmentName:    "Unknown",
+			Weight:           e.Weight,
+			Reps:             e.Reps,
+			Sets:             e.Sets,
+			PersonalRecord:   e.PersonalRecord,
+			CreatedAt:        e.CreatedAt,
+		}
+		err = tx.QueryRowContext(ctx, `
+				SELECT e.name, et.name
+				FROM exercises e
+				JOIN gym_equipment ge ON ge.id = $1
+				JOIN equipment_types et ON et.id = ge.equipment_type_id
+				WHERE e.id = $2`, in.GymEquipmentID, in.ExerciseID,
+		).Scan(&details.ExerciseName, &details.EquipmentName)
+		if err != nil && err != sql.ErrNoRows {
+			return w, err
+		}
+		w.Exercises = append(w.Exercises, details)
+	}
+
+	return w, tx.Commit()
+}
+
+func (s *WorkoutStore) AddExercise(ctx context.Context, sessionID int, input models.WorkoutExerciseInput) (models.WorkoutExercise, error) {
+	defer metrics.ObserveQuery("workouts", "AddExercise")()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.WorkoutExercise{}, err
+	}
+	defer tx.Rollback()
+
+	var found bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM workout_sessions WHERE id = $1)", sessionID).Scan(&found)
+	if err != nil {
+		return models.WorkoutExercise{}, err
+	}
+	if !found {
+		return models.WorkoutExercise{}, store.ErrNotFound
+	}
+
+	e, err := insertWorkoutExercise(ctx, tx, sessionID, input)
+	if err != nil {
+		return e, err
+	}
+	return e, tx.Commit()
+}
+
+func insertWorkoutExercise(ctx context.Context, tx *sql.Tx, sessionID int, input models.WorkoutExerciseInput) (models.WorkoutExercise, error) {
+	e := models.WorkoutExercise{
+		WorkoutSessionID: sessionID,
+		ExerciseID:       input.ExerciseID,
+		GymEquipmentID:   input.GymEquipmentID,
+		Weight:           input.Weight,
+		Reps:             input.Reps,
+		Sets:             input.Sets,
+	}
+	// The best weight is read before the insert, so a set only counts as a
+	// personal record when it beats an earlier one.
+	err := tx.QueryRowContext(ctx, `
+			WITH best AS (
+				SELECT MAX(we.weight) AS weight
+				FROM workout_exercises we
+				JOIN workout_sessions ws ON ws.id = we.workout_session_id
+				WHERE we.exercise_id = $2
+				  AND ws.user_id = (SELECT user_id FROM workout_sessions WHERE id = $1)
+			)
+			INSERT INTO workout_exercises (workout_session_id, exercise_id, gym_equipment_id, weight, reps, sets)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at, COALESCE(weight > (SELECT weight FROM best), false)`,
+		sessionID, input.ExerciseID, input.GymEquipmentID, input.Weight, input.Reps, input.Sets,
+	).Scan(&e.ID, &e.CreatedAt, &e.PersonalRecord)
+	return e, err
+}
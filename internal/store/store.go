@@ -0,0 +1,256 @@
+// Package store defines the repositories the gym tracking handlers read
+// and write through, so that queries live in one place and handlers can be
+// exercised against in-memory fakes. The Postgres implementation is in
+// store/postgres.
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/analytics"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/pantry"
+)
+
+// ErrNotFound is returned when the row a method addresses does not exist.
+var ErrNotFound = errors.New("not found")
+
+type GymStore interface {
+	List(ctx context.Context) ([]models.Gym, error)
+	ListByUser(ctx context.Context, userID int) ([]models.Gym, error)
+	Get(ctx context.Context, id int) (models.Gym, error)
+	// Create inserts the gym and returns it with its ID and creation time.
+	Create(ctx context.Context, gym models.Gym) (models.Gym, error)
+	Update(ctx context.Context, id int, gym models.Gym) (models.Gym, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type EquipmentStore interface {
+	ListByGym(ctx context.Context, gymID int) ([]models.GymEquipmentWithDetails, error)
+	Get(ctx context.Context, id int) (models.GymEquipmentWithDetails, error)
+	Exists(ctx context.Context, id int) (bool, error)
+	Create(ctx context.Context, gymID int, input models.GymEquipmentInput) (models.GymEquipment, error)
+	Update(ctx context.Context, id int, input models.GymEquipmentInput) (models.GymEquipmentWithDetails, error)
+	// InUse reports whether any workout session uses the equipment.
+	InUse(ctx context.Context, id int) (bool, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type EquipmentTypeStore interface {
+	List(ctx context.Context) ([]models.EquipmentType, error)
+	Get(ctx context.Context, id int) (models.EquipmentType, error)
+	Exists(ctx context.Context, id int) (bool, error)
+	// NameTaken reports whether another equipment type than exceptID
+	// already has the name; pass 0 when creating.
+	NameTaken(ctx context.Context, name string, exceptID int) (bool, error)
+	Create(ctx context.Context, name string) (models.EquipmentType, error)
+	Update(ctx context.Context, id int, name string) (models.EquipmentType, error)
+	// InUse reports whether any gym equipment is of the type.
+	InUse(ctx context.Context, id int) (bool, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type ExerciseStore interface {
+	List(ctx context.Context) ([]models.Exercise, error)
+	Exists(ctx context.Context, id int) (bool, error)
+	// NameTaken reports whether another exercise than exceptID already has
+	// the name; pass 0 when creating.
+	NameTaken(ctx context.Context, name string, exceptID int) (bool, error)
+	Create(ctx context.Context, name string) (models.Exercise, error)
+	Update(ctx context.Context, id int, name string) (models.Exercise, error)
+	// InUse reports whether any workout records the exercise.
+	InUse(ctx context.Context, id int) (bool, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type WorkoutStore interface {
+	// ListByUser returns the user's sessions, newest first.
+	ListByUser(ctx context.Context, userID int) ([]models.WorkoutSession, error)
+	// Get returns one of the user's sessions with its exercises.
+	Get(ctx context.Context, userID, id int) (models.WorkoutSessionWithExercises, error)
+	// DailySummary totals the user's sessions per calendar day in tz
+	// between from and to (YYYY-MM-DD), newest day first.
+	DailySummary(ctx context.Context, userID int, tz, from, to string) ([]models.WorkoutDay, error)
+	// History returns the user's most recent sets of an exercise on a piece
+	// of equipment, newest first and at most limit of them.
+	History(ctx context.Context, userID, exerciseID, equipmentID, limit int) ([]models.WorkoutExerciseWithDetails, error)
+	// Create inserts a session and its exercises in one transaction.
+	Create(ctx context.Context, userID int, input models.WorkoutSessionWithExercisesInput) (models.WorkoutSessionWithExercises, error)
+	// AddExercise records an exercise against an existing session,
+	// returning ErrNotFound when there is no such session.
+	AddExercise(ctx context.Context, sessionID int, input models.WorkoutExerciseInput) (models.WorkoutExercise, error)
+}
+
+type UserStore interface {
+	List(ctx context.Context) ([]models.User, error)
+	Get(ctx context.Context, id int) (models.User, error)
+	Exists(ctx context.Context, id int) (bool, error)
+	// FindByEmail looks a user up by email, ignoring case.
+	FindByEmail(ctx context.Context, email string) (models.User, error)
+	// Create inserts a user with an already hashed password.
+	Create(ctx context.Context, email, passwordHash string) (models.User, error)
+	// EmailTaken reports, ignoring case, whether a user other than exceptID
+	// has the email.
+	EmailTaken(ctx context.Context, email string, exceptID int) (bool, error)
+	UpdateEmail(ctx context.Context, id int, email string) (models.User, error)
+	PasswordHash(ctx context.Context, id int) (string, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	// SetDisabled disables the account, or enables it again.
+	SetDisabled(ctx context.Context, id int, disabled bool) error
+	// ScheduleDeletion marks the account for deletion after grace. A
+	// repeated request keeps the original schedule.
+	ScheduleDeletion(ctx context.Context, id int, grace time.Duration) (models.AccountDeletionStatus, error)
+	DeletionStatus(ctx context.Context, id int) (models.AccountDeletionStatus, error)
+	// CancelDeletion clears a pending deletion, returning ErrNotFound when
+	// none is pending.
+	CancelDeletion(ctx context.Context, id int) error
+	// Timezone returns the IANA time zone from the user's profile.
+	Timezone(ctx context.Context, id int) (string, error)
+}
+
+type ProfileStore interface {
+	// Get returns the user's profile, with the defaults filled in when none
+	// has been saved, or ErrNotFound when there is no such user.
+	Get(ctx context.Context, userID int) (models.UserProfile, error)
+	// Save creates or replaces the user's profile.
+	Save(ctx context.Context, userID int, input models.UserProfileInput) (models.UserProfile, error)
+}
+
+// The pantry, meal, recipe, meal plan and shopping list stores are scoped
+// to a user: rows belonging to other users are reported as ErrNotFound.
+// Methods that record ingredients convert them into their pantry items'
+// units and fail with pantry.ErrItemNotFound or a *pantry.UnitError when
+// that is not possible; methods that deduct stock fail with a
+// *pantry.InsufficientStockError, leaving the pantry untouched.
+
+type PantryStore interface {
+	// List returns the user's items by name, only those below their
+	// threshold when lowStock is set.
+	List(ctx context.Context, userID int, lowStock bool) ([]models.PantryItem, error)
+	Get(ctx context.Context, userID, id int) (models.PantryItem, error)
+	// NameTaken reports whether another of the user's items than exceptID
+	// already has the name; pass 0 when creating.
+	NameTaken(ctx context.Context, userID int, name string, exceptID int) (bool, error)
+	// Create inserts the item, recording its quantity as a batch when it
+	// has an expiry date.
+	Create(ctx context.Context, userID int, input models.PantryItemInput) (models.PantryItem, error)
+	// Update replaces the item, dating any stock it gains and converting
+	// the amounts recorded against it when its unit changes. A unit that
+	// does not convert gives pantry.ErrUnitInUse while the item is used.
+	Update(ctx context.Context, userID, id int, input models.PantryItemInput) (models.PantryItem, error)
+	// Restock adds to the item's quantity in a single statement.
+	Restock(ctx context.Context, userID, id int, input models.PantryRestockInput) (models.PantryItem, error)
+	// InUse reports whether logged meals, recipes or meal plans use the
+	// item.
+	InUse(ctx context.Context, id int) (bool, error)
+	Delete(ctx context.Context, userID, id int) error
+	// Expiring returns the batches expiring within days of today
+	// (YYYY-MM-DD), soonest first.
+	Expiring(ctx context.Context, userID int, today string, days int) ([]models.ExpiringBatch, error)
+	Batches(ctx context.Context, userID, id int) (models.PantryItemBatches, error)
+	DiscardBatch(ctx context.Context, userID, id, batchID int) error
+	Forecast(ctx context.Context, userID int, outlook pantry.Outlook, leadDays float64) ([]models.PantryForecast, error)
+	// TuneThresholds applies the forecast's suggested thresholds and
+	// returns the items that changed.
+	TuneThresholds(ctx context.Context, userID int, outlook pantry.Outlook, leadDays float64) ([]models.PantryItem, error)
+}
+
+type MealStore interface {
+	// List returns the meals eaten in [from, until), oldest first.
+	List(ctx context.Context, userID int, from, until time.Time) ([]models.Meal, error)
+	Get(ctx context.Context, userID, id int) (models.Meal, error)
+	// Create logs a meal and deducts its ingredients from the pantry.
+	Create(ctx context.Context, userID int, eatenAt time.Time, ingredients []models.MealIngredientInput) (models.Meal, error)
+	// Update returns the stock used by the meal to the pantry and deducts
+	// the new ingredients instead. A nil eatenAt keeps the original time.
+	Update(ctx context.Context, userID, id int, eatenAt *time.Time, ingredients []models.MealIngredientInput) (models.Meal, error)
+	// Delete removes the meal and returns its stock to the pantry.
+	Delete(ctx context.Context, userID, id int) error
+}
+
+type RecipeStore interface {
+	// List returns the user's recipes by name.
+	List(ctx context.Context, userID int) ([]models.Recipe, error)
+	Get(ctx context.Context, userID, id int) (models.Recipe, error)
+	// NameTaken reports whether another of the user's recipes than
+	// exceptID already has the name; pass 0 when creating.
+	NameTaken(ctx context.Context, userID int, name string, exceptID int) (bool, error)
+	Create(ctx context.Context, userID int, input models.RecipeInput) (models.Recipe, error)
+	Update(ctx context.Context, userID, id int, input models.RecipeInput) (models.Recipe, error)
+	Delete(ctx context.Context, userID, id int) error
+	// Log logs servings of the recipe as a meal, scaling each ingredient
+	// from the recipe's servings and deducting it from the pantry.
+	Log(ctx context.Context, userID, id int, servings float64, eatenAt time.Time) (models.Meal, error)
+}
+
+type MealPlanStore interface {
+	// List returns the entries planned from from to to (YYYY-MM-DD), by
+	// day.
+	List(ctx context.Context, userID int, from, to string) ([]models.MealPlanEntry, error)
+	Get(ctx context.Context, userID, id int) (models.MealPlanEntry, error)
+	// Create plans an entry. A recipe's servings must already be filled
+	// in.
+	Create(ctx context.Context, userID int, input models.MealPlanEntryInput) (models.MealPlanEntry, error)
+	Update(ctx context.Context, userID, id int, input models.MealPlanEntryInput) (models.MealPlanEntry, error)
+	Delete(ctx context.Context, userID, id int) error
+	// Requirements compares what the plan uses over the window with
+	// current stock.
+	Requirements(ctx context.Context, userID int, plan pantry.PlanWindow) ([]models.MealPlanRequirement, error)
+}
+
+type ShoppingListStore interface {
+	// List builds the shopping list. A nil plan or outlook leaves the meal
+	// plan or the projection out.
+	List(ctx context.Context, userID int, plan *pantry.PlanWindow, outlook *pantry.Outlook) ([]models.ShoppingListItem, error)
+	// Add records an item added by hand. Its name and unit must already
+	// be resolved.
+	Add(ctx context.Context, userID int, input models.ShoppingListItemInput) (models.ShoppingListItem, error)
+	Check(ctx context.Context, userID, id int, checked bool) error
+	// CheckPantryItem sets the checked state of the row for one of the
+	// user's pantry items.
+	CheckPantryItem(ctx context.Context, userID, pantryItemID int, checked bool) error
+	Delete(ctx context.Context, userID, id int) error
+	// Purchase restocks the checked rows and clears them, returning the
+	// restocked pantry items and the number of hand-added rows removed.
+	Purchase(ctx context.Context, userID int, plan *pantry.PlanWindow, outlook *pantry.Outlook) (models.ShoppingListPurchase, error)
+}
+
+type BodyMeasurementStore interface {
+	// List returns the measurements taken in [from, until), oldest first.
+	List(ctx context.Context, userID int, from, until time.Time) ([]models.BodyMeasurement, error)
+	Create(ctx context.Context, userID int, input models.BodyMeasurementInput) (models.BodyMeasurement, error)
+	Update(ctx context.Context, userID, id int, input models.BodyMeasurementInput) (models.BodyMeasurement, error)
+	Delete(ctx context.Context, userID, id int) error
+	// Bodyweights returns every bodyweight recorded before until, oldest
+	// first.
+	Bodyweights(ctx context.Context, userID int, until time.Time) ([]analytics.Sample, error)
+	// TrainingWeeks totals the sessions logged in [from, until) per
+	// Monday-based week in tz, keyed by the week's first day (YYYY-MM-DD).
+	// The best estimated 1RM is only filled in for a given exerciseID;
+	// pass 0 to leave it out.
+	TrainingWeeks(ctx context.Context, userID, exerciseID int, tz string, from, until time.Time) (map[string]models.BodyweightWeek, error)
+}
+
+type NutritionStore interface {
+	// Days totals the meals eaten on each day in tz from from to to
+	// (YYYY-MM-DD), keyed by day. Days without meals are absent.
+	Days(ctx context.Context, userID int, tz, from, to string) (map[string]models.NutritionDay, error)
+	// Targets returns the user's targets, all unset when none have been
+	// saved.
+	Targets(ctx context.Context, userID int) (models.NutritionTargets, error)
+	SaveTargets(ctx context.Context, userID int, input models.NutritionTargetsInput) (models.NutritionTargets, error)
+	// Training totals the working sets and cardio minutes logged since
+	// since.
+	Training(ctx context.Context, userID int, since time.Time) (sets, cardioMinutes float64, err error)
+}
+
+type FoodStore interface {
+	// Search runs a tsquery against the catalogue, best matches first.
+	Search(ctx context.Context, query string, limit int) ([]models.Food, error)
+	Get(ctx context.Context, id int) (models.Food, error)
+	// FindByBarcode returns the most recently imported food with the
+	// normalised barcode.
+	FindByBarcode(ctx context.Context, code string) (models.Food, error)
+}
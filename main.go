@@ -1,15 +1,23 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/Ross1116/gym-tracker-backend/api/routes"
+	"github.com/Ross1116/gym-tracker-backend/internal/config"
+	"github.com/Ross1116/gym-tracker-backend/internal/logging"
+	"github.com/Ross1116/gym-tracker-backend/internal/tracing"
 	_ "github.com/lib/pq"
+	"github.com/urfave/cli/v2"
 )
 
-var db *sql.DB
-
 // @title Gym Tracker API
 // @version 1.0
 // @description API for tracking gym workouts and exercises
@@ -26,19 +34,85 @@ var db *sql.DB
 // @BasePath /api/
 // @schemes http
 func main() {
-	var err error
-	connStr := "host=localhost port=5432 user=admin password=admin dbname=mydb sslmode=disable"
-	db, err = sql.Open("postgres", connStr)
-	if err != nil {
+	app := &cli.App{
+		Name:  "gym-tracker-backend",
+		Usage: "gym tracking and nutrition API server and admin tools",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "YAML or TOML config file; settings can also come from GYM_* environment variables",
+				EnvVars: []string{config.FileEnv},
+			},
+		},
+		Commands: []*cli.Command{
+			serveCommand,
+			migrateCommand,
+			seedCommand,
+			userCommand,
+			exportCommand,
+			importCommand,
+			foodsCommand,
+			purgeUsersCommand,
+			doctorCommand,
+		},
+		// Running without a command starts the server, as before the
+		// commands existed.
+		Action: serveCommand.Action,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.RunContext(ctx, os.Args); err != nil {
 		log.Fatal(err)
 	}
+}
 
-	defer db.Close()
+// withDB wraps a command that needs the configuration and a database
+// connection, closing the connection once the command returns.
+func withDB(action func(c *cli.Context, cfg config.Config, db *sql.DB) error) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		cfg, err := config.Load(c.String("config"))
+		if err != nil {
+			return err
+		}
+		slog.SetDefault(logging.New(cfg.Log.Level))
 
-	err = db.Ping()
+		shutdown, err := tracing.Setup(c.Context, cfg.Tracing)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			// Flush spans even when the command was interrupted.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdown(ctx); err != nil {
+				slog.Error("flushing traces failed", "error", err)
+			}
+		}()
+
+		db, err := openDB(c.Context, cfg.Database)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		return action(c, cfg, db)
+	}
+}
+
+func openDB(ctx context.Context, cfg config.Database) (*sql.DB, error) {
+	db, err := tracing.OpenDB(cfg.DSN)
 	if err != nil {
-		log.Fatal("Could not connect to the db", err)
+		return nil, err
 	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime))
 
-	routes.SetupRoutes(db)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not connect to the database: %w", err)
+	}
+	return db, nil
 }
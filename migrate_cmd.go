@@ -0,0 +1,132 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	dbfiles "github.com/Ross1116/gym-tracker-backend/db"
+	"github.com/Ross1116/gym-tracker-backend/internal/config"
+	"github.com/Ross1116/gym-tracker-backend/internal/migrate"
+	"github.com/urfave/cli/v2"
+)
+
+// migrateCommand applies, reverts or lists the migrations built into the
+// binary, checks that they produce db/schema.sql, or starts a new one.
+var migrateCommand = &cli.Command{
+	Name:  "migrate",
+	Usage: "manage the database schema",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "up",
+			Usage:  "apply every pending migration",
+			Action: withMigrator(migrateUp),
+		},
+		{
+			Name:  "down",
+			Usage: "revert the most recently applied migrations",
+			Flags: []cli.Flag{
+				&cli.IntFlag{Name: "steps", Value: 1, Usage: "number of migrations to revert"},
+			},
+			Action: withMigrator(migrateDown),
+		},
+		{
+			Name:   "status",
+			Usage:  "list the migrations and whether they have been applied",
+			Action: withMigrator(migrateStatus),
+		},
+		{
+			Name:  "verify",
+			Usage: "check that db/schema.sql matches the migrations, using scratch schemas that are rolled back",
+			Action: withMigrator(func(c *cli.Context, db *sql.DB, migrations []migrate.Migration) error {
+				diffs, err := migrate.Verify(c.Context, db, migrations, dbfiles.Schema)
+				if err != nil {
+					return err
+				}
+				for _, d := range diffs {
+					fmt.Println(d)
+				}
+				if len(diffs) > 0 {
+					return fmt.Errorf("db/schema.sql and the migrations differ in %d place(s)", len(diffs))
+				}
+				fmt.Println("db/schema.sql matches the migrations")
+				return nil
+			}),
+		},
+		{
+			Name:      "create",
+			Usage:     "write empty up and down files for a new migration",
+			ArgsUsage: "<description>",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "dir", Value: "db/migrations", Usage: "directory holding the migrations"},
+			},
+			// Needs no database connection.
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 1 {
+					return errors.New("usage: migrate create <description>")
+				}
+				up, down, err := migrate.Create(c.String("dir"), c.Args().First())
+				if err != nil {
+					return err
+				}
+				fmt.Printf("created %s\ncreated %s\n", up, down)
+				return nil
+			},
+		},
+	},
+}
+
+func withMigrator(action func(c *cli.Context, db *sql.DB, migrations []migrate.Migration) error) cli.ActionFunc {
+	return withDB(func(c *cli.Context, cfg config.Config, db *sql.DB) error {
+		migrations, err := migrate.Load(dbfiles.Migrations)
+		if err != nil {
+			return err
+		}
+		return action(c, db, migrations)
+	})
+}
+
+func migrateUp(c *cli.Context, db *sql.DB, migrations []migrate.Migration) error {
+	applied, err := migrate.New(db, migrations).Up(c.Context)
+	for _, mig := range applied {
+		fmt.Printf("applied %04d_%s\n", mig.Version, mig.Name)
+	}
+	if err == nil && len(applied) == 0 {
+		fmt.Println("no pending migrations")
+	}
+	return err
+}
+
+func migrateDown(c *cli.Context, db *sql.DB, migrations []migrate.Migration) error {
+	steps := c.Int("steps")
+	if steps < 1 {
+		return errors.New("--steps must be at least 1")
+	}
+	reverted, err := migrate.New(db, migrations).Down(c.Context, steps)
+	for _, mig := range reverted {
+		fmt.Printf("reverted %04d_%s\n", mig.Version, mig.Name)
+	}
+	return err
+}
+
+func migrateStatus(c *cli.Context, db *sql.DB, migrations []migrate.Migration) error {
+	statuses, err := migrate.New(db, migrations).Status(c.Context)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			if s.Up == "" {
+				applied += " (files missing)"
+			}
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return w.Flush()
+}
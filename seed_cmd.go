@@ -0,0 +1,21 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/config"
+	"github.com/Ross1116/gym-tracker-backend/internal/seed"
+	"github.com/urfave/cli/v2"
+)
+
+var seedCommand = &cli.Command{
+	Name:  "seed",
+	Usage: "load the default exercises and equipment types, skipping any already present",
+	Action: withDB(func(c *cli.Context, cfg config.Config, db *sql.DB) error {
+		report, err := seed.Run(c.Context, db)
+		if err != nil {
+			return err
+		}
+		return printJSON(report)
+	}),
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Ross1116/gym-tracker-backend/api/routes"
+	"github.com/Ross1116/gym-tracker-backend/internal/account"
+	"github.com/Ross1116/gym-tracker-backend/internal/config"
+	"github.com/Ross1116/gym-tracker-backend/internal/metrics"
+	"github.com/urfave/cli/v2"
+)
+
+var serveCommand = &cli.Command{
+	Name:  "serve",
+	Usage: "run the API server and the hourly purge of deleted accounts",
+	Action: withDB(func(c *cli.Context, cfg config.Config, db *sql.DB) error {
+		metrics.RegisterDB(db)
+		go account.RunPurger(c.Context, db, time.Hour)
+		return serve(c.Context, db, cfg)
+	}),
+}
+
+// serve runs the API until ctx is cancelled, then stops accepting
+// connections and waits for in-flight requests to finish.
+func serve(ctx context.Context, db *sql.DB, cfg config.Config) error {
+	srv := &http.Server{
+		Addr:         cfg.HTTP.Addr,
+		Handler:      routes.SetupRoutes(db, cfg),
+		ReadTimeout:  time.Duration(cfg.HTTP.ReadTimeout),
+		WriteTimeout: time.Duration(cfg.HTTP.WriteTimeout),
+		IdleTimeout:  time.Duration(cfg.HTTP.IdleTimeout),
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "addr", cfg.HTTP.Addr)
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.HTTP.ShutdownTimeout))
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
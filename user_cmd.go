@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/Ross1116/gym-tracker-backend/internal/account"
+	"github.com/Ross1116/gym-tracker-backend/internal/config"
+	"github.com/Ross1116/gym-tracker-backend/internal/models"
+	"github.com/Ross1116/gym-tracker-backend/internal/service"
+	"github.com/Ross1116/gym-tracker-backend/internal/store/postgres"
+	"github.com/urfave/cli/v2"
+)
+
+// userFlags returns the flags picking the user a command acts on, followed
+// by extra. Each command needs its own slice, as cli appends to it.
+func userFlags(extra ...cli.Flag) []cli.Flag {
+	return append([]cli.Flag{
+		&cli.IntFlag{Name: "id", Usage: "ID of the user"},
+		&cli.StringFlag{Name: "email", Usage: "email of the user, instead of --id"},
+	}, extra...)
+}
+
+func passwordFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "password",
+		Usage: "new password; read from stdin when omitted, to keep it out of the shell history",
+	}
+}
+
+var userCommand = &cli.Command{
+	Name:  "user",
+	Usage: "administer user accounts",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "create",
+			Usage: "register a user",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "email", Usage: "email of the new user", Required: true},
+				passwordFlag(),
+			},
+			Action: withUsers(func(c *cli.Context, users *service.UserService) error {
+				password, err := readPassword(c)
+				if err != nil {
+					return err
+				}
+				u, err := users.Create(c.Context, c.String("email"), password)
+				if err != nil {
+					return err
+				}
+				return printJSON(u)
+			}),
+		},
+		{
+			Name:  "reset-password",
+			Usage: "set a user's password without knowing the current one",
+			Flags: userFlags(passwordFlag()),
+			Action: withUsers(func(c *cli.Context, users *service.UserService) error {
+				u, err := findUser(c, users)
+				if err != nil {
+					return err
+				}
+				password, err := readPassword(c)
+				if err != nil {
+					return err
+				}
+				if err := users.ResetPassword(c.Context, u.ID, password); err != nil {
+					return err
+				}
+				fmt.Printf("password of user %d (%s) reset\n", u.ID, u.Email)
+				return nil
+			}),
+		},
+		{
+			Name:   "disable",
+			Usage:  "disable an account so its password is no longer accepted",
+			Flags:  userFlags(),
+			Action: withUsers(setDisabled(true)),
+		},
+		{
+			Name:   "enable",
+			Usage:  "enable a disabled account again",
+			Flags:  userFlags(),
+			Action: withUsers(setDisabled(false)),
+		},
+	},
+}
+
+var purgeUsersCommand = &cli.Command{
+	Name:  "purge-users",
+	Usage: "hard-delete accounts whose deletion grace period has elapsed",
+	Action: withDB(func(c *cli.Context, cfg config.Config, db *sql.DB) error {
+		n, err := account.PurgeDeletedUsers(c.Context, db)
+		if err != nil {
+			return err
+		}
+		slog.Info("purged deleted accounts", "count", n)
+		return nil
+	}),
+}
+
+func withUsers(action func(c *cli.Context, users *service.UserService) error) cli.ActionFunc {
+	return withDB(func(c *cli.Context, cfg config.Config, db *sql.DB) error {
+		return action(c, service.NewUserService(postgres.NewUserStore(db)))
+	})
+}
+
+func setDisabled(disabled bool) func(c *cli.Context, users *service.UserService) error {
+	return func(c *cli.Context, users *service.UserService) error {
+		u, err := findUser(c, users)
+		if err != nil {
+			return err
+		}
+		if err := users.SetDisabled(c.Context, u.ID, disabled); err != nil {
+			return err
+		}
+		state := "enabled"
+		if disabled {
+			state = "disabled"
+		}
+		fmt.Printf("user %d (%s) %s\n", u.ID, u.Email, state)
+		return nil
+	}
+}
+
+// findUser looks up the user given by --id or --email.
+func findUser(c *cli.Context, users *service.UserService) (models.User, error) {
+	id, email := c.Int("id"), c.String("email")
+	switch {
+	case id != 0 && email != "":
+		return models.User{}, errors.New("pass either --id or --email, not both")
+	case id != 0:
+		return users.Get(c.Context, id)
+	case email != "":
+		return users.FindByEmail(c.Context, email)
+	}
+	return models.User{}, errors.New("--id or --email is required")
+}
+
+// readPassword returns --password, or else the first line of stdin.
+func readPassword(c *cli.Context) (string, error) {
+	if c.IsSet("password") {
+		return c.String("password"), nil
+	}
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}